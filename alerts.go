@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/itay747/go-stonfi/src/alerts"
+	"github.com/itay747/go-stonfi/src/client"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "alerts",
+		summary: "Evaluate alert rules from a YAML file and send what fires to notifiers",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			once := fs.Bool("once", false, "Evaluate the rules once and exit")
+			return func(ctx context.Context, args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("usage: %s alerts [flags] <rules.yaml>", programName())
+				}
+				config, err := alerts.LoadFile(args[0])
+				if err != nil {
+					return err
+				}
+				notifiers, err := config.Notifiers.Build(os.Stdout)
+				if err != nil {
+					return err
+				}
+				engine, err := alerts.New(client.NewStonfiClient(), config.Rules, notifiers...)
+				if err != nil {
+					return err
+				}
+				report := func(fired []alerts.Alert, err error) {
+					if err != nil {
+						errorMessage(fmt.Sprintf("Error: %v", err))
+					}
+				}
+
+				if *once {
+					fired, err := engine.Evaluate(ctx)
+					if err != nil {
+						return err
+					}
+					successMessage(fmt.Sprintf("%d of %d rules fired", len(fired), len(config.Rules)))
+					return nil
+				}
+				infoMessage(fmt.Sprintf("Evaluating %d rules every %s", len(config.Rules), config.Interval))
+				engine.Run(ctx, config.Interval, report)
+				return nil
+			}
+		},
+	})
+}
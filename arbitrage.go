@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/itay747/go-stonfi/src/arbitrage"
+	"github.com/itay747/go-stonfi/src/client"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "arbitrage",
+		summary: "Report profitable swap cycles and mispriced pools (no trading)",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			maxHops := fs.Int("max-hops", 3, "Longest cycle to search")
+			start := fs.String("start", "", "Comma-separated assets cycles must start from (default any)")
+			minProfit := fs.Float64("min-profit-usd", 1, "Smallest expected profit to report, in USD")
+			minSpread := fs.Float64("min-spread", 0.01, "Smallest price spread between pools of a pair to report")
+			deprecated := fs.Bool("include-deprecated", false, "Also search deprecated pools")
+			return func(ctx context.Context, args []string) error {
+				opts := arbitrage.Options{MaxHops: *maxHops, MinProfitUsd: *minProfit, IncludeDeprecated: *deprecated}
+				if *start != "" {
+					opts.Start = strings.Split(*start, ",")
+				}
+				c := client.NewStonfiClient()
+				infoMessage("Fetching pools and assets...")
+				pools, err := c.GetPools(ctx)
+				if err != nil {
+					return fmt.Errorf("fetching pools: %w", err)
+				}
+				assets, err := c.GetAssets(ctx)
+				if err != nil {
+					return fmt.Errorf("fetching assets: %w", err)
+				}
+
+				opportunities := arbitrage.Find(pools.PoolList, assets.AssetList, opts)
+				successMessage(fmt.Sprintf("Found %d profitable cycles", len(opportunities)))
+				printJSON("Opportunities", opportunities)
+				discrepancies := arbitrage.Discrepancies(pools.PoolList, *minSpread, *deprecated)
+				successMessage(fmt.Sprintf("Found %d pairs with pools %.2f%% or more apart", len(discrepancies), *minSpread*100))
+				printJSON("Discrepancies", discrepancies)
+				return nil
+			}
+		},
+	})
+}
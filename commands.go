@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// command is a `stonfi <name>` subcommand. setup registers the command's flags
+// on fs and returns the function that runs it, so the same definition serves
+// both execution and shell completion.
+type command struct {
+	name    string
+	summary string
+	args    []string // fixed choices for the first positional argument, if any
+	hidden  bool
+	setup   func(fs *flag.FlagSet) func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]*command{}
+
+func registerCommand(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+// visibleCommands returns the non-hidden subcommands sorted by name.
+func visibleCommands() []*command {
+	var cmds []*command
+	for _, cmd := range commands {
+		if !cmd.hidden {
+			cmds = append(cmds, cmd)
+		}
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].name < cmds[j].name })
+	return cmds
+}
+
+// programName is the name the binary was invoked as.
+func programName() string {
+	return filepath.Base(os.Args[0])
+}
+
+func runCommand(ctx context.Context, cmd *command, args []string) {
+	fs := flag.NewFlagSet(programName()+" "+cmd.name, flag.ExitOnError)
+	run := cmd.setup(fs)
+	fs.Parse(args)
+	if err := run(ctx, fs.Args()); err != nil {
+		errorMessage(fmt.Sprintf("Error: %v", err))
+		os.Exit(1)
+	}
+}
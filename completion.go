@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+)
+
+// actions are the values accepted by the top-level -action flag.
+var actions = []string{
+	"assets", "asset", "wallet-assets", "swap-simulate", "pools", "pool",
+	"wallet-pools", "farms", "farm", "wallet-farms", "farm-by-pool",
+}
+
+// flagCompletions maps flag names to the kind of value they complete to.
+var flagCompletions = map[string]string{
+	"action":        "actions",
+	"asset-address": "assets",
+	"offer-address": "assets",
+	"ask-address":   "assets",
+	"pool-address":  "pools",
+}
+
+func init() {
+	registerCommand(&command{
+		name:    "completion",
+		summary: "Print a shell completion script, or refresh the completion cache",
+		args:    []string{"bash", "zsh", "fish", "refresh"},
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			return func(ctx context.Context, args []string) error {
+				if len(args) != 1 {
+					return errors.New("usage: completion bash|zsh|fish|refresh")
+				}
+				if args[0] == "refresh" {
+					return refreshCompletionCache(ctx, client.NewStonfiClient())
+				}
+				return writeCompletionScript(os.Stdout, args[0], programName())
+			}
+		},
+	})
+	registerCommand(&command{
+		name:   "__complete",
+		hidden: true,
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			return func(ctx context.Context, args []string) error {
+				cache, _ := loadCompletionCache()
+				for _, c := range complete(args, cache) {
+					fmt.Println(c)
+				}
+				return nil
+			}
+		},
+	})
+}
+
+// bashCompletion rejoins -flag=value, which bash splits into -flag, = and
+// value (see COMP_WORDBREAKS), and strips from the candidates the part of the
+// rejoined word before bash's current word, which is all bash replaces.
+const bashCompletion = `# bash completion for %[1]s
+_%[2]s_complete() {
+    local IFS=$'\n'
+    local -a words=()
+    local i n word out prefix
+    for (( i = 1; i <= COMP_CWORD; i++ )); do
+        word=${COMP_WORDS[i]}
+        n=${#words[@]}
+        if (( n > 0 )) && [[ ( $word == = && ${words[n-1]} == -* ) || ${words[n-1]} == -*= ]]; then
+            words[n-1]+=$word
+        else
+            words+=("$word")
+        fi
+    done
+    prefix=${words[${#words[@]}-1]}
+    prefix=${prefix%%"${COMP_WORDS[COMP_CWORD]}"}
+    out=$("${COMP_WORDS[0]}" __complete -- "${words[@]}" 2>/dev/null) || return
+    COMPREPLY=( $(printf '%%s\n' "$out" | cut -f1) )
+    COMPREPLY=( "${COMPREPLY[@]#"$prefix"}" )
+}
+complete -o default -F _%[2]s_complete %[1]s
+`
+
+const zshCompletion = `#compdef %[1]s
+_%[2]s_complete() {
+    local -a values displays
+    local line
+    for line in "${(@f)$(${words[1]} __complete -- "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z $line ]] && continue
+        values+=("${line%%%%$'\t'*}")
+        displays+=("${line//$'\t'/  -- }")
+    done
+    compadd -U -d displays -a values
+}
+compdef _%[2]s_complete %[1]s
+`
+
+const fishCompletion = `# fish completion for %[1]s
+function __%[2]s_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    $tokens[1] __complete -- $tokens[2..-1] 2>/dev/null
+end
+complete -c %[1]s -f -a '(__%[2]s_complete)'
+`
+
+// writeCompletionScript writes the completion script for shell, bound to the
+// binary name prog. The scripts delegate to the hidden __complete command.
+func writeCompletionScript(w io.Writer, shell, prog string) error {
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	case "fish":
+		script = fishCompletion
+	default:
+		return fmt.Errorf("unsupported shell %q (expected bash, zsh or fish)", shell)
+	}
+	ident := strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, prog)
+	_, err := fmt.Fprintf(w, script, prog, ident)
+	return err
+}
+
+// complete returns the candidates for the last element of words, which holds
+// the (possibly empty) word being completed. Candidates are "value\tdescription".
+func complete(words []string, cache *completionCache) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	prev := words[:len(words)-1]
+
+	fs := flag.CommandLine
+	var cmd *command
+	if len(prev) > 0 {
+		cmd = commands[prev[0]]
+	}
+	if cmd != nil {
+		fs = flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		cmd.setup(fs)
+		prev = prev[1:]
+	}
+
+	// Value for a flag given as the previous word, or inline as -flag=value.
+	if len(prev) > 0 {
+		if name, ok := flagName(prev[len(prev)-1]); ok && !strings.Contains(prev[len(prev)-1], "=") {
+			if f := fs.Lookup(name); f != nil && !isBoolFlag(f) {
+				return completeValue(flagCompletions[name], cur, "", cache)
+			}
+		}
+	}
+	if name, ok := flagName(cur); ok {
+		if i := strings.Index(cur, "="); i >= 0 {
+			return completeValue(flagCompletions[name], cur[i+1:], cur[:i+1], cache)
+		}
+		var out []string
+		fs.VisitAll(func(f *flag.Flag) {
+			if strings.HasPrefix(f.Name, strings.TrimLeft(cur, "-")) {
+				out = append(out, "-"+f.Name+"\t"+f.Usage)
+			}
+		})
+		return out
+	}
+
+	positional := countPositional(fs, prev)
+	var out []string
+	switch {
+	case cmd == nil && positional == 0:
+		for _, c := range visibleCommands() {
+			if strings.HasPrefix(c.name, cur) {
+				out = append(out, c.name+"\t"+c.summary)
+			}
+		}
+	case cmd != nil && positional == 0:
+		for _, arg := range cmd.args {
+			if strings.HasPrefix(arg, cur) {
+				out = append(out, arg)
+			}
+		}
+	}
+	return out
+}
+
+// flagName reports whether word is a flag and returns its name without dashes
+// or an inline value.
+func flagName(word string) (string, bool) {
+	if !strings.HasPrefix(word, "-") || word == "-" || word == "--" {
+		return "", false
+	}
+	name := strings.TrimLeft(word, "-")
+	if i := strings.Index(name, "="); i >= 0 {
+		name = name[:i]
+	}
+	return name, true
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// countPositional counts the positional arguments in words, skipping flags and
+// their separate values.
+func countPositional(fs *flag.FlagSet, words []string) int {
+	n := 0
+	for i := 0; i < len(words); i++ {
+		name, ok := flagName(words[i])
+		if !ok {
+			n++
+			continue
+		}
+		if f := fs.Lookup(name); f != nil && !isBoolFlag(f) && !strings.Contains(words[i], "=") {
+			i++
+		}
+	}
+	return n
+}
+
+func completeValue(kind, cur, prefix string, cache *completionCache) []string {
+	var out []string
+	switch kind {
+	case "actions":
+		for _, a := range actions {
+			if strings.HasPrefix(a, cur) {
+				out = append(out, prefix+a)
+			}
+		}
+	case "assets":
+		if cache == nil {
+			return nil
+		}
+		for _, a := range cache.Assets {
+			if strings.HasPrefix(a.Address, cur) || hasPrefixFold(a.Symbol, cur) {
+				out = append(out, prefix+a.Address+"\t"+a.Symbol+" ("+a.DisplayName+")")
+			}
+		}
+	case "pools":
+		if cache == nil {
+			return nil
+		}
+		for _, p := range cache.Pools {
+			if strings.HasPrefix(p.Address, cur) || hasPrefixFold(p.Pair, cur) {
+				out = append(out, prefix+p.Address+"\t"+p.Pair)
+			}
+		}
+	}
+	return out
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+type cachedAsset struct {
+	Address     string `json:"address"`
+	Symbol      string `json:"symbol"`
+	DisplayName string `json:"display_name"`
+}
+
+type cachedPool struct {
+	Address string `json:"address"`
+	Pair    string `json:"pair"`
+}
+
+// completionCache is a local snapshot of GetAssets and GetPools, so that tab
+// completion never has to wait on the network.
+type completionCache struct {
+	UpdatedAt time.Time     `json:"updated_at"`
+	Assets    []cachedAsset `json:"assets"`
+	Pools     []cachedPool  `json:"pools"`
+}
+
+func completionCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-stonfi", "completion.json"), nil
+}
+
+func loadCompletionCache() (*completionCache, error) {
+	path, err := completionCachePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache completionCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+func refreshCompletionCache(ctx context.Context, c *client.StonfiClient) error {
+	infoMessage("Refreshing completion cache...")
+	assets, err := c.GetAssets(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching assets: %w", err)
+	}
+	pools, err := c.GetPools(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching pools: %w", err)
+	}
+
+	cache := completionCache{UpdatedAt: time.Now().UTC()}
+	symbols := make(map[string]string, len(assets.AssetList))
+	for _, a := range assets.AssetList {
+		if a.Blacklisted || a.Deprecated {
+			continue
+		}
+		symbols[a.ContractAddress] = a.Symbol
+		cache.Assets = append(cache.Assets, cachedAsset{Address: a.ContractAddress, Symbol: a.Symbol, DisplayName: a.DisplayName})
+	}
+	for _, p := range pools.PoolList {
+		if p.Deprecated {
+			continue
+		}
+		cache.Pools = append(cache.Pools, cachedPool{Address: p.Address, Pair: symbolOr(symbols, p.Token0Address) + "/" + symbolOr(symbols, p.Token1Address)})
+	}
+	sort.Slice(cache.Assets, func(i, j int) bool { return cache.Assets[i].Symbol < cache.Assets[j].Symbol })
+	sort.Slice(cache.Pools, func(i, j int) bool { return cache.Pools[i].Pair < cache.Pools[j].Pair })
+
+	path, err := completionCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	successMessage(fmt.Sprintf("Cached %d assets and %d pools in %s", len(cache.Assets), len(cache.Pools), path))
+	return nil
+}
+
+func symbolOr(symbols map[string]string, address string) string {
+	if s, ok := symbols[address]; ok {
+		return s
+	}
+	return address
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testCache = &completionCache{
+	Assets: []cachedAsset{
+		{Address: "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c", Symbol: "TON", DisplayName: "TON"},
+		{Address: "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs", Symbol: "USD₮", DisplayName: "Tether USD"},
+	},
+	Pools: []cachedPool{
+		{Address: "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE", Pair: "TON/USD₮"},
+	},
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		expected []string
+	}{
+		{
+			name:     "should complete subcommands",
+			words:    []string{"comp"},
+			expected: []string{"completion\tPrint a shell completion script, or refresh the completion cache"},
+		},
+		{
+			name:     "should complete flags",
+			words:    []string{"-ac"},
+			expected: []string{"-action\tAction to perform (" + "assets, asset, wallet-assets, swap-simulate, pools, pool, wallet-pools, farms, farm, wallet-farms, farm-by-pool" + ")"},
+		},
+		{
+			name:     "should complete actions",
+			words:    []string{"-action", "farm"},
+			expected: []string{"farms", "farm", "farm-by-pool"},
+		},
+		{
+			name:     "should complete asset addresses by symbol",
+			words:    []string{"-action", "swap-simulate", "-offer-address", "usd"},
+			expected: []string{"EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs\tUSD₮ (Tether USD)"},
+		},
+		{
+			name:     "should complete inline flag values",
+			words:    []string{"--pool-address=EQD8"},
+			expected: []string{"--pool-address=EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE\tTON/USD₮"},
+		},
+		{
+			name:     "should complete subcommand arguments",
+			words:    []string{"completion", "z"},
+			expected: []string{"zsh"},
+		},
+		{
+			name:  "should not complete past the first positional argument",
+			words: []string{"completion", "bash", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, complete(tt.words, testCache))
+		})
+	}
+}
+
+func TestWriteCompletionScript(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeCompletionScript(&buf, "bash", "go-stonfi"))
+	assert.Contains(t, buf.String(), "complete -o default -F _go_stonfi_complete go-stonfi")
+	assert.Contains(t, buf.String(), "words[n-1]+=$word", "bash rejoins -flag=value split on =")
+
+	assert.Error(t, writeCompletionScript(&buf, "powershell", "stonfi"))
+}
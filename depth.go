@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/depth"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "depth",
+		summary: "Show how much a pool can absorb at each price impact, or rank the pools of a pair",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			levels := fs.String("levels", "0.5,1,2,5", "Comma-separated price impacts, in percent")
+			curve := fs.Int("curve", 0, "Number of points of the depth curve to print (0 omits it)")
+			pair := fs.String("pair", "", "Rank the pools of this pair, as <asset>,<quote>, instead of showing one pool")
+			return func(ctx context.Context, args []string) error {
+				opts := depth.Options{CurvePoints: *curve}
+				if *curve == 0 {
+					opts.CurvePoints = -1
+				}
+				for _, s := range strings.Split(*levels, ",") {
+					percent, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+					if err != nil || percent <= 0 || percent >= 100 {
+						return fmt.Errorf("invalid level %q, want a percentage", s)
+					}
+					opts.Levels = append(opts.Levels, percent/100)
+				}
+				c := client.NewStonfiClient()
+
+				if *pair != "" {
+					assets := strings.Split(*pair, ",")
+					if len(assets) != 2 {
+						return fmt.Errorf("-pair wants <asset>,<quote>")
+					}
+					pools, err := c.GetPools(ctx)
+					if err != nil {
+						return fmt.Errorf("fetching pools: %w", err)
+					}
+					for _, impact := range opts.Levels {
+						ranked := depth.Rank(pools.PoolList, assets[0], assets[1], impact)
+						printJSON(fmt.Sprintf("Pools by depth at %g%% impact", impact*100), ranked)
+					}
+					return nil
+				}
+
+				if len(args) != 1 {
+					return fmt.Errorf("usage: %s depth [flags] <pool-address>", programName())
+				}
+				d, err := depth.ForPool(ctx, c, args[0], opts)
+				if err != nil {
+					return err
+				}
+				printJSON("Depth", d)
+				return nil
+			}
+		},
+	})
+}
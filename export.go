@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/export"
+	"github.com/itay747/go-stonfi/src/indexer"
+	"github.com/itay747/go-stonfi/src/types"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "export",
+		summary: "Export operations, pool stats or candles to CSV or Parquet",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			kind := fs.String("kind", "operations", "What to export: operations, pool-stats or candles")
+			format := fs.String("format", "csv", "Output format: csv or parquet")
+			from := fs.String("from", "", "Start of the range, as 2006-01-02 or RFC 3339 (default 24 hours ago)")
+			to := fs.String("to", "", "End of the range, as 2006-01-02 or RFC 3339 (default now)")
+			interval := fs.Duration("interval", time.Hour, "Candle interval")
+			index := fs.String("index", "", "Read operations from this indexer database instead of the API")
+			out := fs.String("out", "-", "Output file, or - for standard output")
+			return func(ctx context.Context, args []string) error {
+				f, err := export.ParseFormat(*format)
+				if err != nil {
+					return err
+				}
+				until := time.Now().UTC().Truncate(time.Second)
+				if *to != "" {
+					if until, err = parseExportTime(*to); err != nil {
+						return err
+					}
+				}
+				since := until.Add(-24 * time.Hour)
+				if *from != "" {
+					if since, err = parseExportTime(*from); err != nil {
+						return err
+					}
+				}
+				if !since.Before(until) {
+					return fmt.Errorf("-from (%s) must be before -to (%s)", since.Format(time.RFC3339), until.Format(time.RFC3339))
+				}
+				if *index != "" {
+					// Opening a missing database would create an empty one.
+					if _, err := os.Stat(*index); err != nil {
+						return fmt.Errorf("-index: %w", err)
+					}
+				}
+
+				var w io.Writer = os.Stdout
+				if *out != "-" {
+					file, err := os.Create(*out)
+					if err != nil {
+						return err
+					}
+					defer file.Close()
+					w = file
+				}
+
+				c := client.NewStonfiClient()
+				operations := func() (iter.Seq2[types.OperationInfo, error], func(), error) {
+					if *index == "" {
+						return export.Operations(ctx, c, since, until), func() {}, nil
+					}
+					ix, err := indexer.Open(*index, c, indexer.Options{})
+					if err != nil {
+						return nil, nil, err
+					}
+					// Filter bounds are inclusive; the range is not.
+					seq := ix.Operations(ctx, indexer.Filter{Since: since, Until: until.Add(-time.Millisecond)})
+					return seq, func() { ix.Close() }, nil
+				}
+
+				var n int
+				switch *kind {
+				case "operations", "candles":
+					ops, done, err := operations()
+					if err != nil {
+						return err
+					}
+					defer done()
+					if *kind == "operations" {
+						n, err = copyRows(w, f, export.OperationRows(ops))
+					} else {
+						n, err = copyRows(w, f, export.CandleRows(ops, *interval))
+					}
+					if err != nil {
+						return err
+					}
+				case "pool-stats":
+					if *index != "" {
+						return fmt.Errorf("-index only applies to operations and candles")
+					}
+					if n, err = copyRows(w, f, export.PoolStats(ctx, c, since, until)); err != nil {
+						return err
+					}
+				default:
+					return fmt.Errorf("unknown kind %q, want operations, pool-stats or candles", *kind)
+				}
+				if *out != "-" {
+					successMessage(fmt.Sprintf("Exported %d %s rows to %s", n, *kind, *out))
+				}
+				return nil
+			}
+		},
+	})
+}
+
+func copyRows[T any](w io.Writer, format export.Format, rows iter.Seq2[T, error]) (int, error) {
+	writer, err := export.NewWriter[T](w, format)
+	if err != nil {
+		return 0, err
+	}
+	return export.Copy(writer, rows)
+}
+
+func parseExportTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, want 2006-01-02 or RFC 3339", s)
+	}
+	return t.UTC(), nil
+}
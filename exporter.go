@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/exporter"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "exporter",
+		summary: "Serve pool, asset, farm and DEX metrics for Prometheus",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			listen := fs.String("listen", ":9617", "Address to serve /metrics on")
+			interval := fs.Duration("interval", time.Minute, fmt.Sprintf("Time between refreshes (at least %s)", exporter.MinInterval))
+			window := fs.Duration("stats-window", 24*time.Hour, "Period covered by the DEX totals (at most 24h)")
+			deprecated := fs.Bool("include-deprecated", false, "Also export deprecated pools and assets")
+			return func(ctx context.Context, args []string) error {
+				e := exporter.New(client.NewStonfiClient(), exporter.Options{
+					Interval:          *interval,
+					StatsWindow:       *window,
+					IncludeDeprecated: *deprecated,
+				})
+				go e.Run(ctx, func(err error) {
+					errorMessage(fmt.Sprintf("Error: %v", err))
+				})
+
+				mux := http.NewServeMux()
+				mux.Handle("/metrics", e.Handler())
+				infoMessage(fmt.Sprintf("Serving metrics on %s/metrics", *listen))
+				server := &http.Server{Addr: *listen, Handler: mux}
+				if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+				return nil
+			}
+		},
+	})
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/farms"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "farm-positions",
+		summary: "List a wallet's farm stakes with pending rewards and unstake eligibility",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			all := fs.Bool("all", false, "Include positions that have been unstaked")
+			return func(ctx context.Context, args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("usage: %s farm-positions [flags] <wallet-address>", programName())
+				}
+				positions, err := farms.WalletFarmPositions(ctx, client.NewStonfiClient(), args[0])
+				if err != nil {
+					return err
+				}
+				var shown []farms.Position
+				var pendingUsd float64
+				for _, p := range positions {
+					if p.Unstaked() && !*all {
+						continue
+					}
+					shown = append(shown, p)
+					pendingUsd += p.PendingUsd
+				}
+				successMessage(fmt.Sprintf("%d positions with $%.2f of pending rewards", len(shown), pendingUsd))
+				printJSON("Positions", shown)
+				return nil
+			}
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/farms"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "farm-yield",
+		summary: "Recompute a farm's APR and APY and project the rewards of a stake",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			stake := fs.Float64("stake-usd", 0, "Value of LP tokens to project rewards for (0 skips the projection)")
+			days := fs.Float64("days", 30, "Days to project the stake over")
+			return func(ctx context.Context, args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("usage: %s farm-yield [flags] <farm-address>", programName())
+				}
+				y, err := farms.ForFarm(ctx, client.NewStonfiClient(), args[0])
+				if err != nil {
+					return err
+				}
+				successMessage(fmt.Sprintf("APR %.2f%%, APY %.2f%% (reported %.2f%%), with pool fees %.2f%%",
+					y.Apr*100, y.Apy*100, y.ReportedApy*100, y.TotalApy*100))
+				printJSON("Yield", y)
+				if *stake > 0 {
+					printJSON("Projection", y.Project(*stake, time.Duration(*days*float64(24*time.Hour))))
+				}
+				return nil
+			}
+		},
+	})
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/feed"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "feed",
+		summary: "Push live pool and asset changes over SSE and WebSocket",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			addr := fs.String("addr", ":8081", "Address to listen on")
+			interval := fs.Duration("interval", 10*time.Second, "Time between polls")
+			heartbeat := fs.Duration("heartbeat", 15*time.Second, "Time between heartbeats to idle clients")
+			origins := fs.String("origins", "", "Comma-separated hosts whose pages may connect over WebSocket")
+			return func(ctx context.Context, args []string) error {
+				opts := feed.Options{Interval: *interval, Heartbeat: *heartbeat}
+				if *origins != "" {
+					opts.OriginPatterns = strings.Split(*origins, ",")
+				}
+				hub := feed.New(client.NewStonfiClient(), opts)
+				go hub.Run(ctx, func(n int, err error) {
+					if err != nil {
+						errorMessage(err.Error())
+					}
+				})
+				srv := &http.Server{Addr: *addr, Handler: hub.Handler(), ReadHeaderTimeout: 10 * time.Second}
+				go func() {
+					<-ctx.Done()
+					shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+					defer cancel()
+					srv.Shutdown(shutdown)
+				}()
+				infoMessage(fmt.Sprintf("Serving SSE at %s/events and WebSocket at %s/ws", *addr, *addr))
+				if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+				return nil
+			}
+		},
+	})
+}
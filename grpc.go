@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/rpc"
+	"google.golang.org/grpc"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "grpc",
+		summary: "Serve DEX data over gRPC",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			addr := fs.String("addr", ":9090", "Address to listen on")
+			interval := fs.Duration("interval", 30*time.Second, "Polling interval of streams that ask for none")
+			minInterval := fs.Duration("min-interval", 5*time.Second, "Shortest polling interval a stream may ask for")
+			return func(ctx context.Context, args []string) error {
+				listener, err := net.Listen("tcp", *addr)
+				if err != nil {
+					return err
+				}
+				g := grpc.NewServer()
+				rpc.New(client.NewStonfiClient(), rpc.Options{Interval: *interval, MinInterval: *minInterval}).Register(g)
+				go func() {
+					<-ctx.Done()
+					g.GracefulStop()
+				}()
+				infoMessage(fmt.Sprintf("Serving stonfi.v1.Dex on %s", listener.Addr()))
+				return g.Serve(listener)
+			}
+		},
+	})
+}
@@ -14,16 +14,24 @@ import (
 
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
-func main() {
+var (
+	action        = flag.String("action", "", "Action to perform ("+strings.Join(actions, ", ")+")")
+	assetAddress  = flag.String("asset-address", "", "Asset address (for asset, wallet-assets, swap-simulate actions)")
+	walletAddress = flag.String("wallet-address", "", "Wallet address (for wallet-assets, wallet-pools, wallet-farms actions)")
+	offerAddress  = flag.String("offer-address", "", "Offer asset address (for swap-simulate action)")
+	askAddress    = flag.String("ask-address", "", "Ask asset address (for swap-simulate action)")
+	amount        = flag.String("amount", "0", "Amount to swap (for swap-simulate action)")
+	slippage      = flag.String("slippage", "0.01", "Slippage tolerance (for swap-simulate action)")
+	poolAddress   = flag.String("pool-address", "", "Pool address (for pool and farm-by-pool actions)")
+)
 
-	action := flag.String("action", "", "Action to perform (assets, asset, wallet-assets, swap-simulate, pools, farms, farm-by-pool)")
-	assetAddress := flag.String("asset-address", "", "Asset address (for asset, wallet-assets, swap-simulate actions)")
-	walletAddress := flag.String("wallet-address", "", "Wallet address (for wallet-assets, wallet-pools, wallet-farms actions)")
-	offerAddress := flag.String("offer-address", "", "Offer asset address (for swap-simulate action)")
-	askAddress := flag.String("ask-address", "", "Ask asset address (for swap-simulate action)")
-	amount := flag.String("amount", "0", "Amount to swap (for swap-simulate action)")
-	slippage := flag.String("slippage", "0.01", "Slippage tolerance (for swap-simulate action)")
-	poolAddress := flag.String("pool-address", "", "Pool address (for pool and farm-by-pool actions)")
+func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			runCommand(context.Background(), cmd, os.Args[2:])
+			return
+		}
+	}
 
 	flag.Parse()
 
@@ -63,7 +71,7 @@ func main() {
 		validateRequiredFlag("pool-address", *poolAddress)
 	//	handleFarmByPool(ctx, client, *poolAddress)
 	default:
-		errorMessage("Invalid action. Valid actions are: assets, asset, wallet-assets, swap-simulate, pools, pool, wallet-pools, farms, farm, wallet-farms, farm-by-pool")
+		errorMessage("Invalid action. Valid actions are: " + strings.Join(actions, ", "))
 		os.Exit(1)
 	}
 }
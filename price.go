@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/indexer"
+	"github.com/itay747/go-stonfi/src/oracle"
+	"github.com/itay747/go-stonfi/src/snapshot"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "price",
+		summary: "Compute the TWAP and VWAP of an asset with outlier rejection",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			window := fs.Duration("window", time.Hour, "Period to sample")
+			index := fs.String("index", "", "Read swaps from this indexer database instead of the API")
+			db := fs.String("db", "", "Also sample reserves from this snapshot database")
+			threshold := fs.Float64("outlier-threshold", 5, "Reject samples this many median absolute deviations from the median")
+			return func(ctx context.Context, args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("usage: %s price [flags] <asset-address>", programName())
+				}
+				c := client.NewStonfiClient()
+				opts := oracle.Options{Window: *window, OutlierThreshold: *threshold, Operations: oracle.APIOperations(c)}
+				if *index != "" {
+					ix, err := indexer.Open(*index, c, indexer.Options{})
+					if err != nil {
+						return err
+					}
+					defer ix.Close()
+					opts.Operations = oracle.IndexedOperations(ix)
+				}
+				if *db != "" {
+					store, err := snapshot.Open(*db)
+					if err != nil {
+						return err
+					}
+					defer store.Close()
+					opts.Snapshots = store
+				}
+
+				infoMessage(fmt.Sprintf("Sampling prices of %s over the last %s...", args[0], *window))
+				price, err := oracle.New(c, opts).Price(ctx, args[0])
+				if errors.Is(err, oracle.ErrNoSamples) {
+					printJSON("Price", price)
+				}
+				if err != nil {
+					return err
+				}
+				if price.Stale {
+					errorMessage(fmt.Sprintf("Latest sample is %s old", price.Staleness))
+				}
+				if price.LowConfidence {
+					errorMessage("Some samples are priced with current prices; record snapshots with -db for historical ones")
+				}
+				if price.ThirdPartyMismatch {
+					errorMessage(fmt.Sprintf("Price deviates %.2f%% from the third party price", price.ThirdPartyDeviation*100))
+				}
+				printJSON("Price", price)
+				return nil
+			}
+		},
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/query"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "query",
+		summary: "Filter and sort pools, assets or farms, e.g. 'tvl>100000 and token=USDT order by apy_7d desc limit 20'",
+		args:    []string{"pools", "assets", "farms"},
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			fields := fs.Bool("fields", false, "List the fields that can be queried instead")
+			return func(ctx context.Context, args []string) error {
+				if len(args) < 1 || len(args) > 2 {
+					return fmt.Errorf("usage: %s query [flags] pools|assets|farms [query]", programName())
+				}
+				kind := args[0]
+				if *fields {
+					names := map[string][]string{"pools": query.PoolFields(), "assets": query.AssetFields(), "farms": query.FarmFields()}[kind]
+					if names == nil {
+						return fmt.Errorf("unknown kind %q, want pools, assets or farms", kind)
+					}
+					fmt.Println(strings.Join(names, "\n"))
+					return nil
+				}
+				q := query.New()
+				if len(args) == 2 {
+					var err error
+					if q, err = query.Parse(args[1]); err != nil {
+						return err
+					}
+				}
+
+				c := client.NewStonfiClient()
+				assets, err := c.GetAssets(ctx)
+				if err != nil {
+					return fmt.Errorf("fetching assets: %w", err)
+				}
+				var result interface{}
+				switch kind {
+				case "pools":
+					pools, err := c.GetPools(ctx)
+					if err != nil {
+						return fmt.Errorf("fetching pools: %w", err)
+					}
+					result, err = q.Pools(pools.PoolList, assets.AssetList)
+					if err != nil {
+						return err
+					}
+				case "assets":
+					result, err = q.Assets(assets.AssetList)
+					if err != nil {
+						return err
+					}
+				case "farms":
+					farms, err := c.GetFarms(ctx)
+					if err != nil {
+						return fmt.Errorf("fetching farms: %w", err)
+					}
+					result, err = q.Farms(farms.Farms, assets.AssetList)
+					if err != nil {
+						return err
+					}
+				default:
+					return fmt.Errorf("unknown kind %q, want pools, assets or farms", kind)
+				}
+				printJSON(strings.ToUpper(kind[:1])+kind[1:], result)
+				return nil
+			}
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/router"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "routers",
+		summary: "List known routers, or show the router version and pool type of a pool",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			file := fs.String("routers", "", "YAML file of routers to add to the known ones")
+			return func(ctx context.Context, args []string) error {
+				if len(args) > 1 {
+					return fmt.Errorf("usage: %s routers [flags] [pool-address]", programName())
+				}
+				registry := router.Default()
+				if *file != "" {
+					if err := registry.LoadFile(*file); err != nil {
+						return err
+					}
+				}
+				if len(args) == 0 {
+					printJSON("Routers", registry.Routers())
+					return nil
+				}
+
+				response, err := client.NewStonfiClient().GetPool(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				info, err := registry.Classify(response.Pool)
+				if err != nil {
+					return err
+				}
+				printJSON("Router", info)
+				return nil
+			}
+		},
+	})
+}
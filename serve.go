@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/server"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "serve",
+		summary: "Serve a caching proxy of the API with aggregated endpoints",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			addr := fs.String("addr", ":8080", "Address to listen on")
+			ttl := fs.Duration("ttl", 30*time.Second, "How long GET responses are cached (negative disables caching)")
+			keysFile := fs.String("keys", "", "YAML file of API keys and their quotas (default: no keys required)")
+			return func(ctx context.Context, args []string) error {
+				opts := server.Options{TTL: *ttl}
+				if *keysFile != "" {
+					f, err := os.Open(*keysFile)
+					if err != nil {
+						return err
+					}
+					opts.Keys, err = server.LoadKeys(f)
+					f.Close()
+					if err != nil {
+						return err
+					}
+				}
+				s, err := server.New(client.NewStonfiClient(), opts)
+				if err != nil {
+					return err
+				}
+				srv := &http.Server{Addr: *addr, Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+				go func() {
+					<-ctx.Done()
+					shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+					defer cancel()
+					srv.Shutdown(shutdown)
+				}()
+				infoMessage(fmt.Sprintf("Serving on %s, with %d API keys; OpenAPI description at /openapi.json", *addr, len(opts.Keys)))
+				if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+				return nil
+			}
+		},
+	})
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/snapshot"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "snapshot",
+		summary: "Record snapshots of pools, assets and farms in a SQLite database",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			db := fs.String("db", "stonfi.db", "Path of the SQLite database")
+			interval := fs.Duration("interval", 15*time.Minute, "Time between snapshots")
+			once := fs.Bool("once", false, "Take a single snapshot and exit")
+			retention := fs.Duration("retention", 0, "Delete snapshots older than this after each snapshot (0 keeps everything)")
+			return func(ctx context.Context, args []string) error {
+				store, err := snapshot.Open(*db)
+				if err != nil {
+					return err
+				}
+				defer store.Close()
+
+				c := client.NewStonfiClient()
+				report := func(snap snapshot.Snapshot, err error) {
+					if err != nil {
+						errorMessage(fmt.Sprintf("Error: %v", err))
+						return
+					}
+					successMessage(fmt.Sprintf("Saved snapshot of %d pools, %d assets and %d farms at %s",
+						len(snap.Pools), len(snap.Assets), len(snap.Farms), snap.TakenAt.Format(time.RFC3339)))
+					if *retention > 0 {
+						if _, err := store.Prune(ctx, snap.TakenAt.Add(-*retention)); err != nil {
+							errorMessage(fmt.Sprintf("Error: %v", err))
+						}
+					}
+				}
+
+				if *once {
+					snap, err := snapshot.Collect(ctx, c, store)
+					if err != nil {
+						return err
+					}
+					report(snap, nil)
+					return nil
+				}
+				infoMessage(fmt.Sprintf("Recording snapshots to %s every %s", *db, *interval))
+				snapshot.Run(ctx, c, store, *interval, report)
+				return nil
+			}
+		},
+	})
+}
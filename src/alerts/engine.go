@@ -0,0 +1,380 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Alert is a rule firing.
+type Alert struct {
+	Rule string `json:"rule"`
+	Kind Kind   `json:"kind"`
+	// Subject is the address of the asset, pool, farm or wallet watched.
+	Subject   string    `json:"subject"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Message   string    `json:"message"`
+	At        time.Time `json:"at"`
+	// Operation is the swap of a WalletSwap alert.
+	Operation *types.OperationInfo `json:"operation,omitempty"`
+}
+
+// Engine evaluates rules and sends what fires to its notifiers.
+type Engine struct {
+	client    *client.StonfiClient
+	rules     []Rule
+	notifiers []Notifier
+	states    map[string]*state
+	// tokens caches the reward tokens of farms, for their decimals.
+	tokens map[string]types.Asset
+	now    func() time.Time
+}
+
+// state is what the engine remembers of a rule between polls.
+type state struct {
+	// armed is false from when a threshold rule fires until its value has
+	// moved back past the threshold by the hysteresis.
+	armed     bool
+	lastFired time.Time
+	reserves  []reserveSample
+	// lastLt is the logical time of the latest wallet transaction seen, and
+	// started whether the wallet has been polled yet.
+	lastLt  uint64
+	started bool
+}
+
+type reserveSample struct {
+	at       time.Time
+	reserves [2]float64
+}
+
+// New returns an engine for rules, which must be valid.
+func New(c *client.StonfiClient, rules []Rule, notifiers ...Notifier) (*Engine, error) {
+	e := &Engine{
+		client:    c,
+		rules:     rules,
+		notifiers: notifiers,
+		states:    map[string]*state{},
+		tokens:    map[string]types.Asset{},
+		now:       time.Now,
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("alerts: %w", err)
+		}
+		if _, ok := e.states[rule.Name]; ok {
+			return nil, fmt.Errorf("alerts: duplicate rule %q", rule.Name)
+		}
+		e.states[rule.Name] = &state{armed: true}
+	}
+	return e, nil
+}
+
+// Evaluate polls the data the rules watch, once, and notifies the alerts
+// that fire. A rule whose data cannot be fetched is skipped; its error and
+// those of the notifiers are returned together.
+func (e *Engine) Evaluate(ctx context.Context) ([]Alert, error) {
+	p := &poll{ctx: ctx, client: e.client, assets: map[string]*types.Asset{}, pools: map[string]*types.Pool{},
+		farms: map[string]*types.Farm{}, operations: map[string]types.Operations{}}
+	now := e.now()
+	var alerts []Alert
+	var errs []error
+	for _, rule := range e.rules {
+		fired, err := e.evaluate(p, rule, e.states[rule.Name], now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alerts: rule %q: %w", rule.Name, err))
+			continue
+		}
+		alerts = append(alerts, fired...)
+	}
+	for _, alert := range alerts {
+		for _, n := range e.notifiers {
+			if err := n.Notify(ctx, alert); err != nil {
+				errs = append(errs, fmt.Errorf("alerts: rule %q: %w", alert.Rule, err))
+			}
+		}
+	}
+	return alerts, errors.Join(errs...)
+}
+
+// Run evaluates the rules every interval until ctx is done. Each outcome is
+// passed to report, if set.
+func (e *Engine) Run(ctx context.Context, interval time.Duration, report func([]Alert, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		alerts, err := e.Evaluate(ctx)
+		if report != nil {
+			report(alerts, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Engine) evaluate(p *poll, rule Rule, st *state, now time.Time) ([]Alert, error) {
+	alert := Alert{Rule: rule.Name, Kind: rule.Kind, Threshold: rule.Threshold, At: now}
+	switch rule.Kind {
+	case PriceAbove, PriceBelow:
+		asset, err := p.asset(rule.Asset)
+		if err != nil {
+			return nil, err
+		}
+		price := assetPrice(*asset)
+		if !e.crossed(rule, st, price, rule.Kind == PriceAbove, now) {
+			return nil, nil
+		}
+		direction := "above"
+		if rule.Kind == PriceBelow {
+			direction = "below"
+		}
+		alert.Subject, alert.Value = rule.Asset, price
+		alert.Message = fmt.Sprintf("%s is at $%g, %s $%g", symbolOf(*asset, rule.Asset), price, direction, rule.Threshold)
+		return []Alert{alert}, nil
+
+	case ReserveDrop:
+		pool, err := p.pool(rule.Pool)
+		if err != nil {
+			return nil, err
+		}
+		reserves := [2]float64{utils.ParseFloat(pool.Reserve0), utils.ParseFloat(pool.Reserve1)}
+		window := rule.Window
+		if window <= 0 {
+			window = time.Hour
+		}
+		st.reserves = append(st.reserves, reserveSample{at: now, reserves: reserves})
+		for len(st.reserves) > 1 && now.Sub(st.reserves[0].at) > window {
+			st.reserves = st.reserves[1:]
+		}
+		var drop float64
+		worst := 0
+		for i := range reserves {
+			var peak float64
+			for _, s := range st.reserves {
+				peak = math.Max(peak, s.reserves[i])
+			}
+			if peak > 0 && 1-reserves[i]/peak > drop {
+				drop, worst = 1-reserves[i]/peak, i
+			}
+		}
+		if !e.crossed(rule, st, drop, true, now) {
+			return nil, nil
+		}
+		token := pool.Token0Address
+		if worst == 1 {
+			token = pool.Token1Address
+		}
+		alert.Subject, alert.Value = rule.Pool, drop
+		alert.Message = fmt.Sprintf("pool %s: reserve of %s is down %.2f%% from its %s high", rule.Pool, token, drop*100, window)
+		return []Alert{alert}, nil
+
+	case FarmRewardsBelow:
+		farm, err := p.farm(rule.Farm)
+		if err != nil {
+			return nil, err
+		}
+		remaining, token, ok, err := e.remainingRewards(p, *farm, rule.Asset)
+		if err != nil || !ok {
+			return nil, err
+		}
+		if !e.crossed(rule, st, remaining, false, now) {
+			return nil, nil
+		}
+		alert.Subject, alert.Value = rule.Farm, remaining
+		alert.Message = fmt.Sprintf("farm %s has %g %s of rewards left, below %g", rule.Farm, remaining, token, rule.Threshold)
+		return []Alert{alert}, nil
+
+	case WalletSwap:
+		ops, err := p.walletOperations(rule.Wallet)
+		if err != nil {
+			return nil, err
+		}
+		var alerts []Alert
+		latest := st.lastLt
+		for i := range ops {
+			op := ops[i]
+			lt := op.Operation.LogicalTime()
+			if lt > latest {
+				latest = lt
+			}
+			// The first poll only sets where new operations start.
+			if !st.started || lt <= st.lastLt || !strings.EqualFold(op.Operation.OperationType, "swap") {
+				continue
+			}
+			if rule.Cooldown > 0 && !st.lastFired.IsZero() && now.Sub(st.lastFired) < rule.Cooldown {
+				continue
+			}
+			st.lastFired = now
+			a := alert
+			a.Subject, a.Operation = rule.Wallet, &op
+			a.Message = fmt.Sprintf("wallet %s swapped %s", rule.Wallet, describeSwap(op))
+			alerts = append(alerts, a)
+		}
+		st.lastLt, st.started = latest, true
+		return alerts, nil
+	}
+	return nil, fmt.Errorf("unknown kind %q", rule.Kind)
+}
+
+// crossed reports whether a threshold rule fires for value, and updates its
+// state. It fires when value reaches the threshold while the rule is armed
+// and out of its cooldown.
+func (e *Engine) crossed(rule Rule, st *state, value float64, above bool, now time.Time) bool {
+	t := rule.Threshold
+	if !st.armed {
+		if above {
+			st.armed = value < t*(1-rule.Hysteresis)
+		} else {
+			st.armed = value > t*(1+rule.Hysteresis)
+		}
+		return false
+	}
+	reached := value <= t
+	if above {
+		reached = value >= t
+	}
+	if !reached {
+		return false
+	}
+	if rule.Cooldown > 0 && !st.lastFired.IsZero() && now.Sub(st.lastFired) < rule.Cooldown {
+		return false
+	}
+	st.armed, st.lastFired = false, now
+	return true
+}
+
+// remainingRewards returns the remaining rewards of farm in whole tokens of
+// asset, or of the active stream with the least left if asset is empty. ok
+// is false if there is no such stream.
+func (e *Engine) remainingRewards(p *poll, farm types.Farm, asset string) (remaining float64, symbol string, ok bool, err error) {
+	for _, r := range farm.Rewards {
+		if asset != "" && utils.AddressKey(r.Address) != utils.AddressKey(asset) {
+			continue
+		}
+		if asset == "" && !strings.EqualFold(r.Status, "active") {
+			continue
+		}
+		units, valid := new(big.Int).SetString(r.RemainingRewards, 10)
+		if !valid {
+			return 0, "", false, fmt.Errorf("farm %s: reward %s: invalid remaining_rewards %q", farm.MinterAddress, r.Address, r.RemainingRewards)
+		}
+		key := utils.AddressKey(r.Address)
+		token, cached := e.tokens[key]
+		if !cached {
+			a, err := p.asset(r.Address)
+			if err != nil {
+				return 0, "", false, err
+			}
+			token = *a
+			e.tokens[key] = token
+		}
+		amount := utils.ToUnits(units, token.Decimals)
+		if !ok || amount < remaining {
+			remaining, symbol, ok = amount, symbolOf(token, r.Address), true
+		}
+	}
+	return remaining, symbol, ok, nil
+}
+
+// poll fetches what the rules of one evaluation need, each thing once.
+type poll struct {
+	ctx        context.Context
+	client     *client.StonfiClient
+	assets     map[string]*types.Asset
+	pools      map[string]*types.Pool
+	farms      map[string]*types.Farm
+	operations map[string]types.Operations
+}
+
+func (p *poll) asset(address string) (*types.Asset, error) {
+	key := utils.AddressKey(address)
+	if a, ok := p.assets[key]; ok {
+		return a, nil
+	}
+	response, err := p.client.GetAsset(p.ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("fetching asset %s: %w", address, err)
+	}
+	p.assets[key] = &response.Asset
+	return &response.Asset, nil
+}
+
+func (p *poll) pool(address string) (*types.Pool, error) {
+	key := utils.AddressKey(address)
+	if pool, ok := p.pools[key]; ok {
+		return pool, nil
+	}
+	response, err := p.client.GetPool(p.ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("fetching pool %s: %w", address, err)
+	}
+	p.pools[key] = &response.Pool
+	return &response.Pool, nil
+}
+
+func (p *poll) farm(address string) (*types.Farm, error) {
+	key := utils.AddressKey(address)
+	if farm, ok := p.farms[key]; ok {
+		return farm, nil
+	}
+	response, err := p.client.GetFarm(p.ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("fetching farm %s: %w", address, err)
+	}
+	p.farms[key] = &response.Farm
+	return &response.Farm, nil
+}
+
+func (p *poll) walletOperations(address string) (types.Operations, error) {
+	key := utils.AddressKey(address)
+	if ops, ok := p.operations[key]; ok {
+		return ops, nil
+	}
+	response, err := p.client.GetWalletOperations(p.ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("fetching operations of %s: %w", address, err)
+	}
+	p.operations[key] = response.Operations
+	return response.Operations, nil
+}
+
+// describeSwap describes a swap by the change in each asset, as in
+// "-1.5 TON, +7.2 USDT".
+func describeSwap(info types.OperationInfo) string {
+	op := info.Operation
+	side := func(delta string, asset types.Asset, address string) string {
+		units, ok := new(big.Int).SetString(delta, 10)
+		if !ok {
+			return delta + " " + symbolOf(asset, address)
+		}
+		return fmt.Sprintf("%+g %s", utils.ToUnits(units, asset.Decimals), symbolOf(asset, address))
+	}
+	return side(op.Asset0Delta, info.Asset0Info, op.Asset0Address) + ", " + side(op.Asset1Delta, info.Asset1Info, op.Asset1Address) +
+		" in pool " + op.PoolAddress
+}
+
+func assetPrice(a types.Asset) float64 {
+	if a.DexPriceUsd != "" {
+		return utils.ParseFloat(a.DexPriceUsd)
+	}
+	return utils.ParseFloat(a.DexUsdPrice)
+}
+
+func symbolOf(a types.Asset, address string) string {
+	if a.Symbol != "" {
+		return a.Symbol
+	}
+	return address
+}
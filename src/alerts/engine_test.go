@@ -0,0 +1,186 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	farmAddress = "EQCEtmlPBWm7X9_Ec7Vmr5Jc6tLgrpjAoTrhoGdGbOkTL9ox"
+	wallet      = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+)
+
+func ton(price string) types.Asset {
+	return types.Asset{ContractAddress: tonAddress, Symbol: "TON", Decimals: 9, DexPriceUsd: price}
+}
+
+// clock is a fake clock the tests move by hand.
+type clock struct{ t time.Time }
+
+func (c *clock) Now() time.Time          { return c.t }
+func (c *clock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// recorder is a Notifier keeping what it is sent.
+type recorder struct{ alerts []Alert }
+
+func (r *recorder) Notify(_ context.Context, a Alert) error {
+	r.alerts = append(r.alerts, a)
+	return nil
+}
+
+func newEngine(t *testing.T, s *stonfitest.Server, rules ...Rule) (*Engine, *clock, *recorder) {
+	rec := &recorder{}
+	e, err := New(s.Client(), rules, rec)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	clk := &clock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	e.now = clk.Now
+	return e, clk, rec
+}
+
+func evaluate(t *testing.T, e *Engine, clk *clock) []Alert {
+	clk.Advance(time.Minute)
+	alerts, err := e.Evaluate(context.Background())
+	assert.NoError(t, err)
+	return alerts
+}
+
+func TestPriceHysteresis(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Assets: []types.Asset{ton("6.5")}})
+	defer s.Close()
+	e, clk, rec := newEngine(t, s, Rule{Name: "ton above 7", Kind: PriceAbove, Asset: tonAddress, Threshold: 7, Hysteresis: 0.05})
+
+	assert.Empty(t, evaluate(t, e, clk))
+	s.AddAsset(ton("7.2"))
+	alerts := evaluate(t, e, clk)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, 7.2, alerts[0].Value)
+		assert.Equal(t, "TON is at $7.2, above $7", alerts[0].Message)
+		assert.Equal(t, clk.Now(), alerts[0].At)
+	}
+	assert.Equal(t, alerts, rec.alerts, "alerts are sent to the notifiers")
+
+	s.AddAsset(ton("6.9"))
+	assert.Empty(t, evaluate(t, e, clk), "still within the hysteresis")
+	s.AddAsset(ton("7.1"))
+	assert.Empty(t, evaluate(t, e, clk), "not rearmed")
+	s.AddAsset(ton("6.6"))
+	assert.Empty(t, evaluate(t, e, clk), "rearmed below 6.65")
+	s.AddAsset(ton("7"))
+	assert.Len(t, evaluate(t, e, clk), 1)
+}
+
+func TestPriceCooldown(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Assets: []types.Asset{ton("4")}})
+	defer s.Close()
+	e, clk, _ := newEngine(t, s, Rule{Name: "ton below 5", Kind: PriceBelow, Asset: tonAddress, Threshold: 5, Cooldown: time.Hour})
+
+	assert.Len(t, evaluate(t, e, clk), 1)
+	s.AddAsset(ton("6"))
+	assert.Empty(t, evaluate(t, e, clk))
+	s.AddAsset(ton("4"))
+	assert.Empty(t, evaluate(t, e, clk), "rearmed but cooling down")
+	clk.Advance(time.Hour)
+	assert.Len(t, evaluate(t, e, clk), 1, "still below once the cooldown is over")
+}
+
+func TestReserveDrop(t *testing.T) {
+	pool := types.Pool{Address: poolAddress, Token0Address: tonAddress, Token1Address: usdtAddress, Reserve0: "1000000000000", Reserve1: "5000000000"}
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Pools: []types.Pool{pool}})
+	defer s.Close()
+	e, clk, _ := newEngine(t, s, Rule{Name: "drain", Kind: ReserveDrop, Pool: poolAddress, Threshold: 0.2, Window: 10 * time.Minute})
+
+	assert.Empty(t, evaluate(t, e, clk))
+	s.SetReserves(poolAddress, "1100000000000", "3900000000")
+	alerts := evaluate(t, e, clk)
+	if assert.Len(t, alerts, 1) {
+		assert.InDelta(t, 0.22, alerts[0].Value, 1e-9)
+		assert.Contains(t, alerts[0].Message, usdtAddress)
+	}
+
+	// Once the high leaves the window, the lower reserves are the baseline.
+	clk.Advance(10 * time.Minute)
+	assert.Empty(t, evaluate(t, e, clk))
+	s.SetReserves(poolAddress, "1100000000000", "3000000000")
+	assert.Len(t, evaluate(t, e, clk), 1, "a 23% drop from 3900")
+}
+
+func TestFarmRewardsBelow(t *testing.T) {
+	farm := types.Farm{MinterAddress: farmAddress, PoolAddress: poolAddress, Rewards: []types.FarmReward{
+		{Address: tonAddress, Status: "active", RemainingRewards: "150000000000"},
+		{Address: usdtAddress, Status: "ended", RemainingRewards: "0"},
+	}}
+	usdt := types.Asset{ContractAddress: usdtAddress, Symbol: "USDT", Decimals: 6}
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Assets: []types.Asset{ton("5"), usdt}, Farms: []types.Farm{farm}})
+	defer s.Close()
+	e, clk, _ := newEngine(t, s, Rule{Name: "low rewards", Kind: FarmRewardsBelow, Farm: farmAddress, Threshold: 100})
+
+	assert.Empty(t, evaluate(t, e, clk), "ended streams are left out")
+	farm.Rewards[0].RemainingRewards = "99000000000"
+	s.AddFarm(farm)
+	alerts := evaluate(t, e, clk)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, 99.0, alerts[0].Value)
+		assert.Equal(t, "farm "+farmAddress+" has 99 TON of rewards left, below 100", alerts[0].Message)
+	}
+	assert.Empty(t, evaluate(t, e, clk))
+}
+
+func TestWalletSwap(t *testing.T) {
+	swap := func(lt, opType string) types.OperationInfo {
+		return types.OperationInfo{
+			Operation: types.Operation{
+				OperationType: opType, WalletAddress: wallet, WalletTxLt: lt, PoolAddress: poolAddress,
+				PoolTxTimestamp: "2024-01-01T00:00:00",
+				Asset0Address:   tonAddress, Asset0Delta: "1500000000",
+				Asset1Address: usdtAddress, Asset1Delta: "-7200000",
+			},
+			Asset0Info: ton("5"),
+			Asset1Info: types.Asset{ContractAddress: usdtAddress, Symbol: "USDT", Decimals: 6},
+		}
+	}
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Operations: types.Operations{swap("100", "swap")}})
+	defer s.Close()
+	e, clk, _ := newEngine(t, s, Rule{Name: "whale", Kind: WalletSwap, Wallet: wallet, Cooldown: time.Hour})
+
+	assert.Empty(t, evaluate(t, e, clk), "operations before the first poll are not new")
+	s.AddOperation(swap("200", "swap"))
+	s.AddOperation(swap("300", "provide_liquidity"))
+	alerts := evaluate(t, e, clk)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, "200", alerts[0].Operation.Operation.WalletTxLt)
+		assert.Equal(t, "wallet "+wallet+" swapped +1.5 TON, -7.2 USDT in pool "+poolAddress, alerts[0].Message)
+	}
+	assert.Empty(t, evaluate(t, e, clk))
+
+	s.AddOperation(swap("400", "swap"))
+	assert.Empty(t, evaluate(t, e, clk), "cooling down")
+	clk.Advance(time.Hour)
+	s.AddOperation(swap("500", "swap"))
+	assert.Len(t, evaluate(t, e, clk), 1, "only swaps since the last poll")
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Assets: []types.Asset{ton("8")}})
+	defer s.Close()
+	e, clk, _ := newEngine(t, s,
+		Rule{Name: "missing", Kind: ReserveDrop, Pool: poolAddress, Threshold: 0.1},
+		Rule{Name: "ton above 7", Kind: PriceAbove, Asset: tonAddress, Threshold: 7},
+	)
+	clk.Advance(time.Minute)
+	alerts, err := e.Evaluate(context.Background())
+	assert.ErrorContains(t, err, `rule "missing"`)
+	assert.Len(t, alerts, 1, "other rules are still evaluated")
+
+	_, err = New(s.Client(), []Rule{{Name: "a", Kind: PriceAbove, Asset: tonAddress, Threshold: 1}, {Name: "a", Kind: PriceBelow, Asset: tonAddress, Threshold: 1}})
+	assert.ErrorContains(t, err, "duplicate rule")
+}
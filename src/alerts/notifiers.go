@@ -0,0 +1,183 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Notifier sends alerts somewhere.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// Writer writes one line per alert.
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriter returns a Notifier writing to w, such as os.Stdout.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Notify writes the alert.
+func (n *Writer) Notify(_ context.Context, alert Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err := fmt.Fprintf(n.w, "%s [%s] %s\n", alert.At.Format(time.RFC3339), alert.Rule, alert.Message)
+	return err
+}
+
+// Webhook POSTs each alert as JSON to URL.
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Notify posts the alert. A response other than 2xx is an error.
+func (n *Webhook) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("webhook: encoding alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.Headers {
+		req.Header.Set(k, v)
+	}
+	c := n.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: posting to %s: %w", n.URL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook: %s answered %s", n.URL, resp.Status)
+	}
+	return nil
+}
+
+// SMTP emails each alert.
+type SMTP struct {
+	Addr string
+	From string
+	To   []string
+	Auth smtp.Auth
+	// Timeout bounds each message when the context has no deadline.
+	// Defaults to 30 seconds.
+	Timeout time.Duration
+	send    func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTP returns a Notifier sending mail through the server at addr
+// ("host:port"), with PLAIN authentication if username is set.
+func NewSMTP(addr, username, password, from string, to ...string) *SMTP {
+	n := &SMTP{Addr: addr, From: from, To: to, send: sendMail}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		n.Auth = smtp.PlainAuth("", username, password, host)
+	}
+	return n
+}
+
+// Notify mails the alert.
+func (n *SMTP) Notify(ctx context.Context, alert Alert) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [stonfi] %s\r\n", alert.Rule)
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.At.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(alert.Message + "\r\n")
+	if _, ok := ctx.Deadline(); !ok {
+		timeout := n.Timeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	send := n.send
+	if send == nil {
+		send = sendMail
+	}
+	if err := send(ctx, n.Addr, n.Auth, n.From, n.To, []byte(msg.String())); err != nil {
+		return fmt.Errorf("smtp: sending to %s: %w", n.Addr, err)
+	}
+	return nil
+}
+
+// sendMail is smtp.SendMail bounded by ctx: the connection is dialed with
+// ctx, and closed when ctx is done.
+func sendMail(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) (err error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer func() {
+		stop()
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+	}()
+
+	host, _, _ := net.SplitHostPort(addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if a != nil {
+		if err := c.Auth(a); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,148 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testAlert = Alert{
+	Rule:      "ton above 7",
+	Kind:      PriceAbove,
+	Subject:   tonAddress,
+	Value:     7.2,
+	Threshold: 7,
+	Message:   "TON is at $7.2, above $7",
+	At:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestWriter(t *testing.T) {
+	var out strings.Builder
+	assert.NoError(t, NewWriter(&out).Notify(context.Background(), testAlert))
+	assert.Equal(t, "2024-01-01T00:00:00Z [ton above 7] TON is at $7.2, above $7\n", out.String())
+}
+
+func TestWebhook(t *testing.T) {
+	var got Alert
+	var token string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		if got.Rule == "fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	n := &Webhook{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer secret"}}
+	assert.NoError(t, n.Notify(context.Background(), testAlert))
+	assert.Equal(t, testAlert, got)
+	assert.Equal(t, "Bearer secret", token)
+
+	failing := testAlert
+	failing.Rule = "fail"
+	assert.ErrorContains(t, n.Notify(context.Background(), failing), "502")
+}
+
+func TestSMTP(t *testing.T) {
+	n := NewSMTP("smtp.example.com:587", "alerts", "secret", "alerts@example.com", "desk@example.com", "ops@example.com")
+	assert.NotNil(t, n.Auth)
+	var addr string
+	var to []string
+	var msg string
+	n.send = func(_ context.Context, a string, _ smtp.Auth, _ string, recipients []string, m []byte) error {
+		addr, to, msg = a, recipients, string(m)
+		return nil
+	}
+	assert.NoError(t, n.Notify(context.Background(), testAlert))
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.Equal(t, []string{"desk@example.com", "ops@example.com"}, to)
+	assert.Contains(t, msg, "Subject: [stonfi] ton above 7\r\n")
+	assert.Contains(t, msg, "To: desk@example.com, ops@example.com\r\n")
+	assert.True(t, strings.HasSuffix(msg, "\r\n\r\nTON is at $7.2, above $7\r\n"))
+}
+
+func TestSMTPHonoursContext(t *testing.T) {
+	// The server accepts connections but never greets.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	n := NewSMTP(l.Addr().String(), "", "", "alerts@example.com", "desk@example.com")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.ErrorIs(t, n.Notify(ctx, testAlert), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	n.Timeout = 50 * time.Millisecond
+	assert.ErrorIs(t, n.Notify(context.Background(), testAlert), context.DeadlineExceeded, "Timeout applies without a deadline")
+}
+
+func TestSendMail(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+	var commands []string
+	var data strings.Builder
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 localhost ready")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			commands = append(commands, strings.Fields(line)[0])
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				tp.PrintfLine("250 localhost")
+			case line == "DATA":
+				tp.PrintfLine("354 go ahead")
+				lines, _ := tp.ReadDotLines()
+				data.WriteString(strings.Join(lines, "\n"))
+				tp.PrintfLine("250 queued")
+			case line == "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("250 ok")
+			}
+		}
+	}()
+
+	n := NewSMTP(l.Addr().String(), "", "", "alerts@example.com", "desk@example.com")
+	assert.NoError(t, n.Notify(context.Background(), testAlert))
+	<-done
+	assert.Equal(t, []string{"EHLO", "MAIL", "RCPT", "DATA", "QUIT"}, commands)
+	assert.Contains(t, data.String(), "TON is at $7.2, above $7")
+}
@@ -0,0 +1,184 @@
+// Package alerts evaluates alert rules against polled API data and sends
+// what fires to notifiers. Rules cover a price crossing a level, a pool's
+// reserves dropping, a farm's remaining rewards running low and a wallet
+// making a swap. Threshold rules have hysteresis and cooldowns, so a value
+// hovering around a level does not page on every poll.
+package alerts
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is what a rule watches.
+type Kind string
+
+const (
+	// PriceAbove fires when an asset's USD price rises to Threshold or above.
+	PriceAbove Kind = "price_above"
+	// PriceBelow fires when an asset's USD price falls to Threshold or below.
+	PriceBelow Kind = "price_below"
+	// ReserveDrop fires when either reserve of a pool falls by the fraction
+	// Threshold from its highest value within Window.
+	ReserveDrop Kind = "reserve_drop"
+	// FarmRewardsBelow fires when a farm's remaining rewards fall to
+	// Threshold whole tokens or below.
+	FarmRewardsBelow Kind = "farm_rewards_below"
+	// WalletSwap fires for each swap a wallet makes.
+	WalletSwap Kind = "wallet_swap"
+)
+
+// Rule is an alert rule.
+type Rule struct {
+	Name string `yaml:"name"`
+	Kind Kind   `yaml:"kind"`
+	// Asset is the asset of price rules. For FarmRewardsBelow it is the
+	// reward token to watch; empty watches the stream with the least left.
+	Asset  string `yaml:"asset"`
+	Pool   string `yaml:"pool"`
+	Farm   string `yaml:"farm"`
+	Wallet string `yaml:"wallet"`
+	// Threshold is a USD price, a fraction of the reserves (0.1 is 10%) or
+	// an amount of reward tokens, depending on Kind.
+	Threshold float64 `yaml:"threshold"`
+	// Hysteresis is how far, as a fraction of Threshold, the value must move
+	// back past it before the rule can fire again.
+	Hysteresis float64 `yaml:"hysteresis"`
+	// Cooldown is the least time between two alerts of the rule.
+	Cooldown time.Duration `yaml:"cooldown"`
+	// Window is how far back ReserveDrop looks for the highest reserves.
+	// Defaults to 1 hour.
+	Window time.Duration `yaml:"window"`
+}
+
+// Validate reports whether the rule is complete.
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule without a name")
+	}
+	require := func(field, value string) error {
+		if value == "" {
+			return fmt.Errorf("rule %q: %s rules need %s", r.Name, r.Kind, field)
+		}
+		return nil
+	}
+	var err error
+	switch r.Kind {
+	case PriceAbove, PriceBelow:
+		err = require("asset", r.Asset)
+	case ReserveDrop:
+		err = require("pool", r.Pool)
+		if err == nil && (r.Threshold <= 0 || r.Threshold >= 1) {
+			err = fmt.Errorf("rule %q: reserve_drop threshold must be between 0 and 1", r.Name)
+		}
+	case FarmRewardsBelow:
+		err = require("farm", r.Farm)
+	case WalletSwap:
+		err = require("wallet", r.Wallet)
+	default:
+		err = fmt.Errorf("rule %q: unknown kind %q", r.Name, r.Kind)
+	}
+	if err != nil {
+		return err
+	}
+	if r.Kind != WalletSwap && r.Threshold <= 0 {
+		return fmt.Errorf("rule %q: threshold must be positive", r.Name)
+	}
+	if r.Hysteresis < 0 || r.Hysteresis >= 1 {
+		return fmt.Errorf("rule %q: hysteresis must be in [0, 1)", r.Name)
+	}
+	if r.Cooldown < 0 || r.Window < 0 {
+		return fmt.Errorf("rule %q: durations must not be negative", r.Name)
+	}
+	return nil
+}
+
+// Config is a rules file.
+type Config struct {
+	// Interval is the time between polls. Defaults to 1 minute.
+	Interval  time.Duration   `yaml:"interval"`
+	Notifiers NotifiersConfig `yaml:"notifiers"`
+	Rules     []Rule          `yaml:"rules"`
+}
+
+// NotifiersConfig selects where alerts are sent.
+type NotifiersConfig struct {
+	Stdout  bool           `yaml:"stdout"`
+	Webhook *WebhookConfig `yaml:"webhook"`
+	SMTP    *SMTPConfig    `yaml:"smtp"`
+}
+
+// WebhookConfig configures a Webhook.
+type WebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// SMTPConfig configures an SMTP notifier. Password may reference
+// environment variables, as in "${SMTP_PASSWORD}".
+type SMTPConfig struct {
+	Addr     string   `yaml:"addr"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// Load reads and validates a rules file.
+func Load(r io.Reader) (*Config, error) {
+	var config Config
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("alerts: decoding rules: %w", err)
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	names := map[string]bool{}
+	for _, rule := range config.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("alerts: %w", err)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("alerts: duplicate rule %q", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	return &config, nil
+}
+
+// LoadFile reads and validates the rules file at path.
+func LoadFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("alerts: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Build returns the notifiers the config selects, stdout writing to w.
+func (c NotifiersConfig) Build(w io.Writer) ([]Notifier, error) {
+	var notifiers []Notifier
+	if c.Stdout {
+		notifiers = append(notifiers, NewWriter(w))
+	}
+	if c.Webhook != nil {
+		if c.Webhook.URL == "" {
+			return nil, fmt.Errorf("alerts: webhook without a url")
+		}
+		notifiers = append(notifiers, &Webhook{URL: c.Webhook.URL, Headers: c.Webhook.Headers})
+	}
+	if c.SMTP != nil {
+		if c.SMTP.Addr == "" || c.SMTP.From == "" || len(c.SMTP.To) == 0 {
+			return nil, fmt.Errorf("alerts: smtp needs addr, from and to")
+		}
+		notifiers = append(notifiers, NewSMTP(c.SMTP.Addr, c.SMTP.Username, os.ExpandEnv(c.SMTP.Password), c.SMTP.From, c.SMTP.To...))
+	}
+	return notifiers, nil
+}
@@ -0,0 +1,75 @@
+package alerts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rulesFile = `
+interval: 30s
+notifiers:
+  stdout: true
+  webhook:
+    url: https://hooks.example.com/stonfi
+  smtp:
+    addr: smtp.example.com:587
+    username: alerts
+    password: ${SMTP_PASSWORD}
+    from: alerts@example.com
+    to: [desk@example.com]
+rules:
+  - name: ton above 7
+    kind: price_above
+    asset: EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c
+    threshold: 7
+    hysteresis: 0.02
+    cooldown: 30m
+  - name: pool drain
+    kind: reserve_drop
+    pool: EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE
+    threshold: 0.1
+    window: 2h
+  - name: whale
+    kind: wallet_swap
+    wallet: UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF
+`
+
+func TestLoad(t *testing.T) {
+	config, err := Load(strings.NewReader(rulesFile))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 30*time.Second, config.Interval)
+	if assert.Len(t, config.Rules, 3) {
+		assert.Equal(t, Rule{Name: "ton above 7", Kind: PriceAbove, Asset: tonAddress, Threshold: 7, Hysteresis: 0.02, Cooldown: 30 * time.Minute}, config.Rules[0])
+		assert.Equal(t, 2*time.Hour, config.Rules[1].Window)
+	}
+
+	t.Setenv("SMTP_PASSWORD", "secret")
+	notifiers, err := config.Notifiers.Build(&strings.Builder{})
+	assert.NoError(t, err)
+	assert.Len(t, notifiers, 3)
+
+	config, err = Load(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, config.Interval)
+}
+
+func TestLoadErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		"rules: [{name: a, kind: price_above, threshold: 1}]":                                       "need asset",
+		"rules: [{name: a, kind: reserve_drop, pool: p, threshold: 10}]":                            "between 0 and 1",
+		"rules: [{name: a, kind: price_below, asset: x}]":                                           "threshold must be positive",
+		"rules: [{name: a, kind: volume, asset: x}]":                                                "unknown kind",
+		"rules: [{kind: wallet_swap, wallet: w}]":                                                   "without a name",
+		"rules: [{name: a, kind: wallet_swap, wallet: w, cooldown: soon}]":                          "decoding rules",
+		"rules: [{name: a, kind: wallet_swap, wallet: w, threshold_usd: 5}]":                        "decoding rules",
+		"rules: [{name: a, kind: wallet_swap, wallet: w}, {name: a, kind: wallet_swap, wallet: v}]": "duplicate rule",
+	} {
+		_, err := Load(strings.NewReader(input))
+		assert.ErrorContains(t, err, want, input)
+	}
+}
@@ -0,0 +1,210 @@
+// Package amm implements the constant-product swap math used by Ston.fi pools,
+// so that swaps can be simulated locally from a types.Pool.
+package amm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// FeeDivider is the denominator of the pool fee fields, which are in basis points.
+const FeeDivider = 10000
+
+var (
+	ErrAssetNotInPool        = errors.New("asset is not in pool")
+	ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+)
+
+// Quote is the outcome of a simulated swap.
+type Quote struct {
+	OfferUnits *big.Int
+	AskUnits   *big.Int
+	// LpFeeUnits stays in the pool; it is charged in the offered asset.
+	LpFeeUnits *big.Int
+	// ProtocolFeeUnits and RefFeeUnits are charged in the asked asset.
+	ProtocolFeeUnits *big.Int
+	RefFeeUnits      *big.Int
+	// PriceImpact is the relative difference between the pool's spot price and
+	// the effective price of the swap, in [0, 1).
+	PriceImpact float64
+}
+
+// Side holds a pool's reserves oriented for a swap from offer to ask.
+type Side struct {
+	OfferReserve *big.Int
+	AskReserve   *big.Int
+	LpFee        int64
+	ProtocolFee  int64
+	RefFee       int64
+}
+
+// Orient returns pool's reserves and fees for a swap that offers offerAddress.
+func Orient(pool types.Pool, offerAddress string) (*Side, error) {
+	reserve0, err := parseUnits(pool.Reserve0)
+	if err != nil {
+		return nil, fmt.Errorf("reserve0: %w", err)
+	}
+	reserve1, err := parseUnits(pool.Reserve1)
+	if err != nil {
+		return nil, fmt.Errorf("reserve1: %w", err)
+	}
+	side := &Side{}
+	switch {
+	case utils.SameAddress(offerAddress, pool.Token0Address):
+		side.OfferReserve, side.AskReserve = reserve0, reserve1
+	case utils.SameAddress(offerAddress, pool.Token1Address):
+		side.OfferReserve, side.AskReserve = reserve1, reserve0
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrAssetNotInPool, offerAddress)
+	}
+	for _, f := range []struct {
+		dst  *int64
+		src  string
+		name string
+	}{{&side.LpFee, pool.LpFee, "lp_fee"}, {&side.ProtocolFee, pool.ProtocolFee, "protocol_fee"}, {&side.RefFee, pool.RefFee, "ref_fee"}} {
+		if f.src == "" {
+			continue
+		}
+		if *f.dst, err = strconv.ParseInt(f.src, 10, 64); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	return side, nil
+}
+
+// SimulateSwap computes the output of offering offerUnits of offerAddress to pool.
+// The referral fee is only charged when withReferral is set.
+func SimulateSwap(pool types.Pool, offerAddress string, offerUnits *big.Int, withReferral bool) (*Quote, error) {
+	side, err := Orient(pool, offerAddress)
+	if err != nil {
+		return nil, err
+	}
+	return side.Swap(offerUnits, withReferral)
+}
+
+// SimulateReverseSwap computes the offer needed to receive askUnits of the asset
+// other than offerAddress from pool.
+func SimulateReverseSwap(pool types.Pool, offerAddress string, askUnits *big.Int, withReferral bool) (*Quote, error) {
+	side, err := Orient(pool, offerAddress)
+	if err != nil {
+		return nil, err
+	}
+	return side.ReverseSwap(askUnits, withReferral)
+}
+
+// Swap computes the output of offering offerUnits.
+func (s *Side) Swap(offerUnits *big.Int, withReferral bool) (*Quote, error) {
+	if offerUnits.Sign() <= 0 {
+		return nil, errors.New("offer units must be positive")
+	}
+	if s.OfferReserve.Sign() <= 0 || s.AskReserve.Sign() <= 0 {
+		return nil, ErrInsufficientLiquidity
+	}
+	// amountInWithFee = offer * (FeeDivider - lpFee)
+	// out = amountInWithFee * askReserve / (offerReserve * FeeDivider + amountInWithFee)
+	inWithFee := new(big.Int).Mul(offerUnits, big.NewInt(FeeDivider-s.LpFee))
+	num := new(big.Int).Mul(inWithFee, s.AskReserve)
+	den := new(big.Int).Mul(s.OfferReserve, big.NewInt(FeeDivider))
+	den.Add(den, inWithFee)
+	out := num.Quo(num, den)
+
+	q := &Quote{
+		OfferUnits:       new(big.Int).Set(offerUnits),
+		LpFeeUnits:       feeOf(offerUnits, s.LpFee),
+		ProtocolFeeUnits: feeOf(out, s.ProtocolFee),
+		RefFeeUnits:      new(big.Int),
+	}
+	if withReferral {
+		q.RefFeeUnits = feeOf(out, s.RefFee)
+	}
+	out.Sub(out, q.ProtocolFeeUnits)
+	out.Sub(out, q.RefFeeUnits)
+	q.AskUnits = out
+	q.PriceImpact = s.priceImpact(offerUnits, out)
+	return q, nil
+}
+
+// ReverseSwap computes the smallest offer that yields at least askUnits.
+func (s *Side) ReverseSwap(askUnits *big.Int, withReferral bool) (*Quote, error) {
+	if askUnits.Sign() <= 0 {
+		return nil, errors.New("ask units must be positive")
+	}
+	feeBps := s.ProtocolFee
+	if withReferral {
+		feeBps += s.RefFee
+	}
+	// Gross output before the protocol and referral fees are taken from it.
+	gross := ceilDiv(new(big.Int).Mul(askUnits, big.NewInt(FeeDivider)), big.NewInt(FeeDivider-feeBps))
+	if gross.Cmp(s.AskReserve) >= 0 {
+		return nil, ErrInsufficientLiquidity
+	}
+	// offer = offerReserve * gross * FeeDivider / ((askReserve - gross) * (FeeDivider - lpFee))
+	num := new(big.Int).Mul(s.OfferReserve, gross)
+	num.Mul(num, big.NewInt(FeeDivider))
+	den := new(big.Int).Sub(s.AskReserve, gross)
+	den.Mul(den, big.NewInt(FeeDivider-s.LpFee))
+	offer := ceilDiv(num, den)
+
+	// Fees are rounded down, so the estimate can be a few units off either way;
+	// settle on the smallest offer whose forward quote covers askUnits.
+	one := big.NewInt(1)
+	q, err := s.Swap(offer, withReferral)
+	for err == nil && q.AskUnits.Cmp(askUnits) < 0 {
+		offer.Add(offer, one)
+		q, err = s.Swap(offer, withReferral)
+	}
+	for err == nil && offer.Cmp(one) > 0 {
+		lower, lowerErr := s.Swap(new(big.Int).Sub(offer, one), withReferral)
+		if lowerErr != nil || lower.AskUnits.Cmp(askUnits) < 0 {
+			break
+		}
+		offer.Sub(offer, one)
+		q = lower
+	}
+	return q, err
+}
+
+// SpotPrice is the marginal price of one offer unit in ask units, before fees.
+func (s *Side) SpotPrice() *big.Rat {
+	if s.OfferReserve.Sign() == 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(s.AskReserve, s.OfferReserve)
+}
+
+func (s *Side) priceImpact(offer, out *big.Int) float64 {
+	spot := s.SpotPrice()
+	if spot.Sign() == 0 {
+		return 0
+	}
+	effective := new(big.Rat).SetFrac(out, offer)
+	impact := new(big.Rat).Quo(new(big.Rat).Sub(spot, effective), spot)
+	f, _ := impact.Float64()
+	return f
+}
+
+func feeOf(units *big.Int, bps int64) *big.Int {
+	fee := new(big.Int).Mul(units, big.NewInt(bps))
+	return fee.Quo(fee, big.NewInt(FeeDivider))
+}
+
+func ceilDiv(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+func parseUnits(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid units %q", s)
+	}
+	return n, nil
+}
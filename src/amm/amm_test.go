@@ -0,0 +1,46 @@
+package amm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPool = types.Pool{
+	Address:       "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE",
+	Token0Address: "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c",
+	Token1Address: "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs",
+	Reserve0:      "1000000",
+	Reserve1:      "2000000",
+	LpFee:         "20",
+	ProtocolFee:   "10",
+	RefFee:        "10",
+}
+
+func TestSimulateSwap(t *testing.T) {
+	q, err := SimulateSwap(testPool, testPool.Token0Address, big.NewInt(1000), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "1993", q.AskUnits.String())
+	assert.Equal(t, "1", q.ProtocolFeeUnits.String())
+	assert.Equal(t, "2", q.LpFeeUnits.String())
+	assert.InDelta(t, 0.0035, q.PriceImpact, 0.0001)
+
+	q, err = SimulateSwap(testPool, "UQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_p0p", big.NewInt(2000), true)
+	assert.NoError(t, err)
+	assert.Equal(t, "997", q.AskUnits.String())
+
+	_, err = SimulateSwap(testPool, "EQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwiuA", big.NewInt(1), false)
+	assert.ErrorIs(t, err, ErrAssetNotInPool)
+}
+
+func TestSimulateReverseSwap(t *testing.T) {
+	q, err := SimulateReverseSwap(testPool, testPool.Token0Address, big.NewInt(1993), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "1000", q.OfferUnits.String())
+	assert.Equal(t, "1993", q.AskUnits.String())
+
+	_, err = SimulateReverseSwap(testPool, testPool.Token0Address, big.NewInt(2000000), false)
+	assert.ErrorIs(t, err, ErrInsufficientLiquidity)
+}
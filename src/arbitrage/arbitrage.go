@@ -0,0 +1,265 @@
+// Package arbitrage finds mispricings between Ston.fi pools: cycles of swaps,
+// such as TON→A→B→TON, that return more than they take after fees, and pools
+// of the same pair that disagree on its price. It only reports what it finds;
+// it never trades.
+package arbitrage
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Options configures detection.
+type Options struct {
+	// MaxHops is the longest cycle searched. Defaults to 3.
+	MaxHops int
+	// Start restricts cycles to those through these assets, which they then
+	// begin and end with. By default every cycle is searched.
+	Start []string
+	// MinProfitUsd is the smallest expected profit reported. Cycles whose
+	// start asset has no USD price are reported if they are profitable at all.
+	MinProfitUsd float64
+	// IncludeDeprecated also searches deprecated pools.
+	IncludeDeprecated bool
+}
+
+// Hop is one swap of an opportunity.
+type Hop struct {
+	PoolAddress   string
+	RouterAddress string
+	OfferAddress  string
+	AskAddress    string
+	OfferUnits    *big.Int
+	AskUnits      *big.Int
+	PriceImpact   float64
+}
+
+// Opportunity is a profitable cycle, sized to maximize profit given the
+// reserves of its pools.
+type Opportunity struct {
+	Hops []Hop
+	// MarginalRate is the product of the marginal rates of the hops after
+	// fees; the cycle is profitable for small sizes when it exceeds 1.
+	MarginalRate float64
+	// InputUnits is the optimal amount of the start asset to offer, and
+	// OutputUnits what the cycle returns for it.
+	InputUnits  *big.Int
+	OutputUnits *big.Int
+	ProfitUnits *big.Int
+	// ProfitUsd is ProfitUnits at the start asset's DEX price, or 0 if it has
+	// none.
+	ProfitUsd float64
+	// PriceImpact is the combined price impact of the hops at the optimal size.
+	PriceImpact float64
+	// CrossRouter is set for two-hop cycles between pools of the same pair on
+	// different routers.
+	CrossRouter bool
+}
+
+// Start returns the raw address of the asset the opportunity begins and
+// ends with.
+func (o Opportunity) Start() string {
+	return o.Hops[0].OfferAddress
+}
+
+// Detect fetches every pool and asset and returns the opportunities in them.
+func Detect(ctx context.Context, c *client.StonfiClient, opts Options) ([]Opportunity, error) {
+	pools, err := c.GetPools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("arbitrage: fetching pools: %w", err)
+	}
+	assets, err := c.GetAssets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("arbitrage: fetching assets: %w", err)
+	}
+	return Find(pools.PoolList, assets.AssetList, opts), nil
+}
+
+// Find returns the opportunities in pools, most profitable first. assets
+// provides USD prices and decimals to value profits.
+func Find(pools []types.Pool, assets []types.Asset, opts Options) []Opportunity {
+	if opts.MaxHops < 2 {
+		opts.MaxHops = 3
+	}
+	byAddress := map[string]types.Asset{}
+	for _, a := range assets {
+		byAddress[utils.AddressKey(a.ContractAddress)] = a
+	}
+
+	var found []Opportunity
+	NewGraph(pools, opts.IncludeDeprecated).Cycles(opts.MaxHops, opts.Start, func(cycle []*Edge) bool {
+		rate := 1.0
+		for _, e := range cycle {
+			rate *= e.Rate
+		}
+		// Integer rounding only loses, so a cycle without a marginal profit
+		// has none at any size.
+		if rate <= 1 {
+			return true
+		}
+		o, ok := size(cycle)
+		if !ok {
+			return true
+		}
+		o.MarginalRate = rate
+		if a, ok := byAddress[o.Start()]; ok {
+			o.ProfitUsd = utils.ToUnits(o.ProfitUnits, a.Decimals) * utils.ParseFloat(a.DexPriceUsd)
+		}
+		if o.ProfitUsd > 0 && o.ProfitUsd < opts.MinProfitUsd {
+			return true
+		}
+		found = append(found, o)
+		return true
+	})
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].ProfitUsd != found[j].ProfitUsd {
+			return found[i].ProfitUsd > found[j].ProfitUsd
+		}
+		return found[i].MarginalRate > found[j].MarginalRate
+	})
+	return found
+}
+
+// size finds the input that maximizes the profit of cycle. Profit is concave
+// in the input for constant-product pools, so a ternary search over
+// [1, reserve of the first pool] finds it.
+func size(cycle []*Edge) (Opportunity, bool) {
+	profit := func(x *big.Int) *big.Int {
+		quotes := simulate(cycle, x)
+		if quotes == nil {
+			return nil
+		}
+		return new(big.Int).Sub(quotes[len(quotes)-1].AskUnits, x)
+	}
+	better := func(a, b *big.Int) bool {
+		pa, pb := profit(a), profit(b)
+		return pb == nil || (pa != nil && pa.Cmp(pb) >= 0)
+	}
+
+	lo, hi := big.NewInt(1), new(big.Int).Set(cycle[0].Side.OfferReserve)
+	three := big.NewInt(3)
+	for new(big.Int).Sub(hi, lo).Cmp(three) > 0 {
+		third := new(big.Int).Quo(new(big.Int).Sub(hi, lo), three)
+		m1 := new(big.Int).Add(lo, third)
+		m2 := new(big.Int).Sub(hi, third)
+		if better(m1, m2) {
+			hi = m2
+		} else {
+			lo = m1
+		}
+	}
+	best, bestProfit := (*big.Int)(nil), (*big.Int)(nil)
+	for x := new(big.Int).Set(lo); x.Cmp(hi) <= 0; x.Add(x, big.NewInt(1)) {
+		if p := profit(x); p != nil && (bestProfit == nil || p.Cmp(bestProfit) > 0) {
+			best, bestProfit = new(big.Int).Set(x), p
+		}
+	}
+	if bestProfit == nil || bestProfit.Sign() <= 0 {
+		return Opportunity{}, false
+	}
+
+	quotes := simulate(cycle, best)
+	o := Opportunity{
+		InputUnits:  best,
+		OutputUnits: quotes[len(quotes)-1].AskUnits,
+		ProfitUnits: bestProfit,
+	}
+	kept := 1.0
+	for i, e := range cycle {
+		o.Hops = append(o.Hops, Hop{
+			PoolAddress:   e.Pool.Address,
+			RouterAddress: e.Pool.RouterAddress,
+			OfferAddress:  e.Offer,
+			AskAddress:    e.Ask,
+			OfferUnits:    quotes[i].OfferUnits,
+			AskUnits:      quotes[i].AskUnits,
+			PriceImpact:   quotes[i].PriceImpact,
+		})
+		kept *= 1 - quotes[i].PriceImpact
+	}
+	o.PriceImpact = 1 - kept
+	o.CrossRouter = len(cycle) == 2 && cycle[0].Pool.RouterAddress != cycle[1].Pool.RouterAddress
+	return o, true
+}
+
+// Discrepancy is a pair of pools of the same assets whose spot prices differ.
+type Discrepancy struct {
+	Asset0 string // raw address
+	Asset1 string // raw address
+	// Low and High are the pools with the lowest and highest price of
+	// Asset0 in Asset1.
+	Low, High types.Pool
+	LowPrice  float64
+	HighPrice float64
+	// Spread is HighPrice relative to LowPrice, minus one.
+	Spread float64
+	// FeeSpread is the spread a round trip needs to beat the fees of both
+	// pools; a larger Spread is an opportunity.
+	FeeSpread   float64
+	CrossRouter bool
+}
+
+// Discrepancies returns, for each pair with pools that disagree on its price
+// by at least minSpread, the pools with the lowest and highest prices. They
+// are sorted by decreasing spread.
+func Discrepancies(pools []types.Pool, minSpread float64, includeDeprecated bool) []Discrepancy {
+	g := NewGraph(pools, includeDeprecated)
+	var found []Discrepancy
+	for _, asset0 := range g.Assets() {
+		byPair := map[string][]*Edge{}
+		for _, e := range g.edges[asset0] {
+			if e.Ask > asset0 {
+				byPair[e.Ask] = append(byPair[e.Ask], e)
+			}
+		}
+		for asset1, edges := range byPair {
+			if len(edges) < 2 {
+				continue
+			}
+			low, high := edges[0], edges[0]
+			for _, e := range edges[1:] {
+				if spot(e) < spot(low) {
+					low = e
+				}
+				if spot(e) > spot(high) {
+					high = e
+				}
+			}
+			d := Discrepancy{
+				Asset0:      asset0,
+				Asset1:      asset1,
+				Low:         low.Pool,
+				High:        high.Pool,
+				LowPrice:    spot(low),
+				HighPrice:   spot(high),
+				CrossRouter: low.Pool.RouterAddress != high.Pool.RouterAddress,
+			}
+			d.Spread = d.HighPrice/d.LowPrice - 1
+			// Buying asset0 in the low pool and selling it in the high one pays
+			// the fees of both.
+			d.FeeSpread = 1/(feeFactor(low.Side)*feeFactor(high.Side)) - 1
+			if d.Spread >= minSpread {
+				found = append(found, d)
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Spread != found[j].Spread {
+			return found[i].Spread > found[j].Spread
+		}
+		return found[i].Low.Address < found[j].Low.Address
+	})
+	return found
+}
+
+// spot is the price of e's offer asset in its ask asset, before fees.
+func spot(e *Edge) float64 {
+	f, _ := e.Side.SpotPrice().Float64()
+	return f
+}
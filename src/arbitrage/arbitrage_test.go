@@ -0,0 +1,147 @@
+package arbitrage
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	xAddress    = "0:1111111111111111111111111111111111111111111111111111111111111111"
+	tonRaw      = "0:0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+var assets = []types.Asset{
+	{ContractAddress: tonAddress, Symbol: "TON", Decimals: 9, DexPriceUsd: "5"},
+	{ContractAddress: usdtAddress, Symbol: "USDT", Decimals: 6, DexPriceUsd: "1"},
+	{ContractAddress: xAddress, Symbol: "X", Decimals: 6, DexPriceUsd: "1"},
+}
+
+func pool(address, router, token0, token1, reserve0, reserve1 string) types.Pool {
+	return types.Pool{
+		Address:       address,
+		RouterAddress: router,
+		Token0Address: token0,
+		Token1Address: token1,
+		Reserve0:      reserve0,
+		Reserve1:      reserve1,
+		LpFee:         "20",
+		ProtocolFee:   "10",
+	}
+}
+
+func profitAt(o Opportunity, pools []types.Pool, x *big.Int) *big.Int {
+	g := NewGraph(pools, false)
+	var path []*Edge
+	for _, h := range o.Hops {
+		for _, e := range g.Edges(h.OfferAddress) {
+			if e.Pool.Address == h.PoolAddress {
+				path = append(path, e)
+			}
+		}
+	}
+	quotes := simulate(path, x)
+	return new(big.Int).Sub(quotes[len(quotes)-1].AskUnits, x)
+}
+
+func TestCrossRouter(t *testing.T) {
+	pools := []types.Pool{
+		pool("pool-a", "router-v1", tonAddress, usdtAddress, "1000000000000", "5000000000"), // 5 USDT per TON
+		pool("pool-b", "router-v2", tonAddress, usdtAddress, "1000000000000", "5500000000"), // 5.5 USDT per TON
+	}
+	found := Find(pools, assets, Options{})
+	if !assert.Len(t, found, 1) {
+		return
+	}
+	o := found[0]
+	assert.Equal(t, tonRaw, o.Start())
+	assert.Equal(t, []string{"pool-b", "pool-a"}, []string{o.Hops[0].PoolAddress, o.Hops[1].PoolAddress}, "sell TON where it is dear, buy it back where it is cheap")
+	assert.True(t, o.CrossRouter)
+	assert.Greater(t, o.MarginalRate, 1.0)
+	assert.Positive(t, o.ProfitUnits.Sign())
+	assert.Equal(t, new(big.Int).Sub(o.OutputUnits, o.InputUnits), o.ProfitUnits)
+	assert.InDelta(t, float64(o.ProfitUnits.Int64())/1e9*5, o.ProfitUsd, 1e-9)
+	assert.Greater(t, o.PriceImpact, 0.0)
+
+	// The size is optimal: offering 10% more or less makes less.
+	tenth := new(big.Int).Quo(o.InputUnits, big.NewInt(10))
+	assert.Negative(t, profitAt(o, pools, new(big.Int).Sub(o.InputUnits, tenth)).Cmp(o.ProfitUnits))
+	assert.Negative(t, profitAt(o, pools, new(big.Int).Add(o.InputUnits, tenth)).Cmp(o.ProfitUnits))
+
+	// Restricting to USDT rotates the same cycle.
+	found = Find(pools, assets, Options{Start: []string{usdtAddress}})
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, "pool-a", found[0].Hops[0].PoolAddress)
+	}
+
+	assert.Empty(t, Find(pools, assets, Options{MinProfitUsd: 1e9}))
+}
+
+func TestNoOpportunityWithinFees(t *testing.T) {
+	pools := []types.Pool{
+		pool("pool-a", "router-v1", tonAddress, usdtAddress, "1000000000000", "5000000000"),
+		pool("pool-b", "router-v1", usdtAddress, tonAddress, "5010000000", "1000000000000"), // 0.2% apart
+	}
+	assert.Empty(t, Find(pools, assets, Options{}))
+}
+
+func TestTriangle(t *testing.T) {
+	pools := []types.Pool{
+		pool("ton-usdt", "router", tonAddress, usdtAddress, "1000000000000", "5000000000"), // 5 USDT per TON
+		pool("usdt-x", "router", usdtAddress, xAddress, "5000000000", "5000000000"),        // 1 X per USDT
+		pool("ton-x", "router", tonAddress, xAddress, "1000000000000", "6000000000"),       // 6 X per TON
+	}
+	found := Find(pools, assets, Options{Start: []string{tonAddress}})
+	if !assert.Len(t, found, 1) {
+		return
+	}
+	o := found[0]
+	assert.Equal(t, []string{"ton-x", "usdt-x", "ton-usdt"}, []string{o.Hops[0].PoolAddress, o.Hops[1].PoolAddress, o.Hops[2].PoolAddress})
+	assert.InDelta(t, 1.2*math.Pow(0.998*0.999, 3), o.MarginalRate, 1e-9)
+	assert.False(t, o.CrossRouter)
+
+	assert.Empty(t, Find(pools, assets, Options{MaxHops: 2}))
+}
+
+func TestCycles(t *testing.T) {
+	pools := []types.Pool{
+		pool("ton-usdt", "router", tonAddress, usdtAddress, "1", "1"),
+		pool("usdt-x", "router", usdtAddress, xAddress, "1", "1"),
+		pool("ton-x", "router", tonAddress, xAddress, "1", "1"),
+		pool("ton-usdt-2", "router", tonAddress, usdtAddress, "1", "1"),
+		pool("empty", "router", tonAddress, xAddress, "", ""),
+	}
+	count := 0
+	NewGraph(pools, false).Cycles(3, nil, func([]*Edge) bool {
+		count++
+		return true
+	})
+	// Two directions of TON/USDT between its two pools, and two directions
+	// of each triangle, one through each TON/USDT pool.
+	assert.Equal(t, 2+4, count)
+}
+
+func TestDiscrepancies(t *testing.T) {
+	pools := []types.Pool{
+		pool("pool-a", "router-v1", tonAddress, usdtAddress, "1000000000000", "5000000000"),
+		pool("pool-b", "router-v2", tonAddress, usdtAddress, "1000000000000", "5500000000"),
+		pool("pool-c", "router-v1", tonAddress, xAddress, "1000000000000", "5000000000"),
+	}
+	found := Discrepancies(pools, 0.01, false)
+	if !assert.Len(t, found, 1) {
+		return
+	}
+	d := found[0]
+	assert.Equal(t, "pool-a", d.Low.Address)
+	assert.Equal(t, "pool-b", d.High.Address)
+	assert.InDelta(t, 0.1, d.Spread, 1e-9)
+	assert.InDelta(t, 1/math.Pow(0.998*0.999, 2)-1, d.FeeSpread, 1e-9)
+	assert.True(t, d.CrossRouter)
+
+	assert.Empty(t, Discrepancies(pools, 0.2, false))
+}
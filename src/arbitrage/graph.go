@@ -0,0 +1,165 @@
+package arbitrage
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/itay747/go-stonfi/src/amm"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Edge is a swap through a pool, from Offer to Ask.
+type Edge struct {
+	Pool  types.Pool
+	Offer string // raw address
+	Ask   string // raw address
+	Side  *amm.Side
+	// Rate is the marginal rate in ask units per offer unit, after fees.
+	Rate float64
+}
+
+// Graph is the directed graph of swaps between assets.
+type Graph struct {
+	// edges holds the edges out of each asset, by raw address.
+	edges map[string][]*Edge
+	// assets is every asset with an edge, sorted.
+	assets []string
+}
+
+// NewGraph builds the graph of pools. Deprecated pools are skipped unless
+// includeDeprecated is set, as are pools without reserves on both sides.
+func NewGraph(pools []types.Pool, includeDeprecated bool) *Graph {
+	g := &Graph{edges: map[string][]*Edge{}}
+	for _, pool := range pools {
+		if pool.Deprecated && !includeDeprecated {
+			continue
+		}
+		token0, token1 := utils.AddressKey(pool.Token0Address), utils.AddressKey(pool.Token1Address)
+		if token0 == token1 {
+			continue
+		}
+		for _, dir := range [][2]string{{token0, token1}, {token1, token0}} {
+			// The API leaves the reserves of some pools empty; they cannot be
+			// swapped through.
+			side, err := amm.Orient(pool, dir[0])
+			if err != nil || side.OfferReserve.Sign() <= 0 || side.AskReserve.Sign() <= 0 {
+				continue
+			}
+			e := &Edge{Pool: pool, Offer: dir[0], Ask: dir[1], Side: side, Rate: marginalRate(side)}
+			g.edges[e.Offer] = append(g.edges[e.Offer], e)
+		}
+	}
+	for asset, edges := range g.edges {
+		g.assets = append(g.assets, asset)
+		sort.Slice(edges, func(i, j int) bool { return edges[i].Pool.Address < edges[j].Pool.Address })
+	}
+	sort.Strings(g.assets)
+	return g
+}
+
+// Edges returns the edges out of asset.
+func (g *Graph) Edges(asset string) []*Edge {
+	return g.edges[utils.AddressKey(asset)]
+}
+
+// Assets returns the raw addresses of every asset that can be swapped.
+func (g *Graph) Assets() []string {
+	return g.assets
+}
+
+// Cycles yields the cycles of 2 to maxHops edges through distinct pools and
+// distinct assets, each once. If start is not empty, only cycles through one
+// of its assets are yielded, rotated to begin there. yield returning false
+// stops the search.
+func (g *Graph) Cycles(maxHops int, start []string, yield func([]*Edge) bool) {
+	starts := map[string]bool{}
+	for _, s := range start {
+		starts[utils.AddressKey(s)] = true
+	}
+	for _, origin := range g.assets {
+		// Each cycle is found once, from its smallest asset; it is then
+		// rotated to begin at a start asset, if there are any.
+		var path []*Edge
+		visited := map[string]bool{origin: true}
+		usedPools := map[string]bool{}
+		var walk func(at string) bool
+		walk = func(at string) bool {
+			for _, e := range g.edges[at] {
+				if usedPools[e.Pool.Address] || e.Ask < origin {
+					continue
+				}
+				if e.Ask == origin {
+					if len(path) == 0 {
+						continue
+					}
+					cycle := append(append([]*Edge(nil), path...), e)
+					if len(starts) > 0 {
+						if cycle = rotateTo(cycle, starts); cycle == nil {
+							continue
+						}
+					}
+					if !yield(cycle) {
+						return false
+					}
+					continue
+				}
+				if visited[e.Ask] || len(path)+1 >= maxHops {
+					continue
+				}
+				visited[e.Ask], usedPools[e.Pool.Address] = true, true
+				path = append(path, e)
+				ok := walk(e.Ask)
+				path = path[:len(path)-1]
+				visited[e.Ask], usedPools[e.Pool.Address] = false, false
+				if !ok {
+					return false
+				}
+			}
+			return true
+		}
+		if !walk(origin) {
+			return
+		}
+	}
+}
+
+// rotateTo rotates cycle to begin at the first of its assets in starts, or
+// returns nil if there is none.
+func rotateTo(cycle []*Edge, starts map[string]bool) []*Edge {
+	for i, e := range cycle {
+		if starts[e.Offer] {
+			return append(append([]*Edge(nil), cycle[i:]...), cycle[:i]...)
+		}
+	}
+	return nil
+}
+
+// marginalRate is the rate of an infinitesimal swap through side, after the
+// LP and protocol fees.
+func marginalRate(side *amm.Side) float64 {
+	spot, _ := side.SpotPrice().Float64()
+	return spot * feeFactor(side)
+}
+
+// feeFactor is the share of a swap through side left after the LP and
+// protocol fees.
+func feeFactor(side *amm.Side) float64 {
+	return (1 - float64(side.LpFee)/amm.FeeDivider) * (1 - float64(side.ProtocolFee)/amm.FeeDivider)
+}
+
+// simulate swaps offer through each edge in turn and returns the quotes, or
+// nil if a swap fails.
+func simulate(path []*Edge, offer *big.Int) []*amm.Quote {
+	quotes := make([]*amm.Quote, 0, len(path))
+	units := offer
+	for _, e := range path {
+		q, err := e.Side.Swap(units, false)
+		if err != nil || q.AskUnits.Sign() <= 0 {
+			return nil
+		}
+		quotes = append(quotes, q)
+		units = q.AskUnits
+	}
+	return quotes
+}
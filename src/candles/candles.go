@@ -0,0 +1,181 @@
+// Package candles aggregates swap operations into OHLCV candles per pool.
+package candles
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/types"
+)
+
+// Candle summarizes the successful swaps of a pool in an interval. Prices are
+// in asset1 per asset0, in whole tokens; volumes are in smallest units.
+type Candle struct {
+	Pool     string
+	Asset0   string
+	Asset1   string
+	Start    time.Time
+	Interval time.Duration
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Volume0  *big.Int
+	Volume1  *big.Int
+	Trades   int
+}
+
+// errNotPriced is returned by price for operations that carry no price.
+var errNotPriced = errors.New("operation has no price")
+
+// Builder turns a stream of operations, ordered by time, into candles.
+type Builder struct {
+	interval time.Duration
+	open     map[string]*Candle
+}
+
+// NewBuilder returns a Builder of candles of the given interval.
+func NewBuilder(interval time.Duration) *Builder {
+	return &Builder{interval: interval, open: map[string]*Candle{}}
+}
+
+// Add adds an operation and returns the candles it closes: an operation in a
+// later interval closes its pool's current candle. Operations other than
+// successful swaps are ignored.
+func (b *Builder) Add(info types.OperationInfo) ([]Candle, error) {
+	op := info.Operation
+	if op.OperationType != "swap" || !op.Success {
+		return nil, nil
+	}
+	t, err := time.Parse(openapi.TimeLayout, op.PoolTxTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("operation %s: invalid timestamp %q", op.PoolTxHash, op.PoolTxTimestamp)
+	}
+	p, amount0, amount1, err := price(info)
+	if errors.Is(err, errNotPriced) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("operation %s: %w", op.PoolTxHash, err)
+	}
+
+	start := t.UTC().Truncate(b.interval)
+	var closed []Candle
+	c := b.open[op.PoolAddress]
+	if c != nil && start.After(c.Start) {
+		closed = append(closed, *c)
+		c = nil
+	}
+	if c == nil {
+		c = &Candle{
+			Pool:     op.PoolAddress,
+			Asset0:   op.Asset0Address,
+			Asset1:   op.Asset1Address,
+			Start:    start,
+			Interval: b.interval,
+			Open:     p, High: p, Low: p,
+			Volume0: new(big.Int),
+			Volume1: new(big.Int),
+		}
+		b.open[op.PoolAddress] = c
+	}
+	c.High = max(c.High, p)
+	c.Low = min(c.Low, p)
+	c.Close = p
+	c.Volume0.Add(c.Volume0, amount0)
+	c.Volume1.Add(c.Volume1, amount1)
+	c.Trades++
+	return closed, nil
+}
+
+// Flush returns the candles still open, ordered by start then pool, and
+// resets the builder.
+func (b *Builder) Flush() []Candle {
+	candles := make([]Candle, 0, len(b.open))
+	for _, c := range b.open {
+		candles = append(candles, *c)
+	}
+	sort.Slice(candles, func(i, j int) bool {
+		if !candles[i].Start.Equal(candles[j].Start) {
+			return candles[i].Start.Before(candles[j].Start)
+		}
+		return candles[i].Pool < candles[j].Pool
+	})
+	b.open = map[string]*Candle{}
+	return candles
+}
+
+// Build yields the candles of ops, which must be ordered by time. Iteration
+// stops at the first error, which is yielded.
+func Build(ops iter.Seq2[types.OperationInfo, error], interval time.Duration) iter.Seq2[Candle, error] {
+	return func(yield func(Candle, error) bool) {
+		b := NewBuilder(interval)
+		for info, err := range ops {
+			if err != nil {
+				yield(Candle{}, err)
+				return
+			}
+			closed, err := b.Add(info)
+			if err != nil {
+				yield(Candle{}, err)
+				return
+			}
+			for _, c := range closed {
+				if !yield(c, nil) {
+					return
+				}
+			}
+		}
+		for _, c := range b.Flush() {
+			if !yield(c, nil) {
+				return
+			}
+		}
+	}
+}
+
+// price returns the swap price in asset1 per asset0 and the absolute amounts
+// swapped of each asset.
+func price(info types.OperationInfo) (float64, *big.Int, *big.Int, error) {
+	op := info.Operation
+	amount0, err := amount(op.Asset0Delta, op.Asset0Amount)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("asset0 amount: %w", err)
+	}
+	amount1, err := amount(op.Asset1Delta, op.Asset1Amount)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("asset1 amount: %w", err)
+	}
+	if amount0.Sign() == 0 || amount1.Sign() == 0 {
+		return 0, nil, nil, errNotPriced
+	}
+	r := new(big.Rat).SetFrac(amount1, amount0)
+	r.Mul(r, new(big.Rat).SetFrac(pow10(info.Asset0Info.Decimals), pow10(info.Asset1Info.Decimals)))
+	p, _ := r.Float64()
+	return p, amount0, amount1, nil
+}
+
+// amount is the absolute value of delta, or of fallback if delta is empty.
+func amount(delta, fallback string) (*big.Int, error) {
+	s := delta
+	if s == "" {
+		s = fallback
+	}
+	if s == "" {
+		return new(big.Int), nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return n.Abs(n), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
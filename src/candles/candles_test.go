@@ -0,0 +1,75 @@
+package candles
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func swap(pool, timestamp, delta0, delta1 string) types.OperationInfo {
+	return types.OperationInfo{
+		Operation: types.Operation{
+			OperationType:   "swap",
+			Success:         true,
+			PoolAddress:     pool,
+			PoolTxTimestamp: timestamp,
+			Asset0Delta:     delta0,
+			Asset1Delta:     delta1,
+		},
+		Asset0Info: types.Asset{Decimals: 9},
+		Asset1Info: types.Asset{Decimals: 6},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	failed := swap("A", "2024-01-01T00:20:00", "1000000000", "-1")
+	failed.Operation.Success = false
+	provide := swap("A", "2024-01-01T00:25:00", "1000000000", "1")
+	provide.Operation.OperationType = "provide"
+
+	ops := slices.Values([]types.OperationInfo{
+		swap("A", "2024-01-01T00:10:00", "1000000000", "-5000000"), // 5
+		swap("B", "2024-01-01T00:15:00", "-2000000000", "1000000"), // 0.5
+		failed,
+		provide,
+		swap("A", "2024-01-01T00:30:00", "-1000000000", "6000000"), // 6
+		swap("A", "2024-01-01T00:40:00", "2000000000", "-8000000"), // 4
+		swap("A", "2024-01-01T01:05:00", "1000000000", "-4500000"), // 4.5
+	})
+	var candles []Candle
+	for c, err := range Build(func(yield func(types.OperationInfo, error) bool) {
+		for op := range ops {
+			if !yield(op, nil) {
+				return
+			}
+		}
+	}, time.Hour) {
+		assert.NoError(t, err)
+		candles = append(candles, c)
+	}
+
+	if !assert.Len(t, candles, 3) {
+		return
+	}
+	first := candles[0]
+	assert.Equal(t, "A", first.Pool)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), first.Start)
+	assert.Equal(t, []float64{5, 6, 4, 4}, []float64{first.Open, first.High, first.Low, first.Close})
+	assert.Equal(t, "4000000000", first.Volume0.String())
+	assert.Equal(t, "19000000", first.Volume1.String())
+	assert.Equal(t, 3, first.Trades)
+
+	// The rest are flushed in order of start, then pool.
+	assert.Equal(t, "B", candles[1].Pool)
+	assert.Equal(t, 0.5, candles[1].Close)
+	assert.Equal(t, "A", candles[2].Pool)
+	assert.Equal(t, 4.5, candles[2].Open)
+}
+
+func TestAddRejectsBadTimestamps(t *testing.T) {
+	_, err := NewBuilder(time.Minute).Add(swap("A", "yesterday", "1", "1"))
+	assert.ErrorContains(t, err, "invalid timestamp")
+}
@@ -0,0 +1,316 @@
+// Package cassette records HTTP interactions with the Ston.fi API to files and
+// replays them, so that tests run offline against real payloads.
+//
+// A Recorder is an http.RoundTripper. Plug it into a client with Use:
+//
+//	rec, err := cassette.New("testdata/pools.json", cassette.Options{Mode: cassette.ModeReplay})
+//	rec.Use(c.Client)
+//	defer rec.Stop()
+//
+// Cassettes only keep the method, path and query of requests and the status,
+// content type and body of responses; headers such as cookies never reach disk.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/imroc/req/v3"
+)
+
+// Mode selects whether a Recorder talks to the network.
+type Mode int
+
+const (
+	// ModeReplay serves recorded interactions only and fails unrecorded requests.
+	ModeReplay Mode = iota
+	// ModeRecord sends every request to the API and rewrites the cassette.
+	ModeRecord
+	// ModeReplayOrRecord serves recorded interactions and records the rest.
+	ModeReplayOrRecord
+)
+
+// ErrUnrecorded is returned in ModeReplay for requests not in the cassette.
+var ErrUnrecorded = errors.New("cassette: unrecorded request")
+
+// ParseMode parses "replay", "record" or "replay_or_record", as found in an
+// environment variable such as STONFI_CASSETTE_MODE. Empty means ModeReplay.
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "", "replay":
+		return ModeReplay, nil
+	case "record":
+		return ModeRecord, nil
+	case "replay_or_record":
+		return ModeReplayOrRecord, nil
+	}
+	return 0, fmt.Errorf("cassette: unknown mode %q", s)
+}
+
+// Request is the recorded part of an HTTP request.
+type Request struct {
+	Method string     `json:"method"`
+	Path   string     `json:"path"`
+	Query  url.Values `json:"query,omitempty"`
+}
+
+// Response is the recorded part of an HTTP response.
+type Response struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	// Body is kept as JSON when the response is JSON, which keeps cassettes
+	// readable in diffs, and as a JSON string otherwise.
+	Body json.RawMessage `json:"body"`
+}
+
+// Interaction is a request and the response it got.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Options configures a Recorder.
+type Options struct {
+	Mode Mode
+	// Real is the transport used to reach the API. It defaults to the
+	// transport replaced by Use, or http.DefaultTransport.
+	Real http.RoundTripper
+	// Redact lists addresses, in any form, that are replaced by stable fake
+	// addresses in recorded paths, queries and bodies.
+	Redact []string
+	// RedactWallets also redacts every address used as a wallet in a request:
+	// the {addr_str} of /wallets routes and the wallet_address and
+	// owner_address query parameters.
+	RedactWallets bool
+}
+
+// Recorder records and replays HTTP interactions.
+type Recorder struct {
+	mode     Mode
+	path     string
+	real     http.RoundTripper
+	redactor *redactor
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+	changed  bool
+
+	client   *http.Client
+	previous http.RoundTripper
+}
+
+// New opens the cassette at path. In ModeRecord the existing content is
+// discarded; in the other modes the file must exist unless the mode records.
+func New(path string, opts Options) (*Recorder, error) {
+	r := &Recorder{
+		mode:     opts.Mode,
+		path:     path,
+		real:     opts.Real,
+		redactor: newRedactor(opts.Redact, opts.RedactWallets),
+	}
+	if r.mode == ModeRecord {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && r.mode == ModeReplayOrRecord {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("cassette: decoding %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Use makes c send its requests through the recorder.
+func (r *Recorder) Use(c *req.Client) {
+	r.client = c.GetClient()
+	r.previous = r.client.Transport
+	if r.real == nil {
+		r.real = r.previous
+	}
+	r.client.Transport = r
+}
+
+// Stop restores the transport replaced by Use and saves the cassette.
+func (r *Recorder) Stop() error {
+	if r.client != nil {
+		r.client.Transport = r.previous
+		r.client = nil
+	}
+	return r.Save()
+}
+
+// Save writes the cassette if anything was recorded.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.changed {
+		return nil
+	}
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cassette: encoding: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	r.changed = false
+	return nil
+}
+
+// Interactions returns the interactions in the cassette.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(httpReq *http.Request) (*http.Response, error) {
+	recorded := r.redactor.request(httpReq)
+
+	if r.mode != ModeRecord {
+		if resp, ok := r.find(recorded); ok {
+			return resp.toHTTP(httpReq)
+		}
+		if r.mode == ModeReplay {
+			return nil, fmt.Errorf("%w: %s %s", ErrUnrecorded, recorded.Method, recordedURL(recorded))
+		}
+	}
+
+	real := r.real
+	if real == nil {
+		real = http.DefaultTransport
+	}
+	resp, err := real.RoundTrip(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: reading response: %w", err)
+	}
+
+	interaction := Interaction{
+		Request: recorded,
+		Response: Response{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        encodeBody(r.redactor.text(string(body))),
+		},
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.used = append(r.used, true)
+	r.changed = true
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// find returns the response of the first unused interaction matching
+// request, falling back to the last used one so that repeated requests such as
+// polls keep replaying. The response is copied under the lock, as recording
+// may grow the interactions meanwhile.
+func (r *Recorder) find(request Request) (Response, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, in := range r.cassette.Interactions {
+		if !in.Request.matches(request) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return in.Response, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return Response{}, false
+	}
+	return r.cassette.Interactions[last].Response, true
+}
+
+func (q Request) matches(other Request) bool {
+	if q.Method != other.Method || q.Path != other.Path {
+		return false
+	}
+	if len(q.Query) == 0 && len(other.Query) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(q.Query, other.Query)
+}
+
+func (resp Response) toHTTP(httpReq *http.Request) (*http.Response, error) {
+	body, err := decodeBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	if resp.ContentType != "" {
+		header.Set("Content-Type", resp.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+		StatusCode:    resp.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       httpReq,
+	}, nil
+}
+
+func encodeBody(body string) json.RawMessage {
+	if json.Valid([]byte(body)) {
+		return json.RawMessage(body)
+	}
+	encoded, _ := json.Marshal(body)
+	return encoded
+}
+
+func decodeBody(raw json.RawMessage) (string, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", fmt.Errorf("cassette: decoding body: %w", err)
+		}
+		return s, nil
+	}
+	return string(raw), nil
+}
+
+func recordedURL(q Request) string {
+	if len(q.Query) == 0 {
+		return q.Path
+	}
+	return q.Path + "?" + q.Query.Encode()
+}
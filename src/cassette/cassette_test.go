@@ -0,0 +1,154 @@
+package cassette
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	walletRaw   = "0:729c13b6df2c07cbf0a06ab63d34af454f3d320ec1bcd8fb5c6d24d0806a17c2"
+	wallet      = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+)
+
+func newServer() *stonfitest.Server {
+	return stonfitest.NewServerWithFixtures(stonfitest.Fixtures{
+		Assets: []types.Asset{{ContractAddress: usdtAddress, Symbol: "USD₮", Decimals: 6}},
+		Operations: types.Operations{{Operation: types.Operation{
+			OperationType:   "swap",
+			WalletAddress:   wallet,
+			PoolTxTimestamp: "2024-01-01T00:30:00",
+		}}},
+	})
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	s := newServer()
+	rec, err := New(path, Options{Mode: ModeRecord, RedactWallets: true})
+	assert.NoError(t, err)
+	c := client.NewStonfiClientWithOptions(client.StonfiClientOptions{BaseURL: s.URL + "/v1"})
+	rec.Use(c.Client)
+	_, err = c.GetAssets(ctx)
+	assert.NoError(t, err)
+	_, err = c.GetWalletOperations(ctx, wallet)
+	assert.NoError(t, err)
+	assert.NoError(t, rec.Stop())
+	s.Close()
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), usdtAddress)
+	assert.NotContains(t, string(data), wallet)
+	assert.NotContains(t, string(data), walletRaw)
+
+	// The server is gone: everything must come from the cassette.
+	rec, err = New(path, Options{Mode: ModeReplay, RedactWallets: true})
+	assert.NoError(t, err)
+	rec.Use(c.Client)
+	defer rec.Stop()
+
+	assets, err := c.GetAssets(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, assets.AssetList, 1) {
+		assert.Equal(t, "USD₮", assets.AssetList[0].Symbol)
+	}
+	ops, err := c.GetWalletOperations(ctx, wallet)
+	assert.NoError(t, err)
+	if assert.Len(t, ops.Operations, 1) {
+		assert.Equal(t, fakeAddress(walletRaw), ops.Operations[0].Operation.WalletAddress)
+	}
+
+	_, err = c.GetPools(ctx)
+	assert.ErrorIs(t, err, ErrUnrecorded)
+}
+
+func TestReplayOrRecord(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	s := newServer()
+	defer s.Close()
+	c := client.NewStonfiClientWithOptions(client.StonfiClientOptions{BaseURL: s.URL + "/v1"})
+
+	rec, err := New(path, Options{Mode: ModeReplayOrRecord})
+	assert.NoError(t, err)
+	rec.Use(c.Client)
+	_, err = c.GetAssets(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, rec.Stop())
+	assert.Len(t, s.Requests(), 1)
+
+	rec, err = New(path, Options{Mode: ModeReplayOrRecord})
+	assert.NoError(t, err)
+	rec.Use(c.Client)
+	_, err = c.GetAssets(ctx)
+	assert.NoError(t, err)
+	_, err = c.GetPools(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, rec.Stop())
+	assert.Equal(t, []string{"GET /v1/assets", "GET /v1/pools"}, s.Requests())
+	assert.Len(t, rec.Interactions(), 2)
+}
+
+func TestConcurrentReplayOrRecord(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	s := newServer()
+	defer s.Close()
+	c := client.NewStonfiClientWithOptions(client.StonfiClientOptions{BaseURL: s.URL + "/v1"})
+
+	rec, err := New(path, Options{Mode: ModeReplayOrRecord})
+	assert.NoError(t, err)
+	rec.Use(c.Client)
+	_, err = c.GetAssets(ctx)
+	assert.NoError(t, err)
+
+	// Replays of the recorded call race with misses that grow the cassette.
+	done := make(chan struct{})
+	var replays, misses sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		replays.Add(1)
+		go func() {
+			defer replays.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				_, err := c.GetAssets(ctx)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		misses.Add(1)
+		go func() {
+			defer misses.Done()
+			c.GetFarm(ctx, fmt.Sprintf("farm-%d", i))
+		}()
+	}
+	misses.Wait()
+	close(done)
+	replays.Wait()
+	assert.NoError(t, rec.Stop())
+	assert.Len(t, rec.Interactions(), 21)
+}
+
+func TestRequestMatching(t *testing.T) {
+	q := Request{Method: "GET", Path: "/v1/stats/dex", Query: map[string][]string{"since": {"a"}, "until": {"b"}}}
+	assert.True(t, q.matches(Request{Method: "GET", Path: "/v1/stats/dex", Query: map[string][]string{"until": {"b"}, "since": {"a"}}}))
+	assert.False(t, q.matches(Request{Method: "GET", Path: "/v1/stats/dex", Query: map[string][]string{"since": {"a"}}}))
+	assert.False(t, q.matches(Request{Method: "POST", Path: "/v1/stats/dex", Query: q.Query}))
+}
@@ -0,0 +1,100 @@
+package cassette
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// walletParams are the query parameters that carry wallet addresses.
+var walletParams = []string{"wallet_address", "owner_address"}
+
+type redactor struct {
+	wallets bool
+
+	mu        sync.Mutex
+	addresses map[string]bool // raw form
+	replacer  *strings.Replacer
+}
+
+func newRedactor(addresses []string, wallets bool) *redactor {
+	r := &redactor{wallets: wallets, addresses: map[string]bool{}}
+	for _, a := range addresses {
+		r.add(a)
+	}
+	return r
+}
+
+func (r *redactor) add(address string) {
+	raw, err := utils.NormalizeAddress(address)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.addresses[raw] {
+		return
+	}
+	r.addresses[raw] = true
+
+	var pairs []string
+	for raw := range r.addresses {
+		forms, _ := utils.AddressForms(raw)
+		for _, form := range forms {
+			pairs = append(pairs, form, fakeAddress(raw))
+		}
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// request returns the redacted form of httpReq, first learning its wallet
+// addresses when wallets are redacted.
+func (r *redactor) request(httpReq *http.Request) Request {
+	query := httpReq.URL.Query()
+	if r.wallets {
+		if rest, ok := strings.CutPrefix(httpReq.URL.Path, "/v1/wallets/"); ok {
+			wallet, _, _ := strings.Cut(rest, "/")
+			r.add(wallet)
+		}
+		for _, name := range walletParams {
+			for _, v := range query[name] {
+				r.add(v)
+			}
+		}
+	}
+
+	recorded := Request{Method: httpReq.Method, Path: r.text(httpReq.URL.Path)}
+	if len(query) > 0 {
+		recorded.Query = url.Values{}
+		for key, values := range query {
+			for _, v := range values {
+				recorded.Query.Add(key, r.text(v))
+			}
+		}
+	}
+	return recorded
+}
+
+// text replaces every spelling of the redacted addresses in s with their
+// fake counterparts.
+func (r *redactor) text(s string) string {
+	r.mu.Lock()
+	replacer := r.replacer
+	r.mu.Unlock()
+	if replacer == nil {
+		return s
+	}
+	return replacer.Replace(s)
+}
+
+// fakeAddress derives a stable raw address from raw, so that a redacted
+// wallet gets the same replacement in every recording.
+func fakeAddress(raw string) string {
+	sum := sha256.Sum256([]byte("go-stonfi cassette " + raw))
+	return fmt.Sprintf("0:%x", sum)
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/itay747/go-stonfi/src/types"
+)
+
+// DefaultBatchConcurrency is how many calls a batch method makes at once,
+// unless StonfiClientOptions.BatchConcurrency says otherwise.
+const DefaultBatchConcurrency = 8
+
+// BatchResult is the outcome of one item of a batch method: its value, or
+// the error fetching it.
+type BatchResult[T any] struct {
+	Address string
+	Value   T
+	Err     error
+}
+
+// BatchErrors joins the errors of a batch's failed items, or returns nil if
+// every item succeeded.
+func BatchErrors[T any](results []BatchResult[T]) error {
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Address, r.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetPoolsByAddress fetches the pools at addresses, with a result for each
+// address, in the same order.
+func (c *StonfiClient) GetPoolsByAddress(ctx context.Context, addresses []string) []BatchResult[types.Pool] {
+	return batch(ctx, c.batchConcurrency, addresses, func(ctx context.Context, address string) (types.Pool, error) {
+		response, err := c.GetPool(ctx, address)
+		if err != nil {
+			return types.Pool{}, err
+		}
+		return response.Pool, nil
+	})
+}
+
+// GetAssetsByAddress fetches the assets at addresses, with a result for
+// each address, in the same order.
+func (c *StonfiClient) GetAssetsByAddress(ctx context.Context, addresses []string) []BatchResult[types.Asset] {
+	return batch(ctx, c.batchConcurrency, addresses, func(ctx context.Context, address string) (types.Asset, error) {
+		response, err := c.GetAsset(ctx, address)
+		if err != nil {
+			return types.Asset{}, err
+		}
+		return response.Asset, nil
+	})
+}
+
+// GetWalletsAssets fetches the balances of the wallets at addresses, with a
+// result for each address, in the same order.
+func (c *StonfiClient) GetWalletsAssets(ctx context.Context, addresses []string) []BatchResult[[]types.AssetList] {
+	return batch(ctx, c.batchConcurrency, addresses, func(ctx context.Context, address string) ([]types.AssetList, error) {
+		response, err := c.GetWalletAssets(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		return response.AssetList, nil
+	})
+}
+
+// batch calls fetch for each address, at most concurrency at once. Once ctx
+// is done, the addresses not yet started fail with its error.
+func batch[T any](ctx context.Context, concurrency int, addresses []string, fetch func(context.Context, string) (T, error)) []BatchResult[T] {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	results := make([]BatchResult[T], len(addresses))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		results[i].Address = address
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i].Value, results[i].Err = fetch(ctx, address)
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPoolsByAddress(t *testing.T) {
+	client := NewStonfiClientWithOptions(StonfiClientOptions{BatchConcurrency: 3})
+	httpmock.ActivateNonDefault(client.Client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	var inFlight, most atomic.Int32
+	httpmock.RegisterResponder(http.MethodGet, `=~/v1/pools/(\w+)$`, func(req *http.Request) (*http.Response, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := most.Load()
+			if n <= m || most.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		address := strings.TrimPrefix(req.URL.Path, "/v1/pools/")
+		if address == "missing" {
+			return httpmock.NewStringResponse(http.StatusNotFound, `{"message":"not found","code":404}`), nil
+		}
+		return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"pool":{"address":%q}}`, address)), nil
+	})
+
+	addresses := []string{"a", "b", "missing", "c", "d", "e", "f", "a"}
+	results := client.GetPoolsByAddress(context.Background(), addresses)
+	if !assert.Len(t, results, len(addresses)) {
+		return
+	}
+	for i, r := range results {
+		assert.Equal(t, addresses[i], r.Address, "input order")
+		if addresses[i] == "missing" {
+			assert.ErrorContains(t, r.Err, "status code: 404")
+			continue
+		}
+		assert.NoError(t, r.Err)
+		assert.Equal(t, addresses[i], r.Value.Address)
+	}
+	assert.Equal(t, int32(3), most.Load(), "bounded concurrency")
+	err := BatchErrors(results)
+	assert.ErrorContains(t, err, "missing: ")
+	assert.Nil(t, BatchErrors(results[:2]))
+}
+
+func TestBatchCancellation(t *testing.T) {
+	client := NewStonfiClientWithOptions(StonfiClientOptions{BatchConcurrency: 2})
+	httpmock.ActivateNonDefault(client.Client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	httpmock.RegisterResponder(http.MethodGet, `=~/v1/wallets/\w+/assets$`, func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	addresses := make([]string, 50)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("w%d", i)
+	}
+	results := client.GetWalletsAssets(ctx, addresses)
+	assert.Equal(t, int32(2), calls.Load(), "no call starts once cancelled")
+	for _, r := range results {
+		assert.ErrorIs(t, r.Err, context.Canceled, r.Address)
+	}
+}
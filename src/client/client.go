@@ -2,20 +2,25 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/imroc/req/v3"
+	"github.com/itay747/go-stonfi/src/openapi"
 	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
 )
 
 var BaseURLStr = "https://api.ston.fi/v1"
 var EarliestDate = time.Date(2022, 11, 17, 0, 0, 0, 0, time.UTC)
 
 type StonfiClient struct {
-	Client *req.Client
+	Client           *req.Client
+	hooks            []Hook
+	batchConcurrency int
 }
 
 // NewStonfiClient creates a new API client for the Ston.fi service.
@@ -35,49 +40,72 @@ func NewStonfiClient() *StonfiClient {
 	}
 }
 
-func (c *StonfiClient) buildQueryParams(base string, params url.Values) string {
-	u, _ := url.Parse(c.Client.BaseURL + base)
-	q := u.Query()
-	for key, values := range params {
-		for _, value := range values {
-			q.Add(key, value)
-		}
+// NewStonfiClientWithOptions creates a new API client configured by opts.
+func NewStonfiClientWithOptions(opts StonfiClientOptions) *StonfiClient {
+	c := NewStonfiClient()
+	if opts.BaseURL != "" {
+		c.Client.SetBaseURL(opts.BaseURL)
 	}
-	u.RawQuery = q.Encode()
-	return u.String()
+	c.Use(opts.Hooks...)
+	c.batchConcurrency = opts.BatchConcurrency
+	return c
+}
+
+// APIError is returned by a call the API answered with a status other than
+// 2xx.
+type APIError struct {
+	StatusCode int
+	Body       []byte
 }
 
-func (c *StonfiClient) request(ctx context.Context, method, url string, body interface{}, response interface{}) error {
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s, status code: %d", e.Body, e.StatusCode)
+}
+
+// request sends the call and decodes the JSON response into response,
+// recording the status code and body size in result.
+func (c *StonfiClient) request(ctx context.Context, call *CallInfo, body interface{}, response interface{}, result *CallResult) error {
 	req := c.Client.R().SetContext(ctx).SetBody(body)
-	resp, err := req.Send(method, url)
+	for key, values := range call.Header {
+		req.SetHeader(key, strings.Join(values, ", "))
+	}
+	resp, err := req.Send(call.Endpoint.Method, call.URL)
 	if err != nil {
-		return fmt.Errorf("request error: %s, status code: %d", err, resp.StatusCode)
+		return fmt.Errorf("request error: %w", err)
 	}
+	result.StatusCode = resp.StatusCode
+	data, err := resp.ToBytes()
+	result.Bytes = len(data)
 	if !resp.IsSuccessState() {
-		return fmt.Errorf("API error: %s, status code: %d", resp.String(), resp.StatusCode)
+		return &APIError{StatusCode: resp.StatusCode, Body: data}
 	}
-	if err = resp.UnmarshalJson(response); err != nil {
+	if err != nil {
+		return fmt.Errorf("read error: %w", err)
+	}
+	if call.Endpoint.NormalizeResponse {
+		if data, err = utils.SnakeCaseResponse(data); err != nil {
+			return fmt.Errorf("JSON normalize error: %s", err)
+		}
+	}
+	if err = json.Unmarshal(data, response); err != nil {
 		return fmt.Errorf("JSON unmarshal error: %s", err)
 	}
 	return nil
 }
 
+// GetAsset fetches details for a single asset.
 func (c *StonfiClient) GetAsset(ctx context.Context, assetAddress string) (*types.AssetResponse, error) {
-	endpoint := fmt.Sprintf("/v1/asset/%s", assetAddress)
-	url := c.buildQueryParams(endpoint, nil)
 	var response types.AssetResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
-		return &response, err
+	if err := c.Call(ctx, getAssetEndpoint, url.Values{"addr_str": {assetAddress}}, &response); err != nil {
+		return nil, err
 	}
 	return &response, nil
 }
 
 // GetAssets fetches details for all assets.
 func (c *StonfiClient) GetAssets(ctx context.Context) (*types.AssetListResponse, error) {
-	endpoint := "/assets"
-	url := c.buildQueryParams(endpoint, nil)
 	var response types.AssetListResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getAssetsEndpoint, nil, &response); err != nil {
 		return nil, err
 	}
 	return &response, nil
@@ -85,10 +113,8 @@ func (c *StonfiClient) GetAssets(ctx context.Context) (*types.AssetListResponse,
 
 // GetFarm fetches details for a single farm.
 func (c *StonfiClient) GetFarm(ctx context.Context, farmAddress string) (*types.FarmResponse, error) {
-	endpoint := fmt.Sprintf("/farm/%s", farmAddress)
-	url := c.buildQueryParams(endpoint, nil)
 	var response types.FarmResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getFarmEndpoint, url.Values{"addr_str": {farmAddress}}, &response); err != nil {
 		return nil, err
 	}
 	return &response, nil
@@ -96,9 +122,8 @@ func (c *StonfiClient) GetFarm(ctx context.Context, farmAddress string) (*types.
 
 // GetFarms fetches details for all farms.
 func (c *StonfiClient) GetFarms(ctx context.Context) (*types.FarmListResponse, error) {
-	url := c.buildQueryParams("/farms", nil)
 	var response types.FarmListResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getFarmsEndpoint, nil, &response); err != nil {
 		return nil, err
 	}
 	return &response, nil
@@ -106,9 +131,8 @@ func (c *StonfiClient) GetFarms(ctx context.Context) (*types.FarmListResponse, e
 
 // GetPools fetches details for all pools.
 func (c *StonfiClient) GetPools(ctx context.Context) (*types.PoolListResponse, error) {
-	url := c.buildQueryParams("/v1/pools", nil)
 	var response types.PoolListResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getPoolsEndpoint, nil, &response); err != nil {
 		return nil, err
 	}
 	return &response, nil
@@ -116,10 +140,8 @@ func (c *StonfiClient) GetPools(ctx context.Context) (*types.PoolListResponse, e
 
 // GetPool fetches details for a single pool.
 func (c *StonfiClient) GetPool(ctx context.Context, poolAddress string) (*types.PoolResponse, error) {
-	endpoint := fmt.Sprintf("/v1/pool/%s", poolAddress)
-	url := c.buildQueryParams(endpoint, nil)
 	var response types.PoolResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getPoolEndpoint, url.Values{"addr_str": {poolAddress}}, &response); err != nil {
 		return nil, err
 	}
 	return &response, nil
@@ -128,24 +150,21 @@ func (c *StonfiClient) GetPool(ctx context.Context, poolAddress string) (*types.
 // GetSwapStatus fetches the status of a specific swap operation.
 func (c *StonfiClient) GetSwapStatus(ctx context.Context, routerAddress, ownerAddress, queryId string) (*types.SwapResponse, error) {
 	queryParams := url.Values{
-		"routerAddress": []string{routerAddress},
-		"ownerAddress":  []string{ownerAddress},
-		"queryId":       []string{queryId},
+		"router_address": []string{routerAddress},
+		"owner_address":  []string{ownerAddress},
+		"query_id":       []string{queryId},
 	}
-	url := c.buildQueryParams("/v1/swap/status", queryParams)
 	var response *types.SwapResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getSwapStatusEndpoint, queryParams, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
 // GetPoolByAddress fetches details of a pool by its address.
-func (c *StonfiClient) GetPoolByAddress(ctx context.Context, poolAddress string) (*types.PoolListResponse, error) {
-	endpoint := fmt.Sprintf("/v1/pools/%s", poolAddress)
-	url := c.buildQueryParams(endpoint, nil)
-	var response *types.PoolListResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+func (c *StonfiClient) GetPoolByAddress(ctx context.Context, poolAddress string) (*types.PoolResponse, error) {
+	var response *types.PoolResponse
+	if err := c.Call(ctx, getPoolEndpoint, url.Values{"addr_str": {poolAddress}}, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -159,9 +178,8 @@ func (c *StonfiClient) SimulateSwap(ctx context.Context, offerAddress, askAddres
 		"units":              []string{units},
 		"slippage_tolerance": []string{slippageTolerance},
 	}
-	url := c.buildQueryParams("/v1/swap/simulate", queryParams)
 	var response *types.SwapSimulationResponse
-	if err := c.request(ctx, http.MethodPost, url, nil, &response); err != nil {
+	if err := c.Call(ctx, simulateSwapEndpoint, queryParams, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -175,9 +193,8 @@ func (c *StonfiClient) SimulateReverseSwap(ctx context.Context, offerAddress, as
 		"units":              []string{units},
 		"slippage_tolerance": []string{slippageTolerance},
 	}
-	url := c.buildQueryParams("/v1/reverse_swap/simulate", queryParams)
 	var response *types.SwapSimulationResponse
-	if err := c.request(ctx, http.MethodPost, url, nil, &response); err != nil {
+	if err := c.Call(ctx, simulateReverseSwapEndpoint, queryParams, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -194,10 +211,8 @@ func (c *StonfiClient) SearchAssets(ctx context.Context, searchString string, co
 		queryParams.Add("wallet_address", *walletAddress)
 	}
 
-	url := c.buildQueryParams("/v1/assets/search", queryParams)
-
 	var response *types.SearchAssetsResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, searchAssetsEndpoint, queryParams, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -218,19 +233,15 @@ func (c *StonfiClient) QueryAssets(ctx context.Context, condition string, uncond
 		queryParams.Add("wallet_address", walletAddress)
 	}
 
-	url := c.buildQueryParams("/v1/assets/query", queryParams)
-
 	var response *types.QueryWalletBallanceResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, queryAssetsEndpoint, queryParams, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 func (c *StonfiClient) GetFarmsByPool(ctx context.Context, poolAddress string) (*types.FarmListResponse, error) {
-	endpoint := fmt.Sprintf("/farms_by_pool/%s", poolAddress)
-	url := c.buildQueryParams(endpoint, nil)
 	var response *types.FarmListResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getFarmsByPoolEndpoint, url.Values{"pool_addr_str": {poolAddress}}, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -248,6 +259,14 @@ func checkValidTimeRange(startDate, endDate time.Time) error {
 	return nil
 }
 
+// timeRangeParams are the query parameters shared by the `/v1/stats/...` endpoints.
+func timeRangeParams(startDate, endDate time.Time) url.Values {
+	return url.Values{
+		"since": []string{startDate.UTC().Format(openapi.TimeLayout)},
+		"until": []string{endDate.UTC().Format(openapi.TimeLayout)},
+	}
+}
+
 // GetStats retrieves aggregated statistics for a specific time range from `/v1/stats/dex`
 func (c *StonfiClient) GetStats(ctx context.Context, startDate, endDate time.Time) (*types.DexStatsResponse, error) {
 
@@ -255,13 +274,8 @@ func (c *StonfiClient) GetStats(ctx context.Context, startDate, endDate time.Tim
 		return nil, err
 	}
 
-	queryParams := url.Values{
-		"start_date": []string{startDate.Format(time.RFC3339)},
-		"end_date":   []string{endDate.Format(time.RFC3339)},
-	}
-	url := c.buildQueryParams("/v1/stats/dex", queryParams)
 	var response *types.DexStatsResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getStatsEndpoint, timeRangeParams(startDate, endDate), &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -272,30 +286,20 @@ func (c *StonfiClient) GetHistoricalSwaps(ctx context.Context, startDate, endDat
 	if err := checkValidTimeRange(startDate, endDate); err != nil {
 		return nil, err
 	}
-	queryParams := url.Values{
-		"start_date": []string{startDate.Format(time.RFC3339)},
-		"end_date":   []string{endDate.Format(time.RFC3339)},
-	}
-	url := c.buildQueryParams("/v1/stats/operations", queryParams)
 	var response *types.OperationsStatsResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getOperationsStatsEndpoint, timeRangeParams(startDate, endDate), &response); err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 
-// Retrieve historical pool data for a specific time range `/v1/stats/pools`
+// Retrieve historical pool data for a specific time range `/v1/stats/pool`
 func (c *StonfiClient) GetPoolStats(ctx context.Context, startDate, endDate time.Time) (*types.PoolStatsResponse, error) {
 	if err := checkValidTimeRange(startDate, endDate); err != nil {
 		return nil, err
 	}
-	queryParams := url.Values{
-		"start_date": []string{startDate.Format(time.RFC3339)},
-		"end_date":   []string{endDate.Format(time.RFC3339)},
-	}
-	url := c.buildQueryParams("/v1/stats/pools", queryParams)
 	var response *types.PoolStatsResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getPoolStatsEndpoint, timeRangeParams(startDate, endDate), &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -303,11 +307,8 @@ func (c *StonfiClient) GetPoolStats(ctx context.Context, startDate, endDate time
 
 // GetWalletAssets fetches details of all assets associated with a specific wallet.
 func (c *StonfiClient) GetWalletAssets(ctx context.Context, walletAddress string) (*types.SearchAssetsResponse, error) {
-	endpoint := fmt.Sprintf("/wallets/%s/assets", walletAddress)
-	url := c.buildQueryParams(endpoint, nil)
-
 	var response *types.SearchAssetsResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getWalletAssetsEndpoint, url.Values{"addr_str": {walletAddress}}, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -315,10 +316,9 @@ func (c *StonfiClient) GetWalletAssets(ctx context.Context, walletAddress string
 
 // GetWalletAsset fetches details of a specific asset associated with a specific wallet.
 func (c *StonfiClient) GetWalletAsset(ctx context.Context, walletAddressStr string, assetStr string) (*types.WalletAssetResponse, error) {
-	endpoint := fmt.Sprintf("/v1/wallets/%s/assets/%s", walletAddressStr, assetStr)
-	url := c.buildQueryParams(endpoint, nil)
+	params := url.Values{"addr_str": {walletAddressStr}, "asset_address": {assetStr}}
 	var response *types.WalletAssetResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getWalletAssetEndpoint, params, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -326,19 +326,16 @@ func (c *StonfiClient) GetWalletAsset(ctx context.Context, walletAddressStr stri
 
 // Wallet-specific farms
 func (c *StonfiClient) GetWalletFarms(ctx context.Context, walletAddress string) (*types.FarmListResponse, error) {
-	endpoint := fmt.Sprintf("/wallets/%s/farms", walletAddress)
-	url := c.buildQueryParams(endpoint, nil)
 	var response *types.FarmListResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getWalletFarmsEndpoint, url.Values{"addr_str": {walletAddress}}, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 func (c *StonfiClient) GetWalletFarm(ctx context.Context, walletAddress string, farmAddress string) (*types.FarmResponse, error) {
-	endpoint := fmt.Sprintf("/wallets/%s/farms/%s", walletAddress, farmAddress)
-	url := c.buildQueryParams(endpoint, nil)
+	params := url.Values{"addr_str": {walletAddress}, "farm_address": {farmAddress}}
 	var response *types.FarmResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getWalletFarmEndpoint, params, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -346,19 +343,16 @@ func (c *StonfiClient) GetWalletFarm(ctx context.Context, walletAddress string,
 
 // Wallet-specific pools
 func (c *StonfiClient) GetWalletPools(ctx context.Context, walletAddress string) (*types.PoolListResponse, error) {
-	endpoint := fmt.Sprintf("/wallets/%s/pools", walletAddress)
-	url := c.buildQueryParams(endpoint, nil)
 	var response *types.PoolListResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getWalletPoolsEndpoint, url.Values{"addr_str": {walletAddress}}, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
 }
 func (c *StonfiClient) GetWalletPool(ctx context.Context, walletAddress string, poolAddress string) (*types.PoolResponse, error) {
-	endpoint := fmt.Sprintf("/wallets/%s/pools/%s", walletAddress, poolAddress)
-	url := c.buildQueryParams(endpoint, nil)
+	params := url.Values{"addr_str": {walletAddress}, "pool_address": {poolAddress}}
 	var response *types.PoolResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getWalletPoolEndpoint, params, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -366,10 +360,8 @@ func (c *StonfiClient) GetWalletPool(ctx context.Context, walletAddress string,
 
 // Wallet-specific operations
 func (c *StonfiClient) GetWalletOperations(ctx context.Context, walletAddress string) (*types.WalletOperationsResponse, error) {
-	endpoint := fmt.Sprintf("/wallets/%s/operations", walletAddress)
-	url := c.buildQueryParams(endpoint, nil)
 	var response *types.WalletOperationsResponse
-	if err := c.request(ctx, http.MethodGet, url, nil, &response); err != nil {
+	if err := c.Call(ctx, getWalletOperationsEndpoint, url.Values{"addr_str": {walletAddress}}, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -2,9 +2,11 @@ package client
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
 
+	"github.com/itay747/go-stonfi/src/types"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 )
@@ -23,13 +25,12 @@ func TestGetAsset(t *testing.T) {
 	mockResponse := `{
 		"asset": {
 			"contract_address": "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c",
-			"kind": "Jetton",
-			"meta": {
-				"symbol": "TON",
-				"display_name": "TON",
-				"image_url": "https://asset.ston.fi/img/EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c/4ecd4687e0b5b8ff21a7fbe03f9d281c26a2dc13eac7b7d16048cc693fe0ec39",
-				"decimals": 9
-			},
+			"kind": "Ton",
+			"symbol": "TON",
+			"display_name": "TON",
+			"image_url": "https://asset.ston.fi/img/EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c/4ecd4687e0b5b8ff21a7fbe03f9d281c26a2dc13eac7b7d16048cc693fe0ec39",
+			"decimals": 9,
+			"default_symbol": true,
 			"tags": ["default_symbol"],
 			"dex_price_usd": "6.730000000000000"
 		}
@@ -38,8 +39,26 @@ func TestGetAsset(t *testing.T) {
 	httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/assets/EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c", httpmock.NewStringResponder(http.StatusOK, mockResponse))
 	asset, err := client.GetAsset(context.Background(), "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c")
 	assert.NoError(t, err)
-	assert.NotNil(t, asset)
-	assert.Equal(t, "TON", asset.Asset.Symbol)
+	if assert.NotNil(t, asset) {
+		assert.Equal(t, "TON", asset.Asset.Symbol)
+		assert.Equal(t, 9, asset.Asset.Decimals)
+		assert.Equal(t, types.AssetKindTon, asset.Asset.Kind)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	client, httpClient := newTestClient()
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/assets/EQ...", httpmock.NewStringResponder(http.StatusNotFound, `{"message":"not found"}`))
+	_, err := client.GetAsset(context.Background(), "EQ...")
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.JSONEq(t, `{"message":"not found"}`, string(apiErr.Body))
+	}
+	assert.ErrorContains(t, err, "status code: 404")
 }
 
 func TestGetWalletAssets(t *testing.T) {
@@ -98,7 +117,7 @@ func TestGetPools(t *testing.T) {
 				"apy_7d": "0",
 				"apy_30d": "0",
 				"deprecated": false
-			},
+			}
 		]
 	}`
 	httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/pools", httpmock.NewStringResponder(http.StatusOK, mockResponse))
@@ -138,11 +157,13 @@ func TestGetPool(t *testing.T) {
 		}
 	}`
 
-	httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/pools/EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c", httpmock.NewStringResponder(http.StatusOK, mockResponse))
-	pool, err := client.GetPool(context.Background(), "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c")
+	httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/pools/EQDAJK0GZ2ZhJlHcuNFrGDSyg70s0OZPFCiy29CNkMRomGFZ", httpmock.NewStringResponder(http.StatusOK, mockResponse))
+	pool, err := client.GetPool(context.Background(), "EQDAJK0GZ2ZhJlHcuNFrGDSyg70s0OZPFCiy29CNkMRomGFZ")
 	assert.NoError(t, err)
-	assert.NotNil(t, pool)
-	assert.Equal(t, "TON Pool", pool)
+	if assert.NotNil(t, pool) {
+		assert.Equal(t, "EQDAJK0GZ2ZhJlHcuNFrGDSyg70s0OZPFCiy29CNkMRomGFZ", pool.Pool.Address)
+		assert.Equal(t, "703912430", pool.Pool.Reserve0)
+	}
 }
 
 func TestGetFarms(t *testing.T) {
@@ -193,14 +214,17 @@ func TestGetSimulatedSwapStatus(t *testing.T) {
 	defer httpmock.DeactivateAndReset()
 
 	mockResponse := `{
-		"status": "completed" 
+		"@type": "Found",
+		"exit_code": "swap_ok",
+		"tx_hash": "abc"
 	}`
-	// Assuming the Swagger API's expected output is "completed"
-	httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/swap/status?routerAddress=routerAddress&ownerAddress=ownerAddress&queryId=queryId", httpmock.NewStringResponder(http.StatusOK, mockResponse))
+	httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/swap/status?owner_address=ownerAddress&query_id=queryId&router_address=routerAddress", httpmock.NewStringResponder(http.StatusOK, mockResponse))
 	status, err := client.GetSwapStatus(context.Background(), "routerAddress", "ownerAddress", "queryId")
 	assert.NoError(t, err)
-	assert.NotNil(t, status)
-	assert.Equal(t, "completed", status)
+	if assert.NotNil(t, status) {
+		assert.Equal(t, "Found", status.Type)
+		assert.Equal(t, "swap_ok", status.ExitCode)
+	}
 }
 
 func TestGetSwapRate(t *testing.T) {
@@ -214,13 +238,18 @@ func TestGetSwapRate(t *testing.T) {
 		"min_ask_units": "45451"
 	}`
 
-	url := "https://api.ston.fi/v1/swap/simulate?offer_address=EQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwiuA&ask_address=EQCM3B12QK1e4yZSf8GtBRT0aLMNyEsBc_DhVfRRtOEffLez&units=300&slippage_tolerance=0.001"
-
-	httpmock.RegisterResponder("POST", url, httpmock.NewStringResponder(http.StatusOK, mockResponse))
+	query := map[string]string{
+		"offer_address":      "EQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwiuA",
+		"ask_address":        "EQCM3B12QK1e4yZSf8GtBRT0aLMNyEsBc_DhVfRRtOEffLez",
+		"units":              "300",
+		"slippage_tolerance": "0.001",
+	}
+	httpmock.RegisterResponderWithQuery("POST", "https://api.ston.fi/v1/swap/simulate", query, httpmock.NewStringResponder(http.StatusOK, mockResponse))
 
 	simulation, err := client.SimulateSwap(context.Background(), "EQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwiuA", "EQCM3B12QK1e4yZSf8GtBRT0aLMNyEsBc_DhVfRRtOEffLez", "300", "0.001")
 	assert.NoError(t, err)
-	assert.NotNil(t, simulation)
-
+	if assert.NotNil(t, simulation) {
+		assert.Equal(t, "0.151656666", simulation.SwapRate)
+	}
 }
 		
\ No newline at end of file
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Endpoint is a declarative description of a Ston.fi API route. Path is
+// relative to BaseURLStr and may contain `{param}` placeholders, which are
+// filled from the call's params; the remaining params become the query string.
+type Endpoint struct {
+	Name   string
+	Method string
+	Path   string
+	// NormalizeResponse snake_cases keys and drops null members before
+	// decoding, see utils.SnakeCaseResponse.
+	NormalizeResponse bool
+}
+
+var (
+	getAssetsEndpoint           = Endpoint{Name: "GetAssets", Method: http.MethodGet, Path: "/assets"}
+	getAssetEndpoint            = Endpoint{Name: "GetAsset", Method: http.MethodGet, Path: "/assets/{addr_str}"}
+	searchAssetsEndpoint        = Endpoint{Name: "SearchAssets", Method: http.MethodPost, Path: "/assets/search"}
+	queryAssetsEndpoint         = Endpoint{Name: "QueryAssets", Method: http.MethodPost, Path: "/assets/query"}
+	getFarmsEndpoint            = Endpoint{Name: "GetFarms", Method: http.MethodGet, Path: "/farms"}
+	getFarmEndpoint             = Endpoint{Name: "GetFarm", Method: http.MethodGet, Path: "/farms/{addr_str}"}
+	getFarmsByPoolEndpoint      = Endpoint{Name: "GetFarmsByPool", Method: http.MethodGet, Path: "/farms_by_pool/{pool_addr_str}"}
+	getPoolsEndpoint            = Endpoint{Name: "GetPools", Method: http.MethodGet, Path: "/pools"}
+	getPoolEndpoint             = Endpoint{Name: "GetPool", Method: http.MethodGet, Path: "/pools/{addr_str}"}
+	getSwapStatusEndpoint       = Endpoint{Name: "GetSwapStatus", Method: http.MethodGet, Path: "/swap/status"}
+	simulateSwapEndpoint        = Endpoint{Name: "SimulateSwap", Method: http.MethodPost, Path: "/swap/simulate"}
+	simulateReverseSwapEndpoint = Endpoint{Name: "SimulateReverseSwap", Method: http.MethodPost, Path: "/reverse_swap/simulate"}
+	getStatsEndpoint            = Endpoint{Name: "GetStats", Method: http.MethodGet, Path: "/stats/dex"}
+	getOperationsStatsEndpoint  = Endpoint{Name: "GetHistoricalSwaps", Method: http.MethodGet, Path: "/stats/operations"}
+	getPoolStatsEndpoint        = Endpoint{Name: "GetPoolStats", Method: http.MethodGet, Path: "/stats/pool"}
+	getWalletAssetsEndpoint     = Endpoint{Name: "GetWalletAssets", Method: http.MethodGet, Path: "/wallets/{addr_str}/assets"}
+	getWalletAssetEndpoint      = Endpoint{Name: "GetWalletAsset", Method: http.MethodGet, Path: "/wallets/{addr_str}/assets/{asset_address}"}
+	getWalletFarmsEndpoint      = Endpoint{Name: "GetWalletFarms", Method: http.MethodGet, Path: "/wallets/{addr_str}/farms"}
+	getWalletFarmEndpoint       = Endpoint{Name: "GetWalletFarm", Method: http.MethodGet, Path: "/wallets/{addr_str}/farms/{farm_address}"}
+	getWalletPoolsEndpoint      = Endpoint{Name: "GetWalletPools", Method: http.MethodGet, Path: "/wallets/{addr_str}/pools"}
+	getWalletPoolEndpoint       = Endpoint{Name: "GetWalletPool", Method: http.MethodGet, Path: "/wallets/{addr_str}/pools/{pool_address}"}
+	getWalletOperationsEndpoint = Endpoint{Name: "GetWalletOperations", Method: http.MethodGet, Path: "/wallets/{addr_str}/operations"}
+)
+
+// Call sends a request to endpoint and decodes the JSON response into response.
+// params supplies both path parameters and query parameters; query keys may be
+// given in camelCase and are sent in snake_case.
+func (c *StonfiClient) Call(ctx context.Context, endpoint Endpoint, params url.Values, response interface{}) error {
+	ctx, call, err := c.begin(ctx, endpoint, params)
+	result := &CallResult{}
+	start := time.Now()
+	if err == nil {
+		err = c.request(ctx, call, nil, response, result)
+	}
+	c.end(ctx, call, result, start, err)
+	return err
+}
+
+// begin builds the call to endpoint and runs the Before hooks. The error is
+// set if the params could not fill the endpoint's path.
+func (c *StonfiClient) begin(ctx context.Context, endpoint Endpoint, params url.Values) (context.Context, *CallInfo, error) {
+	call := &CallInfo{Endpoint: endpoint, Params: params, Header: http.Header{}}
+	path, query, err := utils.NormalizeRequest(endpoint.Path, params)
+	if err == nil {
+		call.URL = c.Client.BaseURL + path
+		if q := encodeQuery(query); q != "" {
+			call.URL += "?" + q
+		}
+	} else {
+		err = fmt.Errorf("%s: %w", endpoint.Name, err)
+	}
+
+	for _, h := range c.hooks {
+		ctx = h.Before(ctx, call)
+	}
+	return ctx, call, err
+}
+
+// end completes result and runs the After hooks.
+func (c *StonfiClient) end(ctx context.Context, call *CallInfo, result *CallResult, start time.Time, err error) {
+	result.Latency = time.Since(start)
+	result.Err = err
+	for i := len(c.hooks) - 1; i >= 0; i-- {
+		c.hooks[i].After(ctx, call, result)
+	}
+}
+
+// API returns a client for every operation in the vendored OpenAPI
+// specification, including ones without a handwritten method.
+func (c *StonfiClient) API() *openapi.Client {
+	return &openapi.Client{Caller: c}
+}
+
+// CallOperation implements openapi.Caller.
+func (c *StonfiClient) CallOperation(ctx context.Context, op openapi.Operation, params url.Values, response interface{}) error {
+	return c.Call(ctx, Endpoint{Name: op.ID, Method: op.Method, Path: op.Path}, params, response)
+}
+
+// encodeQuery joins query parameters whose values are already escaped by
+// utils.NormalizeRequest, sorted by key.
+func encodeQuery(query map[string][]string) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, url.QueryEscape(key)+"="+value)
+		}
+	}
+	return strings.Join(parts, "&")
+}
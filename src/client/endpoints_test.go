@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	client, httpClient := newTestClient()
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	t.Run("should escape path parameters", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/wallets/UQ..%2Fa%2Bb/assets", httpmock.NewStringResponder(http.StatusOK, `{"asset_list": []}`))
+		_, err := client.GetWalletAssets(context.Background(), "UQ../a+b")
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail on missing path parameters", func(t *testing.T) {
+		httpmock.Reset()
+		_, err := client.GetWalletAsset(context.Background(), "UQ...", "")
+		assert.ErrorContains(t, err, "asset_address")
+		assert.Equal(t, 0, httpmock.GetTotalCallCount(), "no request is sent")
+	})
+
+	t.Run("should normalize responses on request", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/pools/EQ...", httpmock.NewStringResponder(http.StatusOK,
+			`{"pool": {"address": "EQ...", "lpFee": "20", "deprecated": null, "reserve0": null}}`))
+		endpoint := Endpoint{Name: "GetPool", Method: http.MethodGet, Path: "/pools/{addr_str}", NormalizeResponse: true}
+		var response types.PoolResponse
+		assert.NoError(t, client.Call(context.Background(), endpoint, url.Values{"addr_str": {"EQ..."}}, &response))
+		assert.Equal(t, "EQ...", response.Pool.Address)
+		assert.Equal(t, "20", response.Pool.LpFee)
+		assert.False(t, response.Pool.Deprecated)
+	})
+}
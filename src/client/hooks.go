@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// CallInfo describes an API call to hooks.
+type CallInfo struct {
+	Endpoint Endpoint
+	// Params are the parameters passed to Call, path parameters included.
+	// Hooks must not modify them.
+	Params url.Values
+	// URL is the request URL. It is empty if the params could not fill the
+	// endpoint's path, in which case no request is sent.
+	URL string
+	// Header is sent with the request; hooks may add to it in Before.
+	Header http.Header
+}
+
+// CallResult is the outcome of an API call.
+type CallResult struct {
+	// StatusCode is zero if no response was received.
+	StatusCode int
+	Latency    time.Duration
+	// Bytes is the size of the response body.
+	Bytes int
+	Err   error
+}
+
+// Hook observes every API call. Before runs before the request is sent and
+// returns the context to send it with, so hooks can attach values such as
+// spans; After receives that context once the call is done. Hooks run in the
+// order they were added, and After in reverse order.
+type Hook interface {
+	Before(ctx context.Context, call *CallInfo) context.Context
+	After(ctx context.Context, call *CallInfo, result *CallResult)
+}
+
+// HookFuncs adapts a pair of functions to Hook. Either may be nil.
+type HookFuncs struct {
+	BeforeFunc func(ctx context.Context, call *CallInfo) context.Context
+	AfterFunc  func(ctx context.Context, call *CallInfo, result *CallResult)
+}
+
+// Before implements Hook.
+func (h HookFuncs) Before(ctx context.Context, call *CallInfo) context.Context {
+	if h.BeforeFunc == nil {
+		return ctx
+	}
+	return h.BeforeFunc(ctx, call)
+}
+
+// After implements Hook.
+func (h HookFuncs) After(ctx context.Context, call *CallInfo, result *CallResult) {
+	if h.AfterFunc != nil {
+		h.AfterFunc(ctx, call, result)
+	}
+}
+
+// Use adds hooks that run around every subsequent call.
+func (c *StonfiClient) Use(hooks ...Hook) {
+	c.hooks = append(c.hooks, hooks...)
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+func TestHooks(t *testing.T) {
+	client, httpClient := newTestClient()
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	var order []string
+	var got *CallResult
+	var sawHeader, sawContext bool
+	client.Use(
+		HookFuncs{
+			BeforeFunc: func(ctx context.Context, call *CallInfo) context.Context {
+				order = append(order, "before 1")
+				call.Header.Set("X-Trace", "abc")
+				return context.WithValue(ctx, ctxKey{}, call.Endpoint.Name)
+			},
+			AfterFunc: func(ctx context.Context, call *CallInfo, result *CallResult) {
+				order = append(order, "after 1")
+				sawContext = ctx.Value(ctxKey{}) == "GetPool"
+				got = result
+			},
+		},
+		HookFuncs{AfterFunc: func(ctx context.Context, call *CallInfo, result *CallResult) {
+			order = append(order, "after 2")
+		}},
+	)
+
+	httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/pools/EQ...", func(req *http.Request) (*http.Response, error) {
+		sawHeader = req.Header.Get("X-Trace") == "abc"
+		return httpmock.NewStringResponse(http.StatusOK, `{"pool": {}}`), nil
+	})
+	_, err := client.GetPool(context.Background(), "EQ...")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"before 1", "after 2", "after 1"}, order)
+	assert.True(t, sawHeader)
+	assert.True(t, sawContext)
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+	assert.Equal(t, len(`{"pool": {}}`), got.Bytes)
+	assert.NoError(t, got.Err)
+
+	httpmock.RegisterResponder("GET", "https://api.ston.fi/v1/pools", httpmock.NewStringResponder(http.StatusTooManyRequests, `{"message": "slow down"}`))
+	_, err = client.GetPools(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, got.StatusCode)
+	assert.Equal(t, err, got.Err)
+
+	// Calls that cannot be built still reach the hooks, without a URL.
+	err = client.Call(context.Background(), getPoolEndpoint, url.Values{}, nil)
+	assert.ErrorContains(t, err, "addr_str")
+	assert.Zero(t, got.StatusCode)
+	assert.Equal(t, err, got.Err)
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// matchOperation finds the spec operation whose path template matches path.
+func matchOperation(method, path string) (openapi.Operation, bool) {
+	segments := strings.Split(path, "/")
+	for _, op := range openapi.Operations {
+		template := strings.Split(op.Path, "/")
+		if op.Method != method || len(template) != len(segments) {
+			continue
+		}
+		matches := true
+		for i, s := range template {
+			if !strings.HasPrefix(s, "{") && s != segments[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return op, true
+		}
+	}
+	return openapi.Operation{}, false
+}
+
+// responseSchema returns the schema of the successful response of the
+// operation with the given ID.
+func responseSchema(spec map[string]interface{}, id string) (map[string]interface{}, bool) {
+	for _, item := range spec["paths"].(map[string]interface{}) {
+		for _, o := range item.(map[string]interface{}) {
+			operation := o.(map[string]interface{})
+			if operation["operationId"] != id {
+				continue
+			}
+			response, ok := operation["responses"].(map[string]interface{})["200"].(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			content, _ := response["content"].(map[string]interface{})
+			media, _ := content["application/json"].(map[string]interface{})
+			schema, ok := media["schema"].(map[string]interface{})
+			return schema, ok
+		}
+	}
+	return nil, false
+}
+
+// checkSchema checks that every JSON field of typ, at any depth, is a property
+// of the corresponding spec schema.
+func checkSchema(t *testing.T, spec map[string]interface{}, path string, typ reflect.Type, schema map[string]interface{}) {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		schema = spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})[name].(map[string]interface{})
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		if items, ok := schema["items"].(map[string]interface{}); assert.True(t, ok, "%s: the spec has no array here", path) {
+			checkSchema(t, spec, path+"[]", typ.Elem(), items)
+		}
+	case reflect.Struct:
+		if typ == reflect.TypeOf(time.Time{}) {
+			return
+		}
+		properties, ok := schema["properties"].(map[string]interface{})
+		if !assert.True(t, ok, "%s: the spec has no object here", path) {
+			return
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := field.Tag.Get("json")
+			if field.Anonymous && tag == "" {
+				checkSchema(t, spec, path, field.Type, schema)
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			property, ok := properties[name].(map[string]interface{})
+			if assert.True(t, ok, "%s: %s is not in the spec", path, name) {
+				checkSchema(t, spec, path+"."+name, field.Type, property)
+			}
+		}
+	}
+}
+
+// TestMethodsMatchSpec calls every handwritten method and checks the request it
+// sends and the type it decodes the response into against the vendored
+// OpenAPI specification.
+func TestMethodsMatchSpec(t *testing.T) {
+	client, httpClient := newTestClient()
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	var last *http.Request
+	httpmock.RegisterNoResponder(func(req *http.Request) (*http.Response, error) {
+		last = req
+		return httpmock.NewStringResponse(http.StatusOK, `{}`), nil
+	})
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(openapi.Spec, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	condition := "asset:essential"
+	wallet := "UQ..."
+	calls := map[string]func() (interface{}, error){
+		"GetAsset":            func() (interface{}, error) { return client.GetAsset(ctx, "EQ...") },
+		"GetAssets":           func() (interface{}, error) { return client.GetAssets(ctx) },
+		"GetFarm":             func() (interface{}, error) { return client.GetFarm(ctx, "EQ...") },
+		"GetFarms":            func() (interface{}, error) { return client.GetFarms(ctx) },
+		"GetPools":            func() (interface{}, error) { return client.GetPools(ctx) },
+		"GetPool":             func() (interface{}, error) { return client.GetPool(ctx, "EQ...") },
+		"GetSwapStatus":       func() (interface{}, error) { return client.GetSwapStatus(ctx, "EQ...", wallet, "1") },
+		"GetPoolByAddress":    func() (interface{}, error) { return client.GetPoolByAddress(ctx, "EQ...") },
+		"SimulateSwap":        func() (interface{}, error) { return client.SimulateSwap(ctx, "EQ...", "EQ...", "1", "0.01") },
+		"SimulateReverseSwap": func() (interface{}, error) { return client.SimulateReverseSwap(ctx, "EQ...", "EQ...", "1", "0.01") },
+		"SearchAssets":        func() (interface{}, error) { return client.SearchAssets(ctx, "TON", &condition, &wallet) },
+		"QueryAssets":         func() (interface{}, error) { return client.QueryAssets(ctx, condition, []string{"EQ..."}, wallet) },
+		"GetFarmsByPool":      func() (interface{}, error) { return client.GetFarmsByPool(ctx, "EQ...") },
+		"GetStats":            func() (interface{}, error) { return client.GetStats(ctx, start, end) },
+		"GetHistoricalSwaps":  func() (interface{}, error) { return client.GetHistoricalSwaps(ctx, start, end) },
+		"GetPoolStats":        func() (interface{}, error) { return client.GetPoolStats(ctx, start, end) },
+		"GetWalletAssets":     func() (interface{}, error) { return client.GetWalletAssets(ctx, wallet) },
+		"GetWalletAsset":      func() (interface{}, error) { return client.GetWalletAsset(ctx, wallet, "EQ...") },
+		"GetWalletFarms":      func() (interface{}, error) { return client.GetWalletFarms(ctx, wallet) },
+		"GetWalletFarm":       func() (interface{}, error) { return client.GetWalletFarm(ctx, wallet, "EQ...") },
+		"GetWalletPools":      func() (interface{}, error) { return client.GetWalletPools(ctx, wallet) },
+		"GetWalletPool":       func() (interface{}, error) { return client.GetWalletPool(ctx, wallet, "EQ...") },
+		"GetWalletOperations": func() (interface{}, error) { return client.GetWalletOperations(ctx, wallet) },
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			last = nil
+			response, _ := call()
+			if !assert.NotNil(t, last, "no request sent") {
+				return
+			}
+			path := strings.TrimPrefix(last.URL.EscapedPath(), "/v1")
+			op, ok := matchOperation(last.Method, path)
+			if !assert.True(t, ok, "%s %s is not in the spec", last.Method, path) {
+				return
+			}
+			query := last.URL.Query()
+			accepted := map[string]bool{}
+			for _, p := range op.QueryParams {
+				accepted[p.Name] = true
+				if p.Required {
+					assert.True(t, query.Has(p.Name), "%s: missing required query parameter %s", op.ID, p.Name)
+				}
+			}
+			for key := range query {
+				assert.True(t, accepted[key], "%s: query parameter %s is not in the spec", op.ID, key)
+			}
+			schema, ok := responseSchema(spec, op.ID)
+			if assert.True(t, ok, "%s: no response schema in the spec", op.ID) {
+				checkSchema(t, spec, op.ID, reflect.TypeOf(response), schema)
+			}
+		})
+	}
+}
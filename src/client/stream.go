@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/types"
+)
+
+// StreamAssets is GetAssets decoding one asset at a time from the response
+// body, instead of the whole list at once. Only the assets keep accepts are
+// yielded; a nil keep accepts every asset. An error is yielded last.
+func (c *StonfiClient) StreamAssets(ctx context.Context, keep func(types.Asset) bool) iter.Seq2[types.Asset, error] {
+	return stream(ctx, c, getAssetsEndpoint, nil, "asset_list", keep)
+}
+
+// StreamPools is GetPools decoding one pool at a time from the response
+// body. Only the pools keep accepts are yielded; a nil keep accepts every
+// pool. An error is yielded last.
+func (c *StonfiClient) StreamPools(ctx context.Context, keep func(types.Pool) bool) iter.Seq2[types.Pool, error] {
+	return stream(ctx, c, getPoolsEndpoint, nil, "pool_list", keep)
+}
+
+// StreamHistoricalSwaps is GetHistoricalSwaps decoding one operation at a
+// time from the response body. Only the operations keep accepts are
+// yielded; a nil keep accepts every operation. An error is yielded last.
+func (c *StonfiClient) StreamHistoricalSwaps(ctx context.Context, startDate, endDate time.Time, keep func(types.OperationInfo) bool) iter.Seq2[types.OperationInfo, error] {
+	if err := checkValidTimeRange(startDate, endDate); err != nil {
+		return func(yield func(types.OperationInfo, error) bool) {
+			yield(types.OperationInfo{}, err)
+		}
+	}
+	return stream(ctx, c, getOperationsStatsEndpoint, timeRangeParams(startDate, endDate), "operations", keep)
+}
+
+// stream calls endpoint and yields the elements of the array under key in
+// the response object as they are decoded. Hooks see the call end once the
+// array is done, or the caller stops early.
+func stream[T any](ctx context.Context, c *StonfiClient, endpoint Endpoint, params url.Values, key string, keep func(T) bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, call, err := c.begin(ctx, endpoint, params)
+		result := &CallResult{}
+		start := time.Now()
+		if err == nil {
+			err = c.decodeList(ctx, call, key, result, func(d *json.Decoder) (bool, error) {
+				var v T
+				if err := d.Decode(&v); err != nil {
+					return false, err
+				}
+				if keep != nil && !keep(v) {
+					return true, nil
+				}
+				return yield(v, nil), nil
+			})
+		}
+		c.end(ctx, call, result, start, err)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// decodeList sends the call and calls next for each element of the array
+// under key, until next returns false. Other keys are skipped.
+func (c *StonfiClient) decodeList(ctx context.Context, call *CallInfo, key string, result *CallResult, next func(*json.Decoder) (bool, error)) error {
+	req := c.Client.R().SetContext(ctx).DisableAutoReadResponse()
+	for name, values := range call.Header {
+		req.SetHeader(name, strings.Join(values, ", "))
+	}
+	resp, err := req.Send(call.Endpoint.Method, call.URL)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+	result.StatusCode = resp.StatusCode
+	body := &countingReader{r: resp.Body}
+	defer func() { result.Bytes = body.n }()
+	if !resp.IsSuccessState() {
+		data, _ := io.ReadAll(body)
+		return &APIError{StatusCode: resp.StatusCode, Body: data}
+	}
+
+	d := json.NewDecoder(body)
+	if err := expectDelim(d, '{'); err != nil {
+		return err
+	}
+	for d.More() {
+		token, err := d.Token()
+		if err != nil {
+			return fmt.Errorf("JSON unmarshal error: %s", err)
+		}
+		if token != key {
+			var skip json.RawMessage
+			if err := d.Decode(&skip); err != nil {
+				return fmt.Errorf("JSON unmarshal error: %s", err)
+			}
+			continue
+		}
+		if err := expectDelim(d, '['); err != nil {
+			return err
+		}
+		for d.More() {
+			more, err := next(d)
+			if err != nil {
+				return fmt.Errorf("JSON unmarshal error: %s", err)
+			}
+			if !more {
+				return nil
+			}
+		}
+		if err := expectDelim(d, ']'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expectDelim(d *json.Decoder, want json.Delim) error {
+	token, err := d.Token()
+	if err != nil {
+		return fmt.Errorf("JSON unmarshal error: %s", err)
+	}
+	if token != want {
+		return fmt.Errorf("JSON unmarshal error: got %v, want %v", token, want)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamPools(t *testing.T) {
+	client, httpClient := newTestClient()
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	var pools []string
+	for i := range 100 {
+		pools = append(pools, fmt.Sprintf(`{"address":"p%d","reserve0":"%d","deprecated":%t}`, i, i, i%3 == 0))
+	}
+	body := `{"meta":{"nested":[1,{"a":2}]},"pool_list":[` + strings.Join(pools, ",") + `]}`
+	httpmock.RegisterResponder(http.MethodGet, "https://api.ston.fi/v1/pools", httpmock.NewStringResponder(http.StatusOK, body))
+	var result *CallResult
+	client.Use(HookFuncs{AfterFunc: func(ctx context.Context, call *CallInfo, r *CallResult) { result = r }})
+
+	var got []types.Pool
+	for p, err := range client.StreamPools(context.Background(), func(p types.Pool) bool { return !p.Deprecated }) {
+		if !assert.NoError(t, err) {
+			return
+		}
+		got = append(got, p)
+	}
+	if assert.Len(t, got, 66) {
+		assert.Equal(t, "p1", got[0].Address)
+		assert.Equal(t, "98", got[65].Reserve0)
+	}
+	if assert.NotNil(t, result) {
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		assert.Equal(t, len(body), result.Bytes)
+	}
+
+	n := 0
+	for range client.StreamPools(context.Background(), nil) {
+		if n++; n == 5 {
+			break
+		}
+	}
+	assert.Equal(t, 5, n, "stops when the caller does")
+}
+
+func TestStreamErrors(t *testing.T) {
+	client, httpClient := newTestClient()
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://api.ston.fi/v1/assets",
+		httpmock.NewStringResponder(http.StatusOK, `{"asset_list":[{"symbol":"TON"},{"symbol":"USDT","blacklisted":true},{"symbol":`))
+	var symbols []string
+	var last error
+	for a, err := range client.StreamAssets(context.Background(), func(a types.Asset) bool { return !a.Blacklisted }) {
+		if err != nil {
+			last = err
+			continue
+		}
+		symbols = append(symbols, a.Symbol)
+	}
+	assert.Equal(t, []string{"TON"}, symbols, "assets before a truncation are yielded")
+	assert.ErrorContains(t, last, "JSON unmarshal error")
+
+	httpmock.RegisterResponder(http.MethodGet, `=~^https://api.ston.fi/v1/stats/operations`,
+		httpmock.NewStringResponder(http.StatusBadRequest, `{"message":"bad range","code":400}`))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, err := range client.StreamHistoricalSwaps(context.Background(), start, start.Add(time.Hour), nil) {
+		assert.ErrorContains(t, err, "status code: 400")
+	}
+	for _, err := range client.StreamHistoricalSwaps(context.Background(), start, start.Add(-time.Hour), nil) {
+		assert.Error(t, err, "invalid range")
+	}
+}
@@ -4,6 +4,11 @@ package client
 
 type StonfiClientOptions struct {
 	BaseURL string
+	// Hooks run around every call, see StonfiClient.Use.
+	Hooks []Hook
+	// BatchConcurrency is how many calls the batch methods, such as
+	// GetPoolsByAddress, make at once. Defaults to DefaultBatchConcurrency.
+	BatchConcurrency int
 }
 
 type MarketListResponse struct {
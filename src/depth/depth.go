@@ -0,0 +1,230 @@
+// Package depth measures how much a pool can absorb: the largest trade in
+// each direction before the price impact exceeds a limit, and the full curve
+// of price impact against size. It uses the swap math of the amm package, so
+// the numbers match a swap simulation.
+package depth
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/itay747/go-stonfi/src/amm"
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// DefaultLevels are the price impacts depth is measured at by default.
+var DefaultLevels = []float64{0.005, 0.01, 0.02, 0.05}
+
+// Options configures Analyze.
+type Options struct {
+	// Levels are the price impacts to measure depth at. Defaults to
+	// DefaultLevels.
+	Levels []float64
+	// CurvePoints is the number of points of each curve. Defaults to 50;
+	// negative leaves the curves out.
+	CurvePoints int
+	// WithReferral includes the referral fee, as a swap with a referral
+	// address would pay.
+	WithReferral bool
+}
+
+// Level is the largest swap whose price impact is at most Impact. Price
+// impact includes the pool's fees, as in a swap simulation, so levels below
+// the fees have no depth.
+type Level struct {
+	Impact     float64
+	OfferUnits *big.Int
+	AskUnits   *big.Int
+}
+
+// Point is a point of a depth curve.
+type Point struct {
+	OfferUnits  *big.Int
+	AskUnits    *big.Int
+	PriceImpact float64
+}
+
+// Direction is the depth of swaps from OfferAddress to AskAddress.
+type Direction struct {
+	OfferAddress string
+	AskAddress   string
+	Levels       []Level
+	// Curve has points from a millionth of the offer reserve up to the
+	// whole of it, spaced evenly on a log scale.
+	Curve []Point
+}
+
+// Depth is the depth of a pool in both directions: Directions[0] offers
+// token0, Directions[1] token1.
+type Depth struct {
+	Pool       types.Pool
+	Directions [2]Direction
+}
+
+// ForPool fetches the pool at address and analyzes it.
+func ForPool(ctx context.Context, c *client.StonfiClient, address string, opts Options) (*Depth, error) {
+	response, err := c.GetPool(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("depth: fetching pool %s: %w", address, err)
+	}
+	return Analyze(response.Pool, opts)
+}
+
+// Analyze measures the depth of pool.
+func Analyze(pool types.Pool, opts Options) (*Depth, error) {
+	if len(opts.Levels) == 0 {
+		opts.Levels = DefaultLevels
+	}
+	if opts.CurvePoints == 0 {
+		opts.CurvePoints = 50
+	}
+	d := &Depth{Pool: pool}
+	for i, dir := range [2][2]string{{pool.Token0Address, pool.Token1Address}, {pool.Token1Address, pool.Token0Address}} {
+		side, err := amm.Orient(pool, dir[0])
+		if err != nil {
+			return nil, fmt.Errorf("depth: pool %s: %w", pool.Address, err)
+		}
+		direction := Direction{OfferAddress: dir[0], AskAddress: dir[1]}
+		for _, impact := range opts.Levels {
+			direction.Levels = append(direction.Levels, levelAt(side, impact, opts.WithReferral))
+		}
+		if opts.CurvePoints > 0 {
+			direction.Curve = curve(side, opts.CurvePoints, opts.WithReferral)
+		}
+		d.Directions[i] = direction
+	}
+	return d, nil
+}
+
+// Level returns the depth at impact of swaps offering offerAddress.
+func (d *Depth) Level(offerAddress string, impact float64) (Level, bool) {
+	for _, dir := range d.Directions {
+		if !utils.SameAddress(dir.OfferAddress, offerAddress) {
+			continue
+		}
+		for _, l := range dir.Levels {
+			if l.Impact == impact {
+				return l, true
+			}
+		}
+	}
+	return Level{}, false
+}
+
+// levelAt finds the largest offer whose price impact is at most impact.
+// Price impact grows with the offer, and offering the whole reserve halves
+// the price, so a binary search up to the reserve finds it.
+func levelAt(side *amm.Side, impact float64, withReferral bool) Level {
+	level := Level{Impact: impact, OfferUnits: new(big.Int), AskUnits: new(big.Int)}
+	if side.OfferReserve.Sign() <= 0 || side.AskReserve.Sign() <= 0 {
+		return level
+	}
+	within := func(offer *big.Int) (*amm.Quote, bool) {
+		q, err := side.Swap(offer, withReferral)
+		if err != nil {
+			return nil, false
+		}
+		return q, q.PriceImpact <= impact
+	}
+	lo, hi := new(big.Int), new(big.Int).Set(side.OfferReserve)
+	one := big.NewInt(1)
+	for new(big.Int).Sub(hi, lo).Cmp(one) > 0 {
+		mid := new(big.Int).Add(lo, hi)
+		mid.Rsh(mid, 1)
+		if _, ok := within(mid); ok {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	if lo.Sign() > 0 {
+		if q, ok := within(lo); ok {
+			level.OfferUnits, level.AskUnits = q.OfferUnits, q.AskUnits
+		}
+	}
+	return level
+}
+
+// curve returns n points from a millionth of the offer reserve up to the
+// whole of it.
+func curve(side *amm.Side, n int, withReferral bool) []Point {
+	if side.OfferReserve.Sign() <= 0 || side.AskReserve.Sign() <= 0 {
+		return nil
+	}
+	reserve := new(big.Float).SetInt(side.OfferReserve)
+	var points []Point
+	for i := range n {
+		exponent := -6.0
+		if n > 1 {
+			exponent += 6 * float64(i) / float64(n-1)
+		}
+		offer, _ := new(big.Float).Mul(reserve, big.NewFloat(math.Pow(10, exponent))).Int(nil)
+		if offer.Sign() <= 0 || (len(points) > 0 && offer.Cmp(points[len(points)-1].OfferUnits) <= 0) {
+			continue
+		}
+		q, err := side.Swap(offer, withReferral)
+		if err != nil {
+			continue
+		}
+		points = append(points, Point{OfferUnits: q.OfferUnits, AskUnits: q.AskUnits, PriceImpact: q.PriceImpact})
+	}
+	return points
+}
+
+// Ranked is a pool's depth for a pair at one impact, in units of the first
+// asset of the pair.
+type Ranked struct {
+	Pool types.Pool
+	// Sell is how much of the asset can be sold into the pool, and Buy how
+	// much bought from it, within the impact.
+	Sell *big.Int
+	Buy  *big.Int
+}
+
+// Rank returns the non-deprecated pools of the pair (asset, quote), deepest
+// first at impact. A pool's depth is the smaller of how much asset can be
+// sold into it and bought from it.
+func Rank(pools []types.Pool, asset, quote string, impact float64) []Ranked {
+	var ranked []Ranked
+	for _, pool := range pools {
+		if pool.Deprecated {
+			continue
+		}
+		pair := (utils.SameAddress(pool.Token0Address, asset) && utils.SameAddress(pool.Token1Address, quote)) ||
+			(utils.SameAddress(pool.Token1Address, asset) && utils.SameAddress(pool.Token0Address, quote))
+		if !pair {
+			continue
+		}
+		// The API leaves the reserves of some pools empty; they have no depth.
+		sell, err := amm.Orient(pool, asset)
+		if err != nil {
+			continue
+		}
+		buy, err := amm.Orient(pool, quote)
+		if err != nil {
+			continue
+		}
+		ranked = append(ranked, Ranked{
+			Pool: pool,
+			Sell: levelAt(sell, impact, false).OfferUnits,
+			Buy:  levelAt(buy, impact, false).AskUnits,
+		})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Depth().Cmp(ranked[j].Depth()) > 0
+	})
+	return ranked
+}
+
+// Depth is the smaller of Sell and Buy.
+func (r Ranked) Depth() *big.Int {
+	if r.Sell.Cmp(r.Buy) < 0 {
+		return r.Sell
+	}
+	return r.Buy
+}
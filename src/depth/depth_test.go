@@ -0,0 +1,111 @@
+package depth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/itay747/go-stonfi/src/amm"
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+)
+
+func testPool(address, reserve0, reserve1, lpFee string) types.Pool {
+	return types.Pool{
+		Address:       address,
+		Token0Address: tonAddress,
+		Token1Address: usdtAddress,
+		Reserve0:      reserve0,
+		Reserve1:      reserve1,
+		LpFee:         lpFee,
+		ProtocolFee:   "0",
+	}
+}
+
+func TestAnalyzeWithoutFees(t *testing.T) {
+	// Without fees, offering x of a reserve R has a price impact of x/(R+x),
+	// so the depth at 1% is R/99, less what the output rounding costs.
+	d, err := Analyze(testPool(poolAddress, "990000000000", "5000000000", "0"), Options{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	level, ok := d.Level(tonAddress, 0.01)
+	if assert.True(t, ok) {
+		offer, _ := new(big.Float).SetInt(level.OfferUnits).Float64()
+		assert.InEpsilon(t, 1e10, offer, 1e-6)
+	}
+	assert.Len(t, d.Directions[0].Levels, len(DefaultLevels))
+	assert.Equal(t, usdtAddress, d.Directions[1].OfferAddress)
+}
+
+func TestAnalyzeMatchesSimulation(t *testing.T) {
+	pool := testPool(poolAddress, "1000000000000", "5000000000", "30")
+	d, err := Analyze(pool, Options{Levels: []float64{0.001, 0.005, 0.01, 0.02, 0.05}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, dir := range d.Directions {
+		levels := dir.Levels
+		assert.Zero(t, levels[0].OfferUnits.Sign(), "no swap has less impact than the 0.3% fee")
+		for i, level := range levels[1:] {
+			assert.Positive(t, level.OfferUnits.Cmp(levels[i].OfferUnits), "depth grows with the impact")
+
+			q, err := amm.SimulateSwap(pool, dir.OfferAddress, level.OfferUnits, false)
+			assert.NoError(t, err)
+			assert.Equal(t, q.AskUnits, level.AskUnits)
+			assert.LessOrEqual(t, q.PriceImpact, level.Impact)
+			q, err = amm.SimulateSwap(pool, dir.OfferAddress, new(big.Int).Add(level.OfferUnits, big.NewInt(1)), false)
+			assert.NoError(t, err)
+			assert.Greater(t, q.PriceImpact, level.Impact, "one more unit exceeds the impact")
+		}
+
+		curve := dir.Curve
+		assert.Len(t, curve, 50)
+		for i := 1; i < len(curve); i++ {
+			// Output rounding adds noise to the impact of the smallest swaps.
+			assert.Greater(t, curve[i].PriceImpact, curve[i-1].PriceImpact-1e-4)
+		}
+		assert.Greater(t, curve[len(curve)-1].PriceImpact, 0.5, "the curve ends at the whole reserve")
+	}
+}
+
+func TestRank(t *testing.T) {
+	pools := []types.Pool{
+		testPool("shallow", "100000000000", "500000000", "30"),
+		testPool("deep", "1000000000000", "5000000000", "30"),
+		testPool("empty", "", "", "30"),
+		{Address: "other", Token0Address: tonAddress, Token1Address: poolAddress, Reserve0: "1", Reserve1: "1"},
+	}
+	deprecated := testPool("deprecated", "1000000000000000", "5000000000000", "30")
+	deprecated.Deprecated = true
+	pools = append(pools, deprecated)
+
+	ranked := Rank(pools, usdtAddress, tonAddress, 0.01)
+	if !assert.Len(t, ranked, 2) {
+		return
+	}
+	assert.Equal(t, "deep", ranked[0].Pool.Address)
+	assert.Equal(t, "shallow", ranked[1].Pool.Address)
+	assert.Positive(t, ranked[1].Depth().Sign())
+}
+
+func TestForPool(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Pools: []types.Pool{testPool(poolAddress, "1000000000000", "5000000000", "20")}})
+	defer s.Close()
+
+	d, err := ForPool(context.Background(), s.Client(), poolAddress, Options{CurvePoints: -1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, d.Directions[0].Curve)
+	level, ok := d.Level(usdtAddress, 0.05)
+	assert.True(t, ok)
+	assert.Positive(t, level.OfferUnits.Sign())
+}
@@ -0,0 +1,57 @@
+package export
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Amount is a token amount in smallest units, stored as a 128-bit big-endian
+// two's complement integer: a Parquet DECIMAL(38, 0). TON coin and jetton
+// amounts are at most 120 bits, and deltas are signed, so every amount fits.
+type Amount [16]byte
+
+var (
+	maxAmount = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	minAmount = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+	two128    = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+// ParseAmount parses a decimal integer. The empty string is zero.
+func ParseAmount(s string) (Amount, error) {
+	if s == "" {
+		return Amount{}, nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid amount %q", s)
+	}
+	return AmountOf(n)
+}
+
+// AmountOf converts n, which must fit in 128 signed bits.
+func AmountOf(n *big.Int) (Amount, error) {
+	var a Amount
+	if n.Cmp(maxAmount) > 0 || n.Cmp(minAmount) < 0 {
+		return a, fmt.Errorf("amount %s does not fit in 128 bits", n)
+	}
+	v := n
+	if n.Sign() < 0 {
+		v = new(big.Int).Add(n, two128)
+	}
+	v.FillBytes(a[:])
+	return a, nil
+}
+
+// Int returns the amount as a big.Int.
+func (a Amount) Int() *big.Int {
+	n := new(big.Int).SetBytes(a[:])
+	if a[0]&0x80 != 0 {
+		n.Sub(n, two128)
+	}
+	return n
+}
+
+// String returns the amount in decimal.
+func (a Amount) String() string {
+	return a.Int().String()
+}
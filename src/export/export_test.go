@@ -0,0 +1,183 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	wallet      = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func swap(hash string, lt int64, timestamp, delta0, delta1 string) types.OperationInfo {
+	return types.OperationInfo{
+		Operation: types.Operation{
+			PoolTxHash:      hash,
+			PoolTxLt:        lt,
+			PoolTxTimestamp: timestamp,
+			WalletTxLt:      "41000000000001",
+			PoolAddress:     poolAddress,
+			WalletAddress:   wallet,
+			Asset0Address:   tonAddress,
+			Asset1Address:   usdtAddress,
+			Asset0Delta:     delta0,
+			Asset1Delta:     delta1,
+			OperationType:   "swap",
+			Success:         true,
+		},
+		Asset0Info: types.Asset{Symbol: "TON", Decimals: 9, DexPriceUsd: "5.1"},
+		Asset1Info: types.Asset{Symbol: "USDT", Decimals: 6, DexPriceUsd: "1"},
+	}
+}
+
+func fixtures() stonfitest.Fixtures {
+	return stonfitest.Fixtures{Operations: types.Operations{
+		swap("b", 2, "2024-01-01T12:00:00", "-2000000000", "10000000"),
+		swap("a", 1, "2024-01-01T10:00:00", "1000000000", "-5000000"),
+		swap("c", 3, "2024-01-02T00:00:00", "1000000000", "-4000000"), // on a window boundary
+		swap("d", 4, "2024-01-02T06:00:00", "-1000000000", "6000000"),
+	}}
+}
+
+func TestAmount(t *testing.T) {
+	for _, s := range []string{"0", "1", "-1", "1329227995784915872903807060280344575", "-170141183460469231731687303715884105728"} {
+		a, err := ParseAmount(s)
+		assert.NoError(t, err)
+		assert.Equal(t, s, a.String())
+	}
+	_, err := ParseAmount("170141183460469231731687303715884105728")
+	assert.ErrorContains(t, err, "does not fit")
+	_, err = ParseAmount("1.5")
+	assert.ErrorContains(t, err, "invalid amount")
+	_, err = AmountOf(new(big.Int).Lsh(big.NewInt(1), 130))
+	assert.Error(t, err)
+}
+
+func TestOperationsParquet(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(fixtures())
+	defer s.Close()
+
+	var buf bytes.Buffer
+	n, err := Copy(NewParquetWriter[OperationRow](&buf), OperationRows(Operations(context.Background(), s.Client(), start, start.Add(36*time.Hour))))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n, "operations on window boundaries are exported once")
+	assert.Len(t, s.Requests(), 2)
+
+	rows, err := parquet.Read[OperationRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if !assert.NoError(t, err) || !assert.Len(t, rows, 4) {
+		return
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, []string{rows[0].PoolTxHash, rows[1].PoolTxHash, rows[2].PoolTxHash, rows[3].PoolTxHash})
+	first := rows[0]
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), first.PoolTxTimestamp.UTC())
+	assert.Equal(t, int64(41000000000001), first.WalletTxLt)
+	assert.Equal(t, "-5000000", first.Asset1Delta.String())
+	assert.Equal(t, "TON", first.Asset0Symbol)
+	assert.Equal(t, int32(9), first.Asset0Decimals)
+	if assert.NotNil(t, first.Asset0PriceUsd) {
+		assert.Equal(t, 5.1, *first.Asset0PriceUsd)
+	}
+
+	schema := parquet.SchemaOf(OperationRow{})
+	column, ok := schema.Lookup("asset0_delta")
+	if assert.True(t, ok) {
+		assert.Equal(t, "DECIMAL(38,0)", column.Node.Type().LogicalType().String())
+	}
+	column, ok = schema.Lookup("pool_tx_timestamp")
+	if assert.True(t, ok) {
+		assert.NotNil(t, column.Node.Type().LogicalType().Timestamp)
+	}
+	column, ok = schema.Lookup("asset0_price_usd")
+	if assert.True(t, ok) {
+		assert.True(t, column.Node.Optional(), "a missing price is null")
+	}
+
+	row, err := OperationRowOf(types.OperationInfo{Operation: types.Operation{PoolTxHash: "e"}})
+	if assert.NoError(t, err) {
+		assert.Nil(t, row.Asset0PriceUsd)
+	}
+}
+
+func TestCandlesCSV(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(fixtures())
+	defer s.Close()
+
+	var buf bytes.Buffer
+	ops := Operations(context.Background(), s.Client(), start, start.Add(36*time.Hour))
+	n, err := Copy(NewCSVWriter[CandleRow](&buf), CandleRows(ops, 24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if !assert.NoError(t, err) || !assert.Len(t, records, 3) {
+		return
+	}
+	assert.Equal(t, []string{"pool_address", "asset0_address", "asset1_address", "start", "interval_seconds",
+		"open", "high", "low", "close", "volume0", "volume1", "trades"}, records[0])
+	assert.Equal(t, []string{poolAddress, tonAddress, usdtAddress, "2024-01-01T00:00:00Z", "86400",
+		"5", "5", "5", "5", "3000000000", "15000000", "2"}, records[1])
+	assert.Equal(t, "2024-01-02T00:00:00Z", records[2][3])
+}
+
+func TestPoolStatsCSV(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{
+		Assets: []types.Asset{
+			{ContractAddress: tonAddress, Symbol: "TON", Decimals: 9},
+			{ContractAddress: usdtAddress, Symbol: "USDT", Decimals: 6},
+		},
+		Pools: []types.Pool{{
+			Address:       poolAddress,
+			Token0Address: tonAddress,
+			Token1Address: usdtAddress,
+			Reserve0:      "2000000000000",
+			Reserve1:      "10000000000",
+			Apy1D:         "0.12",
+		}},
+	})
+	defer s.Close()
+
+	var buf bytes.Buffer
+	n, err := Copy(NewCSVWriter[PoolStatsRow](&buf), PoolStats(context.Background(), s.Client(), start, start.Add(48*time.Hour)))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n, "one row per pool per day")
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if !assert.NoError(t, err) || !assert.Len(t, records, 3) {
+		return
+	}
+	row := map[string]string{}
+	for i, name := range records[0] {
+		row[name] = records[2][i]
+	}
+	assert.Equal(t, "2024-01-02T00:00:00Z", row["since"])
+	assert.Equal(t, "2024-01-03T00:00:00Z", row["until"])
+	assert.Equal(t, "TON", row["base_symbol"])
+	assert.Equal(t, "2000", row["base_liquidity"])
+	assert.Equal(t, "0.12", row["apy"])
+	assert.Equal(t, "0", row["base_volume"])
+	assert.Equal(t, "", row["lp_price_usd"], "a stat the API does not report is empty, not 0")
+}
+
+func TestCopyStopsAtError(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(fixtures())
+	defer s.Close()
+	s.FailNext(500, 10)
+
+	var buf bytes.Buffer
+	_, err := Copy(NewCSVWriter[OperationRow](&buf), OperationRows(Operations(context.Background(), s.Client(), start, start.Add(time.Hour))))
+	assert.Error(t, err)
+}
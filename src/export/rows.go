@@ -0,0 +1,212 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/candles"
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// OperationRow is an operation with the symbols, decimals and prices of its
+// assets. Amounts are in smallest units; a price the API does not report is
+// null.
+type OperationRow struct {
+	PoolTxHash               string    `parquet:"pool_tx_hash" csv:"pool_tx_hash"`
+	PoolTxLt                 int64     `parquet:"pool_tx_lt" csv:"pool_tx_lt"`
+	PoolTxTimestamp          time.Time `parquet:"pool_tx_timestamp,timestamp(millisecond)" csv:"pool_tx_timestamp"`
+	WalletTxHash             string    `parquet:"wallet_tx_hash" csv:"wallet_tx_hash"`
+	WalletTxLt               int64     `parquet:"wallet_tx_lt" csv:"wallet_tx_lt"`
+	WalletTxTimestamp        time.Time `parquet:"wallet_tx_timestamp,timestamp(millisecond)" csv:"wallet_tx_timestamp"`
+	OperationType            string    `parquet:"operation_type,dict" csv:"operation_type"`
+	Success                  bool      `parquet:"success" csv:"success"`
+	ExitCode                 string    `parquet:"exit_code,dict" csv:"exit_code"`
+	PoolAddress              string    `parquet:"pool_address,dict" csv:"pool_address"`
+	RouterAddress            string    `parquet:"router_address,dict" csv:"router_address"`
+	WalletAddress            string    `parquet:"wallet_address" csv:"wallet_address"`
+	DestinationWalletAddress string    `parquet:"destination_wallet_address" csv:"destination_wallet_address"`
+	Asset0Address            string    `parquet:"asset0_address,dict" csv:"asset0_address"`
+	Asset0Symbol             string    `parquet:"asset0_symbol,dict" csv:"asset0_symbol"`
+	Asset0Decimals           int32     `parquet:"asset0_decimals" csv:"asset0_decimals"`
+	Asset0PriceUsd           *float64  `parquet:"asset0_price_usd,optional" csv:"asset0_price_usd"`
+	Asset0Amount             Amount    `parquet:"asset0_amount,decimal(0:38)" csv:"asset0_amount"`
+	Asset0Delta              Amount    `parquet:"asset0_delta,decimal(0:38)" csv:"asset0_delta"`
+	Asset0Reserve            Amount    `parquet:"asset0_reserve,decimal(0:38)" csv:"asset0_reserve"`
+	Asset1Address            string    `parquet:"asset1_address,dict" csv:"asset1_address"`
+	Asset1Symbol             string    `parquet:"asset1_symbol,dict" csv:"asset1_symbol"`
+	Asset1Decimals           int32     `parquet:"asset1_decimals" csv:"asset1_decimals"`
+	Asset1PriceUsd           *float64  `parquet:"asset1_price_usd,optional" csv:"asset1_price_usd"`
+	Asset1Amount             Amount    `parquet:"asset1_amount,decimal(0:38)" csv:"asset1_amount"`
+	Asset1Delta              Amount    `parquet:"asset1_delta,decimal(0:38)" csv:"asset1_delta"`
+	Asset1Reserve            Amount    `parquet:"asset1_reserve,decimal(0:38)" csv:"asset1_reserve"`
+	LpTokenDelta             Amount    `parquet:"lp_token_delta,decimal(0:38)" csv:"lp_token_delta"`
+	LpTokenSupply            Amount    `parquet:"lp_token_supply,decimal(0:38)" csv:"lp_token_supply"`
+	LpFeeAmount              Amount    `parquet:"lp_fee_amount,decimal(0:38)" csv:"lp_fee_amount"`
+	ProtocolFeeAmount        Amount    `parquet:"protocol_fee_amount,decimal(0:38)" csv:"protocol_fee_amount"`
+	ReferralFeeAmount        Amount    `parquet:"referral_fee_amount,decimal(0:38)" csv:"referral_fee_amount"`
+	FeeAssetAddress          string    `parquet:"fee_asset_address,dict" csv:"fee_asset_address"`
+	ReferralAddress          string    `parquet:"referral_address" csv:"referral_address"`
+}
+
+// OperationRowOf converts an operation.
+func OperationRowOf(info types.OperationInfo) (OperationRow, error) {
+	op := info.Operation
+	row := OperationRow{
+		PoolTxHash:               op.PoolTxHash,
+		PoolTxLt:                 op.PoolTxLt,
+		WalletTxHash:             op.WalletTxHash,
+		OperationType:            op.OperationType,
+		Success:                  op.Success,
+		ExitCode:                 op.ExitCode,
+		PoolAddress:              op.PoolAddress,
+		RouterAddress:            op.RouterAddress,
+		WalletAddress:            op.WalletAddress,
+		DestinationWalletAddress: op.DestinationWalletAddress,
+		Asset0Address:            op.Asset0Address,
+		Asset0Symbol:             info.Asset0Info.Symbol,
+		Asset0Decimals:           int32(info.Asset0Info.Decimals),
+		Asset0PriceUsd:           optionalFloat(info.Asset0Info.DexPriceUsd),
+		Asset1Address:            op.Asset1Address,
+		Asset1Symbol:             info.Asset1Info.Symbol,
+		Asset1Decimals:           int32(info.Asset1Info.Decimals),
+		Asset1PriceUsd:           optionalFloat(info.Asset1Info.DexPriceUsd),
+		FeeAssetAddress:          op.FeeAssetAddress,
+		ReferralAddress:          op.ReferralAddress,
+	}
+	var err error
+	if op.PoolTxTimestamp != "" {
+		if row.PoolTxTimestamp, err = openapi.ParseTime(op.PoolTxTimestamp); err != nil {
+			return row, fmt.Errorf("operation %s: pool_tx_timestamp: %w", op.PoolTxHash, err)
+		}
+	}
+	if op.WalletTxTimestamp != "" {
+		if row.WalletTxTimestamp, err = openapi.ParseTime(op.WalletTxTimestamp); err != nil {
+			return row, fmt.Errorf("operation %s: wallet_tx_timestamp: %w", op.PoolTxHash, err)
+		}
+	}
+	if op.WalletTxLt != "" {
+		if row.WalletTxLt, err = strconv.ParseInt(op.WalletTxLt, 10, 64); err != nil {
+			return row, fmt.Errorf("operation %s: wallet_tx_lt: %w", op.PoolTxHash, err)
+		}
+	}
+	for _, a := range []struct {
+		dst  *Amount
+		src  string
+		name string
+	}{
+		{&row.Asset0Amount, op.Asset0Amount, "asset0_amount"},
+		{&row.Asset0Delta, op.Asset0Delta, "asset0_delta"},
+		{&row.Asset0Reserve, op.Asset0Reserve, "asset0_reserve"},
+		{&row.Asset1Amount, op.Asset1Amount, "asset1_amount"},
+		{&row.Asset1Delta, op.Asset1Delta, "asset1_delta"},
+		{&row.Asset1Reserve, op.Asset1Reserve, "asset1_reserve"},
+		{&row.LpTokenDelta, op.LpTokenDelta, "lp_token_delta"},
+		{&row.LpTokenSupply, op.LpTokenSupply, "lp_token_supply"},
+		{&row.LpFeeAmount, op.LpFeeAmount, "lp_fee_amount"},
+		{&row.ProtocolFeeAmount, op.ProtocolFeeAmount, "protocol_fee_amount"},
+		{&row.ReferralFeeAmount, op.ReferralFeeAmount, "referral_fee_amount"},
+	} {
+		if *a.dst, err = ParseAmount(a.src); err != nil {
+			return row, fmt.Errorf("operation %s: %s: %w", op.PoolTxHash, a.name, err)
+		}
+	}
+	return row, nil
+}
+
+// PoolStatsRow is the stats of a pool over [Since, Until]. Volumes and
+// liquidity are in whole tokens; a stat the API does not report is null.
+type PoolStatsRow struct {
+	Since          time.Time `parquet:"since,timestamp(millisecond)" csv:"since"`
+	Until          time.Time `parquet:"until,timestamp(millisecond)" csv:"until"`
+	PoolAddress    string    `parquet:"pool_address,dict" csv:"pool_address"`
+	RouterAddress  string    `parquet:"router_address,dict" csv:"router_address"`
+	BaseID         string    `parquet:"base_id,dict" csv:"base_id"`
+	BaseSymbol     string    `parquet:"base_symbol,dict" csv:"base_symbol"`
+	BaseName       string    `parquet:"base_name,dict" csv:"base_name"`
+	QuoteID        string    `parquet:"quote_id,dict" csv:"quote_id"`
+	QuoteSymbol    string    `parquet:"quote_symbol,dict" csv:"quote_symbol"`
+	QuoteName      string    `parquet:"quote_name,dict" csv:"quote_name"`
+	LastPrice      *float64  `parquet:"last_price,optional" csv:"last_price"`
+	BaseVolume     *float64  `parquet:"base_volume,optional" csv:"base_volume"`
+	QuoteVolume    *float64  `parquet:"quote_volume,optional" csv:"quote_volume"`
+	BaseLiquidity  *float64  `parquet:"base_liquidity,optional" csv:"base_liquidity"`
+	QuoteLiquidity *float64  `parquet:"quote_liquidity,optional" csv:"quote_liquidity"`
+	LpPrice        *float64  `parquet:"lp_price,optional" csv:"lp_price"`
+	LpPriceUsd     *float64  `parquet:"lp_price_usd,optional" csv:"lp_price_usd"`
+	Apy            *float64  `parquet:"apy,optional" csv:"apy"`
+}
+
+// PoolStatsRowOf converts the stats of a pool over [since, until].
+func PoolStatsRowOf(since, until time.Time, s types.PoolStats) PoolStatsRow {
+	return PoolStatsRow{
+		Since:          since.UTC(),
+		Until:          until.UTC(),
+		PoolAddress:    s.PoolAddress,
+		RouterAddress:  s.RouterAddress,
+		BaseID:         s.BaseID,
+		BaseSymbol:     s.BaseSymbol,
+		BaseName:       s.BaseName,
+		QuoteID:        s.QuoteID,
+		QuoteSymbol:    s.QuoteSymbol,
+		QuoteName:      s.QuoteName,
+		LastPrice:      optionalFloat(s.LastPrice),
+		BaseVolume:     optionalFloat(s.BaseVolume),
+		QuoteVolume:    optionalFloat(s.QuoteVolume),
+		BaseLiquidity:  optionalFloat(s.BaseLiquidity),
+		QuoteLiquidity: optionalFloat(s.QuoteLiquidity),
+		LpPrice:        optionalFloat(s.LpPrice),
+		LpPriceUsd:     optionalFloat(s.LpPriceUsd),
+		Apy:            optionalFloat(s.Apy),
+	}
+}
+
+// optionalFloat parses a decimal of the API, nil if it is missing or invalid.
+func optionalFloat(s string) *float64 {
+	if v, ok := utils.ParseDecimal(s); ok {
+		return &v
+	}
+	return nil
+}
+
+// CandleRow is a candle; see candles.Candle.
+type CandleRow struct {
+	PoolAddress     string    `parquet:"pool_address,dict" csv:"pool_address"`
+	Asset0Address   string    `parquet:"asset0_address,dict" csv:"asset0_address"`
+	Asset1Address   string    `parquet:"asset1_address,dict" csv:"asset1_address"`
+	Start           time.Time `parquet:"start,timestamp(millisecond)" csv:"start"`
+	IntervalSeconds int64     `parquet:"interval_seconds" csv:"interval_seconds"`
+	Open            float64   `parquet:"open" csv:"open"`
+	High            float64   `parquet:"high" csv:"high"`
+	Low             float64   `parquet:"low" csv:"low"`
+	Close           float64   `parquet:"close" csv:"close"`
+	Volume0         Amount    `parquet:"volume0,decimal(0:38)" csv:"volume0"`
+	Volume1         Amount    `parquet:"volume1,decimal(0:38)" csv:"volume1"`
+	Trades          int64     `parquet:"trades" csv:"trades"`
+}
+
+// CandleRowOf converts a candle.
+func CandleRowOf(c candles.Candle) (CandleRow, error) {
+	row := CandleRow{
+		PoolAddress:     c.Pool,
+		Asset0Address:   c.Asset0,
+		Asset1Address:   c.Asset1,
+		Start:           c.Start,
+		IntervalSeconds: int64(c.Interval / time.Second),
+		Open:            c.Open,
+		High:            c.High,
+		Low:             c.Low,
+		Close:           c.Close,
+		Trades:          int64(c.Trades),
+	}
+	var err error
+	if row.Volume0, err = AmountOf(c.Volume0); err != nil {
+		return row, fmt.Errorf("candle %s %s: volume0: %w", c.Pool, c.Start, err)
+	}
+	if row.Volume1, err = AmountOf(c.Volume1); err != nil {
+		return row, fmt.Errorf("candle %s %s: volume1: %w", c.Pool, c.Start, err)
+	}
+	return row, nil
+}
@@ -0,0 +1,121 @@
+package export
+
+import (
+	"context"
+	"iter"
+	"sort"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/candles"
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/types"
+)
+
+// window is the longest span the `/v1/stats/...` endpoints accept.
+const window = 24 * time.Hour
+
+// windows yields [from, to) in consecutive spans of at most 24 hours.
+func windows(from, to time.Time) iter.Seq2[time.Time, time.Time] {
+	return func(yield func(time.Time, time.Time) bool) {
+		for start := from; start.Before(to); {
+			end := start.Add(window)
+			if end.After(to) {
+				end = to
+			}
+			if !yield(start, end) {
+				return
+			}
+			start = end
+		}
+	}
+}
+
+// Operations yields the operations from `/v1/stats/operations` between from
+// and to, ordered by time. Only one 24 hour window is held in memory at a
+// time. Iteration stops at the first error, which is yielded.
+func Operations(ctx context.Context, c *client.StonfiClient, from, to time.Time) iter.Seq2[types.OperationInfo, error] {
+	return func(yield func(types.OperationInfo, error) bool) {
+		for start, end := range windows(from, to) {
+			response, err := c.GetHistoricalSwaps(ctx, start, end)
+			if err != nil {
+				yield(types.OperationInfo{}, err)
+				return
+			}
+			ops := make([]timedOperation, 0, len(response.Operations))
+			for _, info := range response.Operations {
+				t, _ := openapi.ParseTime(info.Operation.PoolTxTimestamp)
+				// Windows share their bounds, which the API includes in both;
+				// keep a boundary operation in the later window only.
+				if !t.Before(end) && end.Before(to) {
+					continue
+				}
+				ops = append(ops, timedOperation{t, info})
+			}
+			sort.SliceStable(ops, func(i, j int) bool {
+				if !ops[i].t.Equal(ops[j].t) {
+					return ops[i].t.Before(ops[j].t)
+				}
+				return ops[i].info.Operation.PoolTxLt < ops[j].info.Operation.PoolTxLt
+			})
+			for _, op := range ops {
+				if !yield(op.info, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+type timedOperation struct {
+	t    time.Time
+	info types.OperationInfo
+}
+
+// PoolStats yields the stats of every pool for each 24 hour window between
+// from and to.
+func PoolStats(ctx context.Context, c *client.StonfiClient, from, to time.Time) iter.Seq2[PoolStatsRow, error] {
+	return func(yield func(PoolStatsRow, error) bool) {
+		for start, end := range windows(from, to) {
+			response, err := c.GetPoolStats(ctx, start, end)
+			if err != nil {
+				yield(PoolStatsRow{}, err)
+				return
+			}
+			for _, s := range response.Stats {
+				if !yield(PoolStatsRowOf(start, end, s), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// OperationRows converts a stream of operations to rows.
+func OperationRows(ops iter.Seq2[types.OperationInfo, error]) iter.Seq2[OperationRow, error] {
+	return convert(ops, OperationRowOf)
+}
+
+// CandleRows builds candles of the given interval from a stream of
+// operations ordered by time, and converts them to rows.
+func CandleRows(ops iter.Seq2[types.OperationInfo, error], interval time.Duration) iter.Seq2[CandleRow, error] {
+	return convert(candles.Build(ops, interval), CandleRowOf)
+}
+
+func convert[S, T any](seq iter.Seq2[S, error], f func(S) (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for s, err := range seq {
+			var t T
+			if err == nil {
+				t, err = f(s)
+			}
+			if err != nil {
+				yield(t, err)
+				return
+			}
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
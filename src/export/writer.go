@@ -0,0 +1,186 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Writer writes rows of type T to a file format.
+type Writer[T any] interface {
+	Write(rows ...T) error
+	// Close flushes buffered rows and writes any footer. It does not close
+	// the underlying io.Writer.
+	Close() error
+}
+
+// Format is an output format.
+type Format string
+
+const (
+	CSV     Format = "csv"
+	Parquet Format = "parquet"
+)
+
+// ParseFormat parses "csv" or "parquet".
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case CSV, Parquet:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q, want csv or parquet", s)
+}
+
+// NewWriter returns a Writer of the given format.
+func NewWriter[T any](w io.Writer, format Format) (Writer[T], error) {
+	switch format {
+	case CSV:
+		return NewCSVWriter[T](w), nil
+	case Parquet:
+		return NewParquetWriter[T](w), nil
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// Copy writes every row of rows to w, then closes w, and returns the number
+// of rows written. It stops at the first error.
+func Copy[T any](w Writer[T], rows iter.Seq2[T, error]) (int, error) {
+	n := 0
+	for row, err := range rows {
+		if err != nil {
+			return n, err
+		}
+		if err := w.Write(row); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, w.Close()
+}
+
+// RowGroupSize is the number of rows buffered in memory before a Parquet
+// row group is written.
+const RowGroupSize = 100_000
+
+type parquetWriter[T any] struct {
+	w        *parquet.GenericWriter[T]
+	buffered int
+}
+
+// NewParquetWriter returns a Writer of Parquet, with the schema taken from
+// the parquet tags of T. At most RowGroupSize rows are held in memory.
+func NewParquetWriter[T any](w io.Writer) Writer[T] {
+	return &parquetWriter[T]{w: parquet.NewGenericWriter[T](w, parquet.Compression(&parquet.Zstd))}
+}
+
+func (p *parquetWriter[T]) Write(rows ...T) error {
+	if _, err := p.w.Write(rows); err != nil {
+		return fmt.Errorf("export: writing parquet: %w", err)
+	}
+	p.buffered += len(rows)
+	if p.buffered >= RowGroupSize {
+		p.buffered = 0
+		if err := p.w.Flush(); err != nil {
+			return fmt.Errorf("export: writing parquet: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *parquetWriter[T]) Close() error {
+	if err := p.w.Close(); err != nil {
+		return fmt.Errorf("export: writing parquet: %w", err)
+	}
+	return nil
+}
+
+type csvWriter[T any] struct {
+	w      *csv.Writer
+	fields []int
+	header []string
+	record []string
+	begun  bool
+}
+
+// NewCSVWriter returns a Writer of CSV with a header row. The columns are
+// the fields of T with a csv tag, in order. Times are written in RFC 3339,
+// amounts in decimal and nil pointers as empty cells.
+func NewCSVWriter[T any](w io.Writer) Writer[T] {
+	c := &csvWriter[T]{w: csv.NewWriter(w)}
+	t := reflect.TypeFor[T]()
+	for i := range t.NumField() {
+		if name := t.Field(i).Tag.Get("csv"); name != "" && name != "-" {
+			c.fields = append(c.fields, i)
+			c.header = append(c.header, name)
+		}
+	}
+	c.record = make([]string, len(c.fields))
+	return c
+}
+
+func (c *csvWriter[T]) Write(rows ...T) error {
+	if !c.begun {
+		c.begun = true
+		if err := c.w.Write(c.header); err != nil {
+			return fmt.Errorf("export: writing csv: %w", err)
+		}
+	}
+	for i := range rows {
+		v := reflect.ValueOf(&rows[i]).Elem()
+		for j, f := range c.fields {
+			c.record[j] = formatField(v.Field(f))
+		}
+		if err := c.w.Write(c.record); err != nil {
+			return fmt.Errorf("export: writing csv: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *csvWriter[T]) Close() error {
+	if !c.begun {
+		// An empty export still has its header.
+		c.begun = true
+		c.w.Write(c.header)
+	}
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return fmt.Errorf("export: writing csv: %w", err)
+	}
+	return nil
+}
+
+func formatField(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch x := v.Interface().(type) {
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.UTC().Format(time.RFC3339)
+	case Amount:
+		return x.String()
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,366 @@
+// Package exporter exposes Ston.fi pool, asset, farm and DEX metrics in the
+// Prometheus format.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MinInterval is the shortest refresh interval accepted. Every refresh makes
+// exactly four API calls, so this bounds the exporter's API usage.
+const MinInterval = 30 * time.Second
+
+// Options configures an Exporter.
+type Options struct {
+	// Interval is the time between refreshes; it is raised to MinInterval.
+	Interval time.Duration
+	// StatsWindow is the period the DEX totals cover, at most 24h. Defaults to 24h.
+	StatsWindow time.Duration
+	// IncludeDeprecated also exports deprecated pools and assets.
+	IncludeDeprecated bool
+}
+
+// Exporter periodically fetches data from the API and serves it as metrics.
+type Exporter struct {
+	client   *client.StonfiClient
+	opts     Options
+	registry *prometheus.Registry
+
+	poolReserve         *gaugeVec
+	poolLpSupplyUsd     *gaugeVec
+	poolApy             *gaugeVec
+	poolLpPriceUsd      *gaugeVec
+	assetPriceUsd       *gaugeVec
+	farmApy             *gaugeVec
+	farmLockedLpUsd     *gaugeVec
+	farmRemaining       *gaugeVec
+	dexTvl              prometheus.Gauge
+	dexVolume           prometheus.Gauge
+	dexTrades           prometheus.Gauge
+	dexUniqueWallets    prometheus.Gauge
+	requestDuration     *prometheus.HistogramVec
+	requestErrors       *prometheus.CounterVec
+	lastRefresh         prometheus.Gauge
+	lastRefreshDuration prometheus.Gauge
+}
+
+// New creates an Exporter that reads from c.
+func New(c *client.StonfiClient, opts Options) *Exporter {
+	if opts.Interval < MinInterval {
+		opts.Interval = MinInterval
+	}
+	if opts.StatsWindow <= 0 || opts.StatsWindow > 24*time.Hour {
+		opts.StatsWindow = 24 * time.Hour
+	}
+	poolLabels := []string{"pool", "pair"}
+	farmLabels := []string{"farm", "pool"}
+	e := &Exporter{
+		client:   c,
+		opts:     opts,
+		registry: prometheus.NewRegistry(),
+
+		poolReserve: newGaugeVec(prometheus.GaugeOpts{
+			Name: "stonfi_pool_reserve",
+			Help: "Pool reserve of a token, in whole tokens.",
+		}, append(poolLabels, "token", "symbol")),
+		poolLpSupplyUsd: newGaugeVec(prometheus.GaugeOpts{
+			Name: "stonfi_pool_lp_total_supply_usd",
+			Help: "USD value of all LP tokens of the pool.",
+		}, poolLabels),
+		poolApy: newGaugeVec(prometheus.GaugeOpts{
+			Name: "stonfi_pool_apy",
+			Help: "Pool APY over the window, as a ratio.",
+		}, append(poolLabels, "window")),
+		poolLpPriceUsd: newGaugeVec(prometheus.GaugeOpts{
+			Name: "stonfi_pool_lp_price_usd",
+			Help: "USD price of one LP token.",
+		}, poolLabels),
+		assetPriceUsd: newGaugeVec(prometheus.GaugeOpts{
+			Name: "stonfi_asset_price_usd",
+			Help: "DEX USD price of the asset.",
+		}, []string{"asset", "symbol"}),
+		farmApy: newGaugeVec(prometheus.GaugeOpts{
+			Name: "stonfi_farm_apy",
+			Help: "Farm APY, as a ratio.",
+		}, farmLabels),
+		farmLockedLpUsd: newGaugeVec(prometheus.GaugeOpts{
+			Name: "stonfi_farm_locked_total_lp_usd",
+			Help: "USD value of the LP tokens staked in the farm.",
+		}, farmLabels),
+		farmRemaining: newGaugeVec(prometheus.GaugeOpts{
+			Name: "stonfi_farm_remaining_rewards",
+			Help: "Rewards left to distribute, in the reward token's smallest units.",
+		}, append(farmLabels, "reward")),
+		dexTvl: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "stonfi_dex_tvl_usd",
+			Help: "Total value locked in the DEX.",
+		}),
+		dexVolume: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "stonfi_dex_volume_usd",
+			Help: "DEX volume over the stats window.",
+		}),
+		dexTrades: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "stonfi_dex_trades",
+			Help: "Number of trades over the stats window.",
+		}),
+		dexUniqueWallets: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "stonfi_dex_unique_wallets",
+			Help: "Number of wallets that traded over the stats window.",
+		}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "stonfi_client_request_duration_seconds",
+			Help:    "Latency of API calls made by the exporter.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+		}, []string{"method"}),
+		requestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "stonfi_client_request_errors_total",
+			Help: "API calls made by the exporter that failed.",
+		}, []string{"method"}),
+		lastRefresh: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "stonfi_exporter_last_refresh_timestamp_seconds",
+			Help: "Time of the last refresh.",
+		}),
+		lastRefreshDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "stonfi_exporter_last_refresh_duration_seconds",
+			Help: "Duration of the last refresh.",
+		}),
+	}
+	e.registry.MustRegister(
+		e.poolReserve, e.poolLpSupplyUsd, e.poolApy, e.poolLpPriceUsd,
+		e.assetPriceUsd,
+		e.farmApy, e.farmLockedLpUsd, e.farmRemaining,
+		e.dexTvl, e.dexVolume, e.dexTrades, e.dexUniqueWallets,
+		e.requestDuration, e.requestErrors, e.lastRefresh, e.lastRefreshDuration,
+	)
+	return e
+}
+
+// Registry returns the registry holding the exporter's metrics.
+func (e *Exporter) Registry() *prometheus.Registry {
+	return e.registry
+}
+
+// Handler serves the metrics.
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{})
+}
+
+// Run refreshes the metrics every Interval until ctx is done. Refresh errors
+// are passed to onError, if set, and the previous values are kept.
+func (e *Exporter) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if err := e.Refresh(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh fetches assets, pools, farms and DEX stats once and updates the
+// metrics. A failed call leaves the metrics it feeds unchanged.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	start := time.Now()
+	var errs []error
+
+	assets, err := e.fetchAssets(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if err := e.refreshPools(ctx, assets); err != nil {
+		errs = append(errs, err)
+	}
+	if err := e.refreshFarms(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := e.refreshStats(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	e.lastRefresh.SetToCurrentTime()
+	e.lastRefreshDuration.Set(time.Since(start).Seconds())
+	if len(errs) > 0 {
+		return fmt.Errorf("refresh: %v", errs)
+	}
+	return nil
+}
+
+// observe records the latency and outcome of an API call.
+func (e *Exporter) observe(method string, call func() error) error {
+	start := time.Now()
+	err := call()
+	e.requestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		e.requestErrors.WithLabelValues(method).Inc()
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return nil
+}
+
+// fetchAssets updates the asset metrics and returns the assets by raw
+// address. It returns nil if the call failed.
+func (e *Exporter) fetchAssets(ctx context.Context) (map[string]types.Asset, error) {
+	var response *types.AssetListResponse
+	err := e.observe("GetAssets", func() (err error) {
+		response, err = e.client.GetAssets(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	assets := map[string]types.Asset{}
+	for _, a := range response.AssetList {
+		assets[utils.AddressKey(a.ContractAddress)] = a
+		if (a.Deprecated || a.Blacklisted) && !e.opts.IncludeDeprecated {
+			continue
+		}
+		if v, ok := utils.ParseDecimal(a.DexPriceUsd); ok {
+			e.assetPriceUsd.set(v, a.ContractAddress, a.Symbol)
+		}
+	}
+	e.assetPriceUsd.commit()
+	return assets, nil
+}
+
+func (e *Exporter) refreshPools(ctx context.Context, assets map[string]types.Asset) error {
+	var response *types.PoolListResponse
+	err := e.observe("GetPools", func() (err error) {
+		response, err = e.client.GetPools(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, p := range response.PoolList {
+		if p.Deprecated && !e.opts.IncludeDeprecated {
+			continue
+		}
+		token0, token1 := assets[utils.AddressKey(p.Token0Address)], assets[utils.AddressKey(p.Token1Address)]
+		pair := symbolOf(token0, p.Token0Address) + "/" + symbolOf(token1, p.Token1Address)
+
+		for _, r := range []struct {
+			address, units string
+			asset          types.Asset
+		}{{p.Token0Address, p.Reserve0, token0}, {p.Token1Address, p.Reserve1, token1}} {
+			if n, ok := new(big.Int).SetString(r.units, 10); ok {
+				e.poolReserve.set(utils.ToUnits(n, r.asset.Decimals), p.Address, pair, r.address, symbolOf(r.asset, r.address))
+			}
+		}
+		if v, ok := utils.ParseDecimal(p.LpTotalSupplyUsd); ok {
+			e.poolLpSupplyUsd.set(v, p.Address, pair)
+		}
+		if v, ok := utils.ParseDecimal(p.LpPriceUsd); ok {
+			e.poolLpPriceUsd.set(v, p.Address, pair)
+		}
+		for window, apy := range map[string]string{"1d": p.Apy1D, "7d": p.Apy7D, "30d": p.Apy30D} {
+			if v, ok := utils.ParseDecimal(apy); ok {
+				e.poolApy.set(v, p.Address, pair, window)
+			}
+		}
+	}
+	for _, g := range []*gaugeVec{e.poolReserve, e.poolLpSupplyUsd, e.poolLpPriceUsd, e.poolApy} {
+		g.commit()
+	}
+	return nil
+}
+
+func (e *Exporter) refreshFarms(ctx context.Context) error {
+	var response *types.FarmListResponse
+	err := e.observe("GetFarms", func() (err error) {
+		response, err = e.client.GetFarms(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, f := range response.Farms {
+		if v, ok := utils.ParseDecimal(f.APY); ok {
+			e.farmApy.set(v, f.MinterAddress, f.PoolAddress)
+		}
+		if v, ok := utils.ParseDecimal(f.LockedTotalLPUSD); ok {
+			e.farmLockedLpUsd.set(v, f.MinterAddress, f.PoolAddress)
+		}
+		for _, r := range f.Rewards {
+			if v, ok := utils.ParseDecimal(r.RemainingRewards); ok {
+				e.farmRemaining.set(v, f.MinterAddress, f.PoolAddress, r.Address)
+			}
+		}
+	}
+	for _, g := range []*gaugeVec{e.farmApy, e.farmLockedLpUsd, e.farmRemaining} {
+		g.commit()
+	}
+	return nil
+}
+
+func (e *Exporter) refreshStats(ctx context.Context) error {
+	until := time.Now().UTC().Truncate(time.Second)
+	var response *types.DexStatsResponse
+	err := e.observe("GetStats", func() (err error) {
+		response, err = e.client.GetStats(ctx, until.Add(-e.opts.StatsWindow), until)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if v, ok := utils.ParseDecimal(response.Stats.Tvl); ok {
+		e.dexTvl.Set(v)
+	}
+	if v, ok := utils.ParseDecimal(response.Stats.VolumeUsd); ok {
+		e.dexVolume.Set(v)
+	}
+	e.dexTrades.Set(float64(response.Stats.Trades))
+	e.dexUniqueWallets.Set(float64(response.Stats.UniqueWallets))
+	return nil
+}
+
+func symbolOf(asset types.Asset, address string) string {
+	if asset.Symbol != "" {
+		return asset.Symbol
+	}
+	return address
+}
+
+// gaugeVec is a GaugeVec that a refresh updates in place. Series are set as
+// the new values come in, and the series of the previous refresh that were
+// not set again are deleted on commit, so a scrape during a refresh never
+// misses a series that is still there.
+type gaugeVec struct {
+	*prometheus.GaugeVec
+	current, next map[string][]string
+}
+
+func newGaugeVec(opts prometheus.GaugeOpts, labels []string) *gaugeVec {
+	return &gaugeVec{GaugeVec: prometheus.NewGaugeVec(opts, labels), current: map[string][]string{}, next: map[string][]string{}}
+}
+
+func (g *gaugeVec) set(v float64, labels ...string) {
+	g.WithLabelValues(labels...).Set(v)
+	g.next[strings.Join(labels, "\xff")] = labels
+}
+
+// commit deletes the series that were set by the previous refresh but not by
+// this one.
+func (g *gaugeVec) commit() {
+	for key, labels := range g.current {
+		if _, ok := g.next[key]; !ok {
+			g.DeleteLabelValues(labels...)
+		}
+	}
+	g.current, g.next = g.next, map[string][]string{}
+}
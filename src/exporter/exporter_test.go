@@ -0,0 +1,113 @@
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	farmAddress = "EQCEtmlPBWm7X9_Ec7Vmr5Jc6tLgrpjAoTrhoGdGbOkTL9ox"
+)
+
+func newServer() *stonfitest.Server {
+	farm := types.Farm{
+		MinterAddress:    farmAddress,
+		PoolAddress:      poolAddress,
+		APY:              "0.25",
+		LockedTotalLPUSD: "1200.5",
+		Rewards:          []types.FarmReward{{Address: tonAddress, RemainingRewards: "5000"}},
+	}
+
+	return stonfitest.NewServerWithFixtures(stonfitest.Fixtures{
+		Assets: []types.Asset{
+			{ContractAddress: tonAddress, Symbol: "TON", Decimals: 9, DexPriceUsd: "5.5"},
+			{ContractAddress: usdtAddress, Symbol: "USD₮", Decimals: 6, DexPriceUsd: "1"},
+		},
+		Pools: []types.Pool{{
+			Address:          poolAddress,
+			Token0Address:    tonAddress,
+			Token1Address:    usdtAddress,
+			Reserve0:         "2000000000000",
+			Reserve1:         "11000000000",
+			LpTotalSupplyUsd: "22000",
+			LpPriceUsd:       "1.1",
+			Apy1D:            "0.1",
+			Apy7D:            "0.2",
+		}},
+		Farms: []types.Farm{farm},
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	e := New(s.Client(), Options{})
+
+	assert.NoError(t, e.Refresh(context.Background()))
+	assert.Len(t, s.Requests(), 4)
+
+	assert.Equal(t, 5.5, testutil.ToFloat64(e.assetPriceUsd.WithLabelValues(tonAddress, "TON")))
+	assert.Equal(t, 2000.0, testutil.ToFloat64(e.poolReserve.WithLabelValues(poolAddress, "TON/USD₮", tonAddress, "TON")))
+	assert.Equal(t, 11000.0, testutil.ToFloat64(e.poolReserve.WithLabelValues(poolAddress, "TON/USD₮", usdtAddress, "USD₮")))
+	assert.Equal(t, 22000.0, testutil.ToFloat64(e.poolLpSupplyUsd.WithLabelValues(poolAddress, "TON/USD₮")))
+	assert.Equal(t, 0.2, testutil.ToFloat64(e.poolApy.WithLabelValues(poolAddress, "TON/USD₮", "7d")))
+	assert.Equal(t, 2, testutil.CollectAndCount(e.poolApy), "missing APY windows are not exported")
+	assert.Equal(t, 0.25, testutil.ToFloat64(e.farmApy.WithLabelValues(farmAddress, poolAddress)))
+	assert.Equal(t, 5000.0, testutil.ToFloat64(e.farmRemaining.WithLabelValues(farmAddress, poolAddress, tonAddress)))
+	assert.Equal(t, 22000.0, testutil.ToFloat64(e.dexTvl))
+	assert.Equal(t, 4, testutil.CollectAndCount(e.requestDuration))
+}
+
+func TestRefreshKeepsValuesOnError(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	e := New(s.Client(), Options{})
+	assert.NoError(t, e.Refresh(context.Background()))
+
+	s.Inject(stonfitest.Fault{Path: "/v1/pools", Status: http.StatusInternalServerError})
+	s.SetReserves(poolAddress, "1000000000000", "11000000000")
+	err := e.Refresh(context.Background())
+	assert.ErrorContains(t, err, "GetPools")
+	assert.Equal(t, 2000.0, testutil.ToFloat64(e.poolReserve.WithLabelValues(poolAddress, "TON/USD₮", tonAddress, "TON")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(e.requestErrors.WithLabelValues("GetPools")))
+}
+
+func TestGaugeVecCommit(t *testing.T) {
+	g := newGaugeVec(prometheus.GaugeOpts{Name: "test"}, []string{"pool"})
+	g.set(1, "a")
+	g.set(2, "b")
+	g.commit()
+
+	g.set(3, "a")
+	assert.Equal(t, 2, testutil.CollectAndCount(g), "series are kept until the refresh commits")
+	g.commit()
+	assert.Equal(t, 1, testutil.CollectAndCount(g), "series that were not set again are deleted")
+	assert.Equal(t, 3.0, testutil.ToFloat64(g.WithLabelValues("a")))
+}
+
+func TestHandler(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	e := New(s.Client(), Options{Interval: time.Second})
+	assert.Equal(t, MinInterval, e.opts.Interval)
+	assert.NoError(t, e.Refresh(context.Background()))
+
+	err := testutil.GatherAndCompare(e.Registry(), strings.NewReader(`
+# HELP stonfi_pool_lp_price_usd USD price of one LP token.
+# TYPE stonfi_pool_lp_price_usd gauge
+stonfi_pool_lp_price_usd{pair="TON/USD₮",pool="`+poolAddress+`"} 1.1
+`), "stonfi_pool_lp_price_usd")
+	assert.NoError(t, err)
+}
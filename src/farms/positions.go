@@ -0,0 +1,184 @@
+package farms
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Pending is the unclaimed reward of a position in one token.
+type Pending struct {
+	Address string
+	Symbol  string
+	Units   *big.Int
+	// Amount is in whole tokens.
+	Amount    float64
+	AmountUsd float64
+}
+
+// Position is a stake in a farm. The API reports what is pending, not what
+// has been claimed.
+type Position struct {
+	Farm       types.Farm
+	NftAddress string
+	Status     string
+	// StakedLp is the LP tokens staked, in smallest units, and StakedUsd
+	// their value at the farm's average LP price.
+	StakedLp  *big.Int
+	StakedUsd float64
+	StakedAt  time.Time
+	// UnlocksAt is when the position can first be unstaked: the NFT's
+	// min_unstake_timestamp, or StakedAt plus the farm's minimum stake
+	// duration when it has none.
+	UnlocksAt  time.Time
+	CanUnstake bool
+	Pending    []Pending
+	PendingUsd float64
+}
+
+// Unstaked reports whether the position has been withdrawn.
+func (p Position) Unstaked() bool {
+	return strings.EqualFold(p.Status, "unstaked")
+}
+
+// Positions decodes the stakes in farm as of now. assets must include the
+// reward tokens.
+func Positions(farm types.Farm, assets []types.Asset, now time.Time) ([]Position, error) {
+	byAddress := map[string]types.Asset{}
+	for _, a := range assets {
+		byAddress[utils.AddressKey(a.ContractAddress)] = a
+	}
+	var minStake time.Duration
+	if farm.MinStakeDurationS != "" {
+		seconds, err := strconv.ParseInt(farm.MinStakeDurationS, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("farm %s: min_stake_duration_s: %w", farm.MinterAddress, err)
+		}
+		minStake = time.Duration(seconds) * time.Second
+	}
+	var lpPriceUsd float64
+	if lp, err := parseUnits(farm.LockedTotalLP); err == nil && lp.Sign() > 0 {
+		// LP tokens have 9 decimals.
+		lpPriceUsd = utils.ParseFloat(farm.LockedTotalLPUSD) / utils.ToUnits(lp, 9)
+	}
+
+	var positions []Position
+	for _, nft := range farm.NftInfos {
+		p := Position{Farm: farm, NftAddress: nft.Address, Status: nft.Status}
+		var err error
+		if p.StakedLp, err = parseUnits(nft.StakedTokens); err != nil {
+			return nil, fmt.Errorf("farm %s: nft %s: staked_tokens: %w", farm.MinterAddress, nft.Address, err)
+		}
+		p.StakedUsd = utils.ToUnits(p.StakedLp, 9) * lpPriceUsd
+		if p.StakedAt, err = parseTimestamp(nft.CreateTimestamp); err != nil {
+			return nil, fmt.Errorf("farm %s: nft %s: create_timestamp: %w", farm.MinterAddress, nft.Address, err)
+		}
+		if p.UnlocksAt, err = parseTimestamp(nft.MinUnstakeTimestamp); err != nil {
+			return nil, fmt.Errorf("farm %s: nft %s: min_unstake_timestamp: %w", farm.MinterAddress, nft.Address, err)
+		}
+		if p.UnlocksAt.IsZero() && !p.StakedAt.IsZero() {
+			p.UnlocksAt = p.StakedAt.Add(minStake)
+		}
+		p.CanUnstake = !p.Unstaked() && !now.Before(p.UnlocksAt)
+
+		rewards := nft.Rewards
+		if len(rewards) == 0 && nft.NonclaimedRewards != "" {
+			// Farms with a single reward token report it on its own.
+			rewards = []types.FarmNftReward{{Address: farm.RewardTokenAddress, Amount: nft.NonclaimedRewards}}
+		}
+		for _, r := range rewards {
+			pending := Pending{Address: r.Address}
+			if pending.Units, err = parseUnits(r.Amount); err != nil {
+				return nil, fmt.Errorf("farm %s: nft %s: reward %s: %w", farm.MinterAddress, nft.Address, r.Address, err)
+			}
+			asset, ok := byAddress[utils.AddressKey(r.Address)]
+			if !ok {
+				return nil, fmt.Errorf("farm %s: no asset for reward token %s", farm.MinterAddress, r.Address)
+			}
+			pending.Symbol = asset.Symbol
+			pending.Amount = utils.ToUnits(pending.Units, asset.Decimals)
+			pending.AmountUsd = pending.Amount * utils.ParseFloat(asset.DexPriceUsd)
+			p.Pending = append(p.Pending, pending)
+			p.PendingUsd += pending.AmountUsd
+		}
+		positions = append(positions, p)
+	}
+	return positions, nil
+}
+
+// WalletFarmPositions fetches the farms of wallet and returns its stakes in
+// them, with pending rewards valued at current prices.
+func WalletFarmPositions(ctx context.Context, c *client.StonfiClient, wallet string) ([]Position, error) {
+	response, err := c.GetWalletFarms(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("farms: fetching farms of %s: %w", wallet, err)
+	}
+	assets, err := rewardAssets(ctx, c, RewardTokens(response.Farms))
+	if err != nil {
+		return nil, err
+	}
+	return WalletPositions(response.Farms, assets, time.Now())
+}
+
+// RewardTokens returns the addresses of the reward tokens of farms, as
+// listed by `/v1/wallets/{addr_str}/farms`, each once.
+func RewardTokens(farms []types.Farm) []string {
+	seen := map[string]bool{}
+	var tokens []string
+	add := func(address string) {
+		if address != "" && !seen[utils.AddressKey(address)] {
+			seen[utils.AddressKey(address)] = true
+			tokens = append(tokens, address)
+		}
+	}
+	for _, farm := range farms {
+		add(farm.RewardTokenAddress)
+		for _, nft := range farm.NftInfos {
+			for _, r := range nft.Rewards {
+				add(r.Address)
+			}
+		}
+	}
+	return tokens
+}
+
+// WalletPositions decodes the stakes in the farms of a wallet as of now.
+// assets must include the reward tokens, see RewardTokens.
+func WalletPositions(farms []types.Farm, assets []types.Asset, now time.Time) ([]Position, error) {
+	var positions []Position
+	for _, farm := range farms {
+		ps, err := Positions(farm, assets, now)
+		if err != nil {
+			return nil, fmt.Errorf("farms: %w", err)
+		}
+		positions = append(positions, ps...)
+	}
+	return positions, nil
+}
+
+// parseTimestamp parses an API timestamp, in the stats layout, RFC 3339 or
+// Unix seconds. The empty string is the zero time.
+func parseTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(openapi.TimeLayout, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
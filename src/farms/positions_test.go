@@ -0,0 +1,123 @@
+package farms
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const wallet = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+
+// walletFarm is a farm as `/v1/wallets/{addr_str}/farms` returns it.
+const walletFarm = `{
+	"minter_address": "EQCEtmlPBWm7X9_Ec7Vmr5Jc6tLgrpjAoTrhoGdGbOkTL9ox",
+	"pool_address": "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE",
+	"reward_token_address": "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c",
+	"status": "operational",
+	"min_stake_duration_s": "86400",
+	"locked_total_lp": "1000000000000",
+	"locked_total_lp_usd": "36500",
+	"apy": "0.1",
+	"nft_infos": [
+		{
+			"address": "EQnft1",
+			"status": "active",
+			"create_timestamp": "2024-01-01T00:00:00",
+			"staked_tokens": "10000000000",
+			"rewards": [
+				{"address": "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c", "amount": "2000000000"},
+				{"address": "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs", "amount": "500000"}
+			]
+		},
+		{
+			"address": "EQnft2",
+			"status": "active",
+			"create_timestamp": "1704067200",
+			"min_unstake_timestamp": "2024-01-03T00:00:00",
+			"staked_tokens": "5000000000",
+			"nonclaimed_rewards": "1000000000"
+		},
+		{
+			"address": "EQnft3",
+			"status": "unstaked",
+			"create_timestamp": "2023-06-01T00:00:00",
+			"staked_tokens": "0"
+		}
+	],
+	"rewards": [{"address": "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c", "status": "active", "remaining_rewards": "0", "reward_rate_24h": "0"}]
+}`
+
+func decodeWalletFarm(t *testing.T) types.Farm {
+	var farm types.Farm
+	assert.NoError(t, json.Unmarshal([]byte(walletFarm), &farm))
+	return farm
+}
+
+func TestPositions(t *testing.T) {
+	farm := decodeWalletFarm(t)
+	positions, err := Positions(farm, assets, now.Add(12*time.Hour))
+	if !assert.NoError(t, err) || !assert.Len(t, positions, 3) {
+		return
+	}
+
+	first := positions[0]
+	assert.Equal(t, "EQnft1", first.NftAddress)
+	assert.Equal(t, "10000000000", first.StakedLp.String())
+	assert.InDelta(t, 365, first.StakedUsd, 1e-9, "10 of 1000 LP tokens worth $36500")
+	assert.Equal(t, now, first.StakedAt)
+	assert.Equal(t, now.Add(24*time.Hour), first.UnlocksAt, "staked plus the minimum stake duration")
+	assert.False(t, first.CanUnstake)
+	if assert.Len(t, first.Pending, 2) {
+		assert.Equal(t, "TON", first.Pending[0].Symbol)
+		assert.Equal(t, 2.0, first.Pending[0].Amount)
+		assert.Equal(t, 0.5, first.Pending[1].AmountUsd)
+	}
+	assert.InDelta(t, 2.5, first.PendingUsd, 1e-9)
+
+	second := positions[1]
+	assert.Equal(t, now, second.StakedAt, "Unix seconds")
+	assert.Equal(t, now.Add(48*time.Hour), second.UnlocksAt, "min_unstake_timestamp wins")
+	if assert.Len(t, second.Pending, 1) {
+		assert.Equal(t, "1000000000", second.Pending[0].Units.String(), "nonclaimed_rewards are in the farm's reward token")
+		assert.Equal(t, "TON", second.Pending[0].Symbol)
+	}
+
+	third := positions[2]
+	assert.True(t, third.Unstaked())
+	assert.False(t, third.CanUnstake)
+
+	positions, err = Positions(farm, assets, now.Add(25*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, positions[0].CanUnstake)
+	assert.False(t, positions[1].CanUnstake)
+}
+
+func TestPositionsErrors(t *testing.T) {
+	farm := decodeWalletFarm(t)
+	_, err := Positions(farm, assets[:1], now)
+	assert.ErrorContains(t, err, "no asset for reward token")
+
+	farm.NftInfos[0].CreateTimestamp = "yesterday"
+	_, err = Positions(farm, assets, now)
+	assert.ErrorContains(t, err, "create_timestamp")
+}
+
+func TestWalletFarmPositions(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{
+		Assets:  assets,
+		Wallets: map[string]stonfitest.Wallet{wallet: {Farms: []types.Farm{decodeWalletFarm(t)}}},
+	})
+	defer s.Close()
+
+	positions, err := WalletFarmPositions(context.Background(), s.Client(), wallet)
+	if !assert.NoError(t, err) || !assert.Len(t, positions, 3) {
+		return
+	}
+	assert.InDelta(t, 2.5, positions[0].PendingUsd, 1e-9)
+	assert.True(t, positions[0].CanUnstake, "long after the minimum stake duration")
+}
@@ -0,0 +1,233 @@
+// Package farms interprets farms: the yield their reward streams pay, what a
+// stake would earn, and the positions a wallet holds in them.
+package farms
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Stream is a reward stream of a farm.
+type Stream struct {
+	Address string
+	Symbol  string
+	Status  string
+	// RatePerDay is the reward paid to all stakers per day, in smallest
+	// units, and RatePerDayUsd its value.
+	RatePerDay    *big.Int
+	RatePerDayUsd float64
+	// Remaining is the reward left to pay, in smallest units.
+	Remaining *big.Int
+	// RunsOutAt is when the remaining reward is paid out at the current
+	// rate; zero if the stream pays nothing.
+	RunsOutAt time.Time
+	// Apr is the stream's share of the farm's APR.
+	Apr float64
+	// Decimals and PriceUsd are those of the reward token.
+	Decimals int
+	PriceUsd float64
+}
+
+// Active reports whether the stream is still paying.
+func (s Stream) Active() bool {
+	return s.RatePerDay.Sign() > 0 && s.Remaining.Sign() > 0
+}
+
+// Yield is the yield of a farm, recomputed from its reward rates and the
+// prices of its reward tokens.
+type Yield struct {
+	Farm    types.Farm
+	Streams []Stream
+	// TvlUsd is the value of the LP tokens staked.
+	TvlUsd float64
+	// Apr is the yearly reward value of the active streams over TvlUsd, and
+	// Apy the same compounded daily.
+	Apr float64
+	Apy float64
+	// ReportedApy is the farm's `apy` field, for comparison.
+	ReportedApy float64
+	// PoolApy7D is the underlying pool's fee APY over 7 days, and TotalApy
+	// the sum of the two: a stake earns both.
+	PoolApy7D        float64
+	TotalApy         float64
+	MinStakeDuration time.Duration
+	// At is the time the yield was computed for.
+	At time.Time
+}
+
+// Calculate computes the yield of farm at now. pool is the farm's pool, or
+// nil to leave out the pool's APY; assets must include the reward tokens.
+func Calculate(farm types.Farm, pool *types.Pool, assets []types.Asset, now time.Time) (*Yield, error) {
+	byAddress := map[string]types.Asset{}
+	for _, a := range assets {
+		byAddress[utils.AddressKey(a.ContractAddress)] = a
+	}
+	y := &Yield{
+		Farm:        farm,
+		TvlUsd:      utils.ParseFloat(farm.LockedTotalLPUSD),
+		ReportedApy: utils.ParseFloat(farm.APY),
+		At:          now,
+	}
+	if farm.MinStakeDurationS != "" {
+		seconds, err := strconv.ParseInt(farm.MinStakeDurationS, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("farm %s: min_stake_duration_s: %w", farm.MinterAddress, err)
+		}
+		y.MinStakeDuration = time.Duration(seconds) * time.Second
+	}
+
+	var dailyUsd float64
+	for _, r := range farm.Rewards {
+		s := Stream{Address: r.Address, Status: r.Status, RatePerDay: new(big.Int), Remaining: new(big.Int)}
+		var err error
+		if s.RatePerDay, err = parseUnits(r.RewardRate24H); err != nil {
+			return nil, fmt.Errorf("farm %s: reward %s: reward_rate_24h: %w", farm.MinterAddress, r.Address, err)
+		}
+		if s.Remaining, err = parseUnits(r.RemainingRewards); err != nil {
+			return nil, fmt.Errorf("farm %s: reward %s: remaining_rewards: %w", farm.MinterAddress, r.Address, err)
+		}
+		asset, ok := byAddress[utils.AddressKey(r.Address)]
+		if !ok {
+			return nil, fmt.Errorf("farm %s: no asset for reward token %s", farm.MinterAddress, r.Address)
+		}
+		s.Symbol, s.Decimals, s.PriceUsd = asset.Symbol, asset.Decimals, utils.ParseFloat(asset.DexPriceUsd)
+		if s.Active() {
+			s.RatePerDayUsd = utils.ToUnits(s.RatePerDay, s.Decimals) * s.PriceUsd
+			days, _ := new(big.Rat).SetFrac(s.Remaining, s.RatePerDay).Float64()
+			s.RunsOutAt = now.Add(time.Duration(days * float64(24*time.Hour)))
+			if y.TvlUsd > 0 {
+				s.Apr = s.RatePerDayUsd * 365 / y.TvlUsd
+			}
+			dailyUsd += s.RatePerDayUsd
+		}
+		y.Streams = append(y.Streams, s)
+	}
+	if y.TvlUsd > 0 {
+		y.Apr = dailyUsd * 365 / y.TvlUsd
+		y.Apy = math.Pow(1+y.Apr/365, 365) - 1
+	}
+	if pool != nil {
+		y.PoolApy7D = utils.ParseFloat(pool.Apy7D)
+	}
+	y.TotalApy = y.Apy + y.PoolApy7D
+	return y, nil
+}
+
+// Reward is the projected reward of one stream.
+type Reward struct {
+	Address string
+	Symbol  string
+	// Amount is in whole tokens.
+	Amount    float64
+	AmountUsd float64
+	// Days is how many of the projected days the stream pays for.
+	Days float64
+}
+
+// Projection is what a new stake would earn.
+type Projection struct {
+	StakeUsd float64
+	Duration time.Duration
+	// Share is the stake's share of the farm once added to it.
+	Share   float64
+	Rewards []Reward
+	// RewardsUsd is the value of all rewards, and FeesUsd the pool fees the
+	// LP tokens earn over the same time, at PoolApy7D.
+	RewardsUsd float64
+	FeesUsd    float64
+	TotalUsd   float64
+	// UnstakableAt is when the stake can first be withdrawn.
+	UnstakableAt time.Time
+}
+
+// Project projects the rewards of staking stakeUsd worth of LP tokens for d,
+// at current rates and prices. Streams that run out during d pay until they
+// do.
+func (y *Yield) Project(stakeUsd float64, d time.Duration) Projection {
+	p := Projection{StakeUsd: stakeUsd, Duration: d, UnstakableAt: y.At.Add(y.MinStakeDuration)}
+	if stakeUsd <= 0 {
+		return p
+	}
+	p.Share = stakeUsd / (y.TvlUsd + stakeUsd)
+	end := y.At.Add(d)
+	for _, s := range y.Streams {
+		if !s.Active() {
+			continue
+		}
+		paysUntil := end
+		if s.RunsOutAt.Before(end) {
+			paysUntil = s.RunsOutAt
+		}
+		days := paysUntil.Sub(y.At).Hours() / 24
+		amount := utils.ToUnits(s.RatePerDay, s.Decimals) * days * p.Share
+		r := Reward{Address: s.Address, Symbol: s.Symbol, Amount: amount, AmountUsd: amount * s.PriceUsd, Days: days}
+		p.Rewards = append(p.Rewards, r)
+		p.RewardsUsd += r.AmountUsd
+	}
+	p.FeesUsd = stakeUsd * (math.Pow(1+y.PoolApy7D, d.Hours()/24/365) - 1)
+	p.TotalUsd = p.RewardsUsd + p.FeesUsd
+	return p
+}
+
+// ForFarm fetches the farm at address, its pool and its reward tokens, and
+// computes its yield now.
+func ForFarm(ctx context.Context, c *client.StonfiClient, address string) (*Yield, error) {
+	farm, err := c.GetFarm(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("farms: fetching farm %s: %w", address, err)
+	}
+	pool, err := c.GetPool(ctx, farm.Farm.PoolAddress)
+	if err != nil {
+		return nil, fmt.Errorf("farms: fetching pool %s: %w", farm.Farm.PoolAddress, err)
+	}
+	var tokens []string
+	for _, r := range farm.Farm.Rewards {
+		tokens = append(tokens, r.Address)
+	}
+	assets, err := rewardAssets(ctx, c, tokens)
+	if err != nil {
+		return nil, err
+	}
+	return Calculate(farm.Farm, &pool.Pool, assets, time.Now())
+}
+
+// rewardAssets fetches the reward tokens at addresses, each once.
+func rewardAssets(ctx context.Context, c *client.StonfiClient, addresses []string) ([]types.Asset, error) {
+	seen := map[string]bool{}
+	var assets []types.Asset
+	for _, address := range addresses {
+		key := utils.AddressKey(address)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		asset, err := c.GetAsset(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("farms: fetching reward token %s: %w", address, err)
+		}
+		if asset.Asset.ContractAddress == "" {
+			asset.Asset.ContractAddress = address
+		}
+		assets = append(assets, asset.Asset)
+	}
+	return assets, nil
+}
+
+func parseUnits(s string) (*big.Int, error) {
+	if s == "" {
+		return new(big.Int), nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid units %q", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,102 @@
+package farms
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	farmAddress = "EQCEtmlPBWm7X9_Ec7Vmr5Jc6tLgrpjAoTrhoGdGbOkTL9ox"
+)
+
+var (
+	now    = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assets = []types.Asset{
+		{ContractAddress: tonAddress, Symbol: "TON", Decimals: 9, DexPriceUsd: "1"},
+		{ContractAddress: usdtAddress, Symbol: "USDT", Decimals: 6, DexPriceUsd: "1"},
+	}
+	pool = types.Pool{Address: poolAddress, Token0Address: tonAddress, Token1Address: usdtAddress, Apy7D: "0.05"}
+	farm = types.Farm{
+		MinterAddress:     farmAddress,
+		PoolAddress:       poolAddress,
+		MinStakeDurationS: "86400",
+		LockedTotalLPUSD:  "36500",
+		APY:               "0.2",
+		Rewards: []types.FarmReward{
+			{Address: tonAddress, Status: "active", RewardRate24H: "10000000000", RemainingRewards: "50000000000"}, // $10 a day for 5 days
+			{Address: usdtAddress, Status: "ended", RewardRate24H: "0", RemainingRewards: "0"},
+		},
+	}
+)
+
+func TestCalculate(t *testing.T) {
+	y, err := Calculate(farm, &pool, assets, now)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.InDelta(t, 0.1, y.Apr, 1e-12, "$3650 a year over $36500")
+	assert.InDelta(t, math.Pow(1+0.1/365, 365)-1, y.Apy, 1e-12)
+	assert.Equal(t, 0.2, y.ReportedApy)
+	assert.InDelta(t, y.Apy+0.05, y.TotalApy, 1e-12)
+	assert.Equal(t, 24*time.Hour, y.MinStakeDuration)
+
+	if assert.Len(t, y.Streams, 2) {
+		ton, usdt := y.Streams[0], y.Streams[1]
+		assert.True(t, ton.Active())
+		assert.Equal(t, "TON", ton.Symbol)
+		assert.Equal(t, 10.0, ton.RatePerDayUsd)
+		assert.Equal(t, now.Add(5*24*time.Hour), ton.RunsOutAt)
+		assert.InDelta(t, 0.1, ton.Apr, 1e-12)
+		assert.False(t, usdt.Active())
+		assert.True(t, usdt.RunsOutAt.IsZero())
+	}
+}
+
+func TestProject(t *testing.T) {
+	y, err := Calculate(farm, &pool, assets, now)
+	if !assert.NoError(t, err) {
+		return
+	}
+	p := y.Project(36500, 10*24*time.Hour)
+	assert.Equal(t, 0.5, p.Share, "the stake doubles the farm")
+	if assert.Len(t, p.Rewards, 1) {
+		assert.InDelta(t, 25, p.Rewards[0].Amount, 1e-9, "half of $10 a day until the stream runs out after 5 days")
+		assert.InDelta(t, 5, p.Rewards[0].Days, 1e-9)
+	}
+	assert.InDelta(t, 36500*(math.Pow(1.05, 10.0/365)-1), p.FeesUsd, 1e-9)
+	assert.InDelta(t, p.RewardsUsd+p.FeesUsd, p.TotalUsd, 1e-9)
+	assert.Equal(t, now.Add(24*time.Hour), p.UnstakableAt)
+
+	assert.Zero(t, y.Project(0, time.Hour).TotalUsd)
+}
+
+func TestCalculateErrors(t *testing.T) {
+	_, err := Calculate(farm, nil, assets[1:], now)
+	assert.ErrorContains(t, err, "no asset for reward token")
+
+	bad := farm
+	bad.MinStakeDurationS = "a day"
+	_, err = Calculate(bad, nil, assets, now)
+	assert.ErrorContains(t, err, "min_stake_duration_s")
+}
+
+func TestForFarm(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Assets: assets, Pools: []types.Pool{pool}, Farms: []types.Farm{farm}})
+	defer s.Close()
+
+	y, err := ForFarm(context.Background(), s.Client(), farmAddress)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.InDelta(t, 0.1, y.Apr, 1e-12)
+	assert.Equal(t, 0.05, y.PoolApy7D)
+}
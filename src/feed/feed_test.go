@@ -0,0 +1,178 @@
+package feed
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	notAddress  = "EQAvlWFDxGF2lXm67y4yzC17wYKD9A0guwPkMs1gOsM__NOT"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	otherPool   = "EQCaY8Ifl2S6lRBMBJeY35LIuMXPc8JfItWG4tl7lBGrSoR2"
+)
+
+func newHub(t *testing.T, opts Options) (*stonfitest.Server, *Hub) {
+	upstream := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{
+		Assets: []types.Asset{
+			{ContractAddress: tonAddress, Symbol: "TON", DexPriceUsd: "5"},
+			{ContractAddress: usdtAddress, Symbol: "USDT", DexPriceUsd: "1"},
+			{ContractAddress: notAddress, Symbol: "NOT", DexPriceUsd: "0.01"},
+		},
+		Pools: []types.Pool{
+			{Address: poolAddress, Token0Address: tonAddress, Token1Address: usdtAddress, Reserve0: "1000000000000", Reserve1: "5000000000"},
+			{Address: otherPool, Token0Address: tonAddress, Token1Address: notAddress, Reserve0: "1000000000000", Reserve1: "500000000000000"},
+		},
+	})
+	t.Cleanup(upstream.Close)
+	h := New(upstream.Client(), opts)
+	if _, err := h.Poll(context.Background()); !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return upstream, h
+}
+
+func TestSubscription(t *testing.T) {
+	upstream, h := newHub(t, Options{})
+	ctx := context.Background()
+
+	sub := h.Subscribe(Filter{Pools: []string{poolAddress}, MinChange: 0.01})
+	defer sub.Close()
+	events := sub.Take()
+	if assert.Len(t, events, 1, "the current state comes first") {
+		assert.Equal(t, poolAddress, events[0].Pool.Address)
+		assert.Equal(t, 1.0, events[0].Change)
+	}
+
+	upstream.SetReserves(poolAddress, "1005000000000", "4975000000")
+	n, err := h.Poll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Empty(t, sub.Take(), "under min_change")
+
+	upstream.SetReserves(poolAddress, "1015000000000", "4926000000")
+	h.Poll(ctx)
+	upstream.SetReserves(poolAddress, "1030000000000", "4854000000")
+	h.Poll(ctx)
+	select {
+	case <-sub.Ready():
+	default:
+		t.Error("not ready")
+	}
+	events = sub.Take()
+	if assert.Len(t, events, 1, "pending updates coalesce") {
+		assert.Equal(t, "1030000000000", events[0].Pool.Reserve0)
+		assert.InDelta(t, 0.03, events[0].Change, 1e-9, "measured from the state delivered")
+	}
+
+	upstream.SetReserves(poolAddress, "1040000000000", "4807000000")
+	h.Poll(ctx)
+	upstream.SetReserves(poolAddress, "1030000000000", "4854000000")
+	h.Poll(ctx)
+	assert.Empty(t, sub.Take(), "a change undone before delivery is dropped")
+
+	sub.SetFilter(Filter{Assets: []string{notAddress}})
+	var kinds []string
+	for _, e := range sub.Take() {
+		if e.Pool != nil {
+			kinds = append(kinds, e.Pool.Address)
+		} else {
+			kinds = append(kinds, e.Asset.Symbol)
+		}
+	}
+	assert.ElementsMatch(t, []string{otherPool, "NOT"}, kinds, "an asset's pools come with it")
+
+	sub.Close()
+	assert.Equal(t, 0, h.Subscribers())
+}
+
+func TestSSE(t *testing.T) {
+	upstream, h := newHub(t, Options{Heartbeat: 50 * time.Millisecond})
+	srv := httptest.NewServer(h.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/events?min_change=-1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp.Body.Close()
+	}
+
+	resp, err = http.Get(srv.URL + "/events?pools=" + poolAddress)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	lines := bufio.NewScanner(resp.Body)
+	next := func() (event, data string) {
+		for lines.Scan() {
+			line := lines.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "":
+				return event, data
+			}
+		}
+		return "", ""
+	}
+
+	event, data := next()
+	assert.Equal(t, KindPool, event)
+	assert.Contains(t, data, `"reserve0":"1000000000000"`)
+
+	upstream.SetReserves(poolAddress, "1100000000000", "4600000000")
+	h.Poll(context.Background())
+	event, data = next()
+	assert.Equal(t, KindPool, event)
+	assert.Contains(t, data, `"reserve0":"1100000000000"`)
+
+	event, _ = next()
+	assert.Equal(t, KindHeartbeat, event)
+}
+
+func TestWebSocket(t *testing.T) {
+	_, h := newHub(t, Options{})
+	srv := httptest.NewServer(h.Handler())
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/ws?pools="+otherPool, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.CloseNow()
+	var e Event
+	if assert.NoError(t, wsjson.Read(ctx, conn, &e)) {
+		assert.Equal(t, otherPool, e.Pool.Address)
+	}
+
+	assert.NoError(t, wsjson.Write(ctx, conn, Filter{Assets: []string{usdtAddress}, MinChange: 0.5}))
+	got := map[string]bool{}
+	for range 2 {
+		var e Event
+		if assert.NoError(t, wsjson.Read(ctx, conn, &e)) {
+			if e.Pool != nil {
+				got[e.Pool.Address] = true
+			} else {
+				got[e.Asset.Symbol] = true
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{poolAddress: true, "USDT": true}, got)
+}
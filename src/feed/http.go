@@ -0,0 +1,131 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// Handler serves the feed: Server-Sent Events at `/events` and WebSocket at
+// `/ws`. Both take a filter in the pools, assets and min_change query
+// parameters; WebSocket clients can replace it by sending a Filter as JSON.
+// Each event is a JSON Event; over SSE, its kind is the event name.
+func (h *Hub) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /events", h.serveSSE)
+	mux.HandleFunc("GET /ws", h.serveWebSocket)
+	return mux
+}
+
+func (h *Hub) serveSSE(w http.ResponseWriter, r *http.Request) {
+	f, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	sub := h.Subscribe(f)
+	defer sub.Close()
+	h.stream(r.Context(), sub, func(events []Event) error {
+		// Not every ResponseWriter supports deadlines; without one, a slow
+		// client only holds up its own handler.
+		rc.SetWriteDeadline(h.now().Add(h.opts.WriteTimeout))
+		for _, e := range events {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Kind, data); err != nil {
+				return err
+			}
+		}
+		return rc.Flush()
+	})
+}
+
+func (h *Hub) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	f, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: h.opts.OriginPatterns})
+	if err != nil {
+		return
+	}
+	defer conn.CloseNow()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	sub := h.Subscribe(f)
+	defer sub.Close()
+	go func() {
+		defer cancel()
+		for {
+			var f Filter
+			if err := wsjson.Read(ctx, conn, &f); err != nil {
+				return
+			}
+			if f.MinChange < 0 {
+				conn.Close(websocket.StatusPolicyViolation, "min_change must not be negative")
+				return
+			}
+			sub.SetFilter(f)
+		}
+	}()
+	err = h.stream(ctx, sub, func(events []Event) error {
+		ctx, cancel := context.WithTimeout(ctx, h.opts.WriteTimeout)
+		defer cancel()
+		for _, e := range events {
+			if err := wsjson.Write(ctx, conn, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		conn.Close(websocket.StatusPolicyViolation, "too slow")
+		return
+	}
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// stream writes the events of sub as they come, and a heartbeat when there
+// have been none for a while, until ctx is done or a write fails.
+func (h *Hub) stream(ctx context.Context, sub *Subscription, write func([]Event) error) error {
+	heartbeat := time.NewTicker(h.opts.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sub.Ready():
+			events := sub.Take()
+			if len(events) == 0 {
+				continue
+			}
+			if err := write(events); err != nil {
+				return err
+			}
+			heartbeat.Reset(h.opts.Heartbeat)
+		case <-heartbeat.C:
+			if err := write([]Event{{Kind: KindHeartbeat, At: h.now()}}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package feed pushes live pool reserves and asset prices to subscribers. A
+// Hub polls the pools and assets centrally, diffs each poll against the
+// previous one, and fans the changes out to every subscription that matches
+// them, over Server-Sent Events or WebSocket.
+//
+// A subscription never blocks the hub. Its pending updates are kept by pool
+// or asset, so a subscriber that falls behind gets only the latest state of
+// each, not every state it missed.
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Event kinds.
+const (
+	KindPool      = "pool"
+	KindAsset     = "asset"
+	KindHeartbeat = "heartbeat"
+)
+
+// Event is a change of a pool or an asset, or a heartbeat.
+type Event struct {
+	Kind  string       `json:"kind"`
+	Pool  *types.Pool  `json:"pool,omitempty"`
+	Asset *types.Asset `json:"asset,omitempty"`
+	// Change is the largest relative change of the pool's reserves, or of
+	// the asset's price, since the state last sent to the subscriber: 0.01
+	// for 1%. It is 1 for the first state sent.
+	Change float64   `json:"change"`
+	At     time.Time `json:"at"`
+}
+
+// Options configures a Hub.
+type Options struct {
+	// Interval is the time between polls. Defaults to 10 seconds.
+	Interval time.Duration
+	// Heartbeat is the time between heartbeats sent to idle subscribers.
+	// Defaults to 15 seconds.
+	Heartbeat time.Duration
+	// WriteTimeout is how long a write to a subscriber may block before it
+	// is disconnected. Defaults to 10 seconds.
+	WriteTimeout time.Duration
+	// OriginPatterns are the hosts, besides the feed's own, whose pages may
+	// open WebSocket connections, such as "app.example.com" or
+	// "*.example.com".
+	OriginPatterns []string
+}
+
+// Hub polls the API and fans changes out to subscriptions.
+type Hub struct {
+	client *client.StonfiClient
+	opts   Options
+	now    func() time.Time
+
+	mu     sync.Mutex
+	pools  map[string]types.Pool
+	assets map[string]types.Asset
+	subs   map[*Subscription]struct{}
+}
+
+// New returns a hub polling c.
+func New(c *client.StonfiClient, opts Options) *Hub {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+	if opts.Heartbeat <= 0 {
+		opts.Heartbeat = 15 * time.Second
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = 10 * time.Second
+	}
+	return &Hub{
+		client: c,
+		opts:   opts,
+		now:    time.Now,
+		pools:  map[string]types.Pool{},
+		assets: map[string]types.Asset{},
+		subs:   map[*Subscription]struct{}{},
+	}
+}
+
+// Poll fetches the pools and assets and publishes those that changed since
+// the previous poll, returning how many did. If one of the two fetches
+// fails, the other is still published.
+func (h *Hub) Poll(ctx context.Context) (int, error) {
+	var errs []error
+	pools, err := h.client.GetPools(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("feed: fetching pools: %w", err))
+	}
+	assets, err := h.client.GetAssets(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("feed: fetching assets: %w", err))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	at := h.now()
+	var changed []Event
+	if pools != nil {
+		for _, p := range pools.PoolList {
+			key := utils.AddressKey(p.Address)
+			if old, ok := h.pools[key]; ok && old == p {
+				continue
+			}
+			h.pools[key] = p
+			changed = append(changed, Event{Kind: KindPool, Pool: &p, At: at})
+		}
+	}
+	if assets != nil {
+		for _, a := range assets.AssetList {
+			key := utils.AddressKey(a.ContractAddress)
+			if old, ok := h.assets[key]; ok && priceOf(old) == priceOf(a) {
+				continue
+			}
+			h.assets[key] = a
+			changed = append(changed, Event{Kind: KindAsset, Asset: &a, At: at})
+		}
+	}
+	for sub := range h.subs {
+		for _, e := range changed {
+			sub.offer(e)
+		}
+	}
+	return len(changed), errors.Join(errs...)
+}
+
+// Run polls until ctx is done, every Interval. Each outcome is passed to
+// report, if set.
+func (h *Hub) Run(ctx context.Context, report func(n int, err error)) {
+	ticker := time.NewTicker(h.opts.Interval)
+	defer ticker.Stop()
+	for {
+		n, err := h.Poll(ctx)
+		if report != nil {
+			report(n, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Subscribe returns a subscription to the changes matching f. It starts
+// with the current state of every pool and asset f matches.
+func (h *Hub) Subscribe(f Filter) *Subscription {
+	sub := &Subscription{hub: h, ready: make(chan struct{}, 1)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs[sub] = struct{}{}
+	h.reset(sub, f)
+	return sub
+}
+
+// Subscribers returns the number of open subscriptions.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// reset sets the filter of sub, dropping what it has pending, and offers it
+// the current state. h.mu must be held.
+func (h *Hub) reset(sub *Subscription, f Filter) {
+	sub.mu.Lock()
+	sub.filter = f.compile()
+	sub.pending = map[string]Event{}
+	sub.order = nil
+	sub.sent = map[string][2]float64{}
+	sub.mu.Unlock()
+	at := h.now()
+	for _, p := range h.pools {
+		sub.offer(Event{Kind: KindPool, Pool: &p, At: at})
+	}
+	for _, a := range h.assets {
+		sub.offer(Event{Kind: KindAsset, Asset: &a, At: at})
+	}
+}
+
+// values returns the numbers whose change an event is measured by: a
+// pool's reserves, or an asset's price.
+func values(e Event) [2]float64 {
+	if e.Pool != nil {
+		return [2]float64{utils.ParseFloat(e.Pool.Reserve0), utils.ParseFloat(e.Pool.Reserve1)}
+	}
+	return [2]float64{utils.ParseFloat(priceOf(*e.Asset)), 0}
+}
+
+// change returns the largest relative change from old to current.
+func change(old, current [2]float64) float64 {
+	largest := 0.0
+	for i := range old {
+		switch {
+		case old[i] == current[i]:
+		case old[i] == 0:
+			largest = 1
+		default:
+			largest = math.Max(largest, math.Abs(current[i]-old[i])/math.Abs(old[i]))
+		}
+	}
+	return largest
+}
+
+func priceOf(a types.Asset) string {
+	if a.DexPriceUsd != "" {
+		return a.DexPriceUsd
+	}
+	return a.DexUsdPrice
+}
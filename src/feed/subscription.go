@@ -0,0 +1,174 @@
+package feed
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Filter selects the changes a subscription receives. Without pools or
+// assets, it matches every pool and asset.
+type Filter struct {
+	// Pools are the addresses of the pools to receive.
+	Pools []string `json:"pools,omitempty"`
+	// Assets are the addresses of the assets to receive, and of the tokens
+	// whose pools to receive.
+	Assets []string `json:"assets,omitempty"`
+	// MinChange is the smallest relative change of a pool's reserves or an
+	// asset's price sent, measured from the state last sent: 0.01 for 1%.
+	MinChange float64 `json:"min_change,omitempty"`
+}
+
+// ParseFilter reads a filter from the pools, assets and min_change query
+// parameters. Addresses are comma separated.
+func ParseFilter(q url.Values) (Filter, error) {
+	var f Filter
+	split := func(v string) []string {
+		var addresses []string
+		for _, a := range strings.Split(v, ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				addresses = append(addresses, a)
+			}
+		}
+		return addresses
+	}
+	f.Pools, f.Assets = split(q.Get("pools")), split(q.Get("assets"))
+	if v := q.Get("min_change"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n < 0 {
+			return f, fmt.Errorf("feed: min_change must be a non-negative number, not %q", v)
+		}
+		f.MinChange = n
+	}
+	return f, nil
+}
+
+type filter struct {
+	pools, assets map[string]bool
+	minChange     float64
+}
+
+func (f Filter) compile() filter {
+	c := filter{pools: map[string]bool{}, assets: map[string]bool{}, minChange: f.MinChange}
+	for _, p := range f.Pools {
+		c.pools[utils.AddressKey(p)] = true
+	}
+	for _, a := range f.Assets {
+		c.assets[utils.AddressKey(a)] = true
+	}
+	return c
+}
+
+func (f filter) match(e Event) bool {
+	if len(f.pools) == 0 && len(f.assets) == 0 {
+		return true
+	}
+	if e.Pool != nil {
+		return f.pools[utils.AddressKey(e.Pool.Address)] ||
+			f.assets[utils.AddressKey(e.Pool.Token0Address)] || f.assets[utils.AddressKey(e.Pool.Token1Address)]
+	}
+	return f.assets[utils.AddressKey(e.Asset.ContractAddress)]
+}
+
+// Subscription receives the changes matching its filter.
+type Subscription struct {
+	hub   *Hub
+	ready chan struct{}
+
+	mu     sync.Mutex
+	filter filter
+	// pending holds the latest undelivered event of each pool and asset, in
+	// the order they first became pending.
+	pending map[string]Event
+	order   []string
+	// sent holds the values of the last event delivered of each pool and
+	// asset, which changes are measured from.
+	sent   map[string][2]float64
+	closed bool
+}
+
+// Ready returns a channel that receives when events are pending.
+func (s *Subscription) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Take returns the pending events and clears them.
+func (s *Subscription) Take() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := make([]Event, 0, len(s.order))
+	for _, key := range s.order {
+		e := s.pending[key]
+		s.sent[key] = values(e)
+		events = append(events, e)
+	}
+	s.pending, s.order = map[string]Event{}, nil
+	return events
+}
+
+// SetFilter replaces the filter. The subscription starts over, with the
+// current state of everything the new filter matches.
+func (s *Subscription) SetFilter(f Filter) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subs[s]; ok {
+		s.hub.reset(s, f)
+	}
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	delete(s.hub.subs, s)
+	s.hub.mu.Unlock()
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+}
+
+// offer queues e if it matches the filter and changed enough since the
+// state last delivered, replacing the pending event of the same pool or
+// asset. It never blocks.
+func (s *Subscription) offer(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || !s.filter.match(e) {
+		return
+	}
+	key := e.Kind + ":"
+	if e.Pool != nil {
+		key += utils.AddressKey(e.Pool.Address)
+	} else {
+		key += utils.AddressKey(e.Asset.ContractAddress)
+	}
+	e.Change = 1
+	if sent, ok := s.sent[key]; ok {
+		e.Change = change(sent, values(e))
+	}
+	_, pending := s.pending[key]
+	if e.Change < s.filter.minChange {
+		// Back to about what was delivered: what was pending is moot.
+		if pending {
+			delete(s.pending, key)
+			for i, k := range s.order {
+				if k == key {
+					s.order = append(s.order[:i], s.order[i+1:]...)
+					break
+				}
+			}
+		}
+		return
+	}
+	if !pending {
+		s.order = append(s.order, key)
+	}
+	s.pending[key] = e
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,233 @@
+// Package indexer keeps a local SQLite index of the operations reported by
+// `/v1/stats/operations`. It backfills from client.EarliestDate in windows of
+// at most 24 hours, then tails new operations, storing each one once.
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/migrate"
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/utils"
+	_ "modernc.org/sqlite"
+)
+
+// Options configures an Indexer.
+type Options struct {
+	// Start is where the backfill begins. Defaults to client.EarliestDate.
+	Start time.Time
+	// Window is the span of each request, at most and by default 24h.
+	Window time.Duration
+	// Overlap is how far before the checkpoint each sync starts again, to pick
+	// up operations the API reports late. Defaults to 5 minutes.
+	Overlap time.Duration
+	// PollInterval is the time between syncs once caught up. Defaults to 1 minute.
+	PollInterval time.Duration
+}
+
+// Indexer stores operations in a SQLite database.
+type Indexer struct {
+	client *client.StonfiClient
+	db     *sql.DB
+	opts   Options
+	now    func() time.Time
+}
+
+// Open opens or creates the index at path. Use ":memory:" for a throwaway
+// index.
+func Open(path string, c *client.StonfiClient, opts Options) (*Indexer, error) {
+	if opts.Start.IsZero() || opts.Start.Before(client.EarliestDate) {
+		opts.Start = client.EarliestDate
+	}
+	if opts.Window <= 0 || opts.Window > 24*time.Hour {
+		opts.Window = 24 * time.Hour
+	}
+	if opts.Overlap <= 0 {
+		opts.Overlap = 5 * time.Minute
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Minute
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("indexer: opening %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+	ix := &Indexer{client: c, db: db, opts: opts, now: time.Now}
+	if err := ix.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return ix, nil
+}
+
+// Close closes the database.
+func (ix *Indexer) Close() error {
+	return ix.db.Close()
+}
+
+// Checkpoint returns the time up to which operations have been indexed, or
+// the zero time if nothing has been indexed yet.
+func (ix *Indexer) Checkpoint(ctx context.Context) (time.Time, error) {
+	var until sql.NullInt64
+	err := ix.db.QueryRowContext(ctx, `SELECT until FROM checkpoint WHERE id = 1`).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !until.Valid) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("indexer: reading checkpoint: %w", err)
+	}
+	return time.UnixMilli(until.Int64).UTC(), nil
+}
+
+// Sync indexes the operations from the checkpoint up to now and returns how
+// many were new. Progress is saved after each window, so an interrupted sync
+// resumes where it stopped.
+func (ix *Indexer) Sync(ctx context.Context) (int, error) {
+	checkpoint, err := ix.Checkpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	start := ix.opts.Start
+	if !checkpoint.IsZero() {
+		start = checkpoint.Add(-ix.opts.Overlap)
+		if start.Before(ix.opts.Start) {
+			start = ix.opts.Start
+		}
+	}
+	now := ix.now().UTC().Truncate(time.Second)
+
+	total := 0
+	for start.Before(now) {
+		end := start.Add(ix.opts.Window)
+		if end.After(now) {
+			end = now
+		}
+		n, err := ix.syncWindow(ctx, start, end)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		start = end
+	}
+	return total, nil
+}
+
+// Run syncs until ctx is done, every PollInterval once caught up. Each
+// outcome is passed to report, if set.
+func (ix *Indexer) Run(ctx context.Context, report func(n int, err error)) {
+	ticker := time.NewTicker(ix.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		n, err := ix.Sync(ctx)
+		if report != nil {
+			report(n, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ix *Indexer) syncWindow(ctx context.Context, start, end time.Time) (int, error) {
+	response, err := ix.client.GetHistoricalSwaps(ctx, start, end)
+	if err != nil {
+		return 0, fmt.Errorf("indexer: fetching operations from %s to %s: %w", start.Format(time.RFC3339), end.Format(time.RFC3339), err)
+	}
+
+	tx, err := ix.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("indexer: %w", err)
+	}
+	defer tx.Rollback()
+
+	inserted := 0
+	for _, info := range response.Operations {
+		op := info.Operation
+		data, err := json.Marshal(info)
+		if err != nil {
+			return 0, fmt.Errorf("indexer: encoding operation %s: %w", op.PoolTxHash, err)
+		}
+		res, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO operations (
+			pool_tx_hash, pool_tx_lt, pool_address, wallet_address, asset0_address, asset1_address,
+			operation_type, timestamp, success, data
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			op.PoolTxHash, op.PoolTxLt, utils.AddressKey(op.PoolAddress), utils.AddressKey(op.WalletAddress),
+			utils.AddressKey(op.Asset0Address), utils.AddressKey(op.Asset1Address),
+			op.OperationType, parseTimestamp(op.PoolTxTimestamp), op.Success, data,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("indexer: storing operation %s: %w", op.PoolTxHash, err)
+		}
+		n, _ := res.RowsAffected()
+		inserted += int(n)
+	}
+
+	// The checkpoint never moves back, which an overlapping sync would do.
+	_, err = tx.ExecContext(ctx, `INSERT INTO checkpoint (id, until) VALUES (1, ?)
+		ON CONFLICT (id) DO UPDATE SET until = MAX(until, excluded.until)`, end.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("indexer: saving checkpoint: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("indexer: %w", err)
+	}
+	return inserted, nil
+}
+
+// parseTimestamp returns the Unix milliseconds of a pool_tx_timestamp, or
+// nil if it cannot be parsed.
+func parseTimestamp(s string) interface{} {
+	t, err := openapi.ParseTime(s)
+	if err != nil {
+		return nil
+	}
+	return t.UnixMilli()
+}
+
+// migrations are applied in order by migrate.Apply. Never edit a released
+// migration, add a new one.
+var migrations = []string{
+	`
+	CREATE TABLE operations (
+		pool_tx_hash   TEXT    NOT NULL,
+		pool_tx_lt     INTEGER NOT NULL,
+		pool_address   TEXT    NOT NULL,
+		wallet_address TEXT    NOT NULL,
+		asset0_address TEXT    NOT NULL,
+		asset1_address TEXT    NOT NULL,
+		operation_type TEXT    NOT NULL,
+		timestamp      INTEGER, -- Unix milliseconds of pool_tx_timestamp
+		success        INTEGER NOT NULL,
+		data           TEXT    NOT NULL, -- types.OperationInfo as JSON
+		PRIMARY KEY (pool_tx_hash, pool_tx_lt)
+	);
+	CREATE INDEX operations_pool ON operations (pool_address, timestamp);
+	CREATE INDEX operations_wallet ON operations (wallet_address, timestamp);
+	CREATE INDEX operations_asset0 ON operations (asset0_address, timestamp);
+	CREATE INDEX operations_asset1 ON operations (asset1_address, timestamp);
+	CREATE INDEX operations_type ON operations (operation_type, timestamp);
+	CREATE INDEX operations_timestamp ON operations (timestamp);
+
+	CREATE TABLE checkpoint (
+		id    INTEGER PRIMARY KEY CHECK (id = 1),
+		until INTEGER NOT NULL -- Unix milliseconds
+	);
+	`,
+}
+
+func (ix *Indexer) migrate(ctx context.Context) error {
+	if err := migrate.Apply(ctx, ix.db, migrations); err != nil {
+		return fmt.Errorf("indexer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package indexer
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	wallet      = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func operation(hash string, lt int64, opType, timestamp string) types.OperationInfo {
+	return types.OperationInfo{Operation: types.Operation{
+		PoolTxHash:      hash,
+		PoolTxLt:        lt,
+		PoolAddress:     poolAddress,
+		WalletAddress:   wallet,
+		Asset0Address:   tonAddress,
+		Asset1Address:   usdtAddress,
+		OperationType:   opType,
+		PoolTxTimestamp: timestamp,
+		Success:         true,
+	}}
+}
+
+func collect(t *testing.T, seq func(func(types.OperationInfo, error) bool)) []string {
+	var hashes []string
+	for info, err := range seq {
+		assert.NoError(t, err)
+		hashes = append(hashes, info.Operation.PoolTxHash)
+	}
+	return hashes
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Operations: types.Operations{
+		operation("a", 1, "swap", "2024-01-01T10:00:00"),
+		operation("b", 2, "provide", "2024-01-02T00:00:00"), // on a window boundary
+		operation("c", 3, "swap", "2024-01-03T06:00:00"),
+	}})
+	defer s.Close()
+
+	path := filepath.Join(t.TempDir(), "operations.db")
+	ix, err := Open(path, s.Client(), Options{Start: start})
+	if !assert.NoError(t, err) {
+		return
+	}
+	ix.now = func() time.Time { return start.Add(60 * time.Hour) }
+
+	n, err := ix.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n, "operations on window boundaries are stored once")
+	assert.Len(t, s.Requests(), 3)
+	checkpoint, err := ix.Checkpoint(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, start.Add(60*time.Hour), checkpoint)
+	assert.NoError(t, ix.Close())
+
+	// Reopening resumes from the checkpoint.
+	s.AddOperation(operation("d", 4, "swap", "2024-01-03T12:30:00"))
+	ix, err = Open(path, s.Client(), Options{Start: start})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ix.Close()
+	ix.now = func() time.Time { return start.Add(61 * time.Hour) }
+	n, err = ix.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, s.Requests(), 4)
+	assert.Contains(t, s.Requests()[3], "since=2024-01-03T11%3A55%3A00", "syncs overlap the checkpoint")
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, collect(t, ix.ByPool(ctx, "0:fc4c9f311160754a99d113877cf583b78e5d16d048819cd4b820168769499d7e")))
+	assert.Equal(t, []string{"a", "c", "d"}, collect(t, ix.ByType(ctx, "swap")))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, collect(t, ix.ByWallet(ctx, wallet)))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, collect(t, ix.ByAsset(ctx, usdtAddress)))
+	assert.Empty(t, collect(t, ix.ByAsset(ctx, poolAddress)))
+	assert.Equal(t, []string{"b", "c"}, collect(t, ix.Operations(ctx, Filter{
+		Since: start.Add(24 * time.Hour),
+		Until: start.Add(54 * time.Hour),
+	})))
+}
+
+func TestOperationsPages(t *testing.T) {
+	ctx := context.Background()
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Operations: types.Operations{
+		operation("a", 1, "swap", "2024-01-01T10:00:00"),
+		operation("b", 2, "swap", "2024-01-01T10:00:00"),
+		operation("c", 3, "swap", "2024-01-01T11:00:00"),
+	}})
+	defer s.Close()
+	ix, err := Open(":memory:", s.Client(), Options{Start: start, Window: time.Hour})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ix.Close()
+	ix.now = func() time.Time { return start.Add(12 * time.Hour) }
+	_, err = ix.Sync(ctx)
+	assert.NoError(t, err)
+
+	defer func(n int) { pageSize = n }(pageSize)
+	pageSize = 2
+	var hashes []string
+	for info, err := range ix.ByType(ctx, "swap") {
+		assert.NoError(t, err)
+		// The index is queried while iterating, which would wait forever for
+		// the connection if the iterator held it.
+		n, err := ix.Count(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		hashes = append(hashes, info.Operation.PoolTxHash)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, hashes)
+}
+
+func TestSyncResumesAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Operations: types.Operations{
+		operation("a", 1, "swap", "2024-01-01T10:00:00"),
+		operation("b", 2, "swap", "2024-01-02T10:00:00"),
+	}})
+	defer s.Close()
+
+	// Fail the request for the second window.
+	c := s.Client()
+	calls := 0
+	c.Use(client.HookFuncs{BeforeFunc: func(ctx context.Context, call *client.CallInfo) context.Context {
+		if calls++; calls == 2 {
+			s.FailNext(http.StatusBadGateway, 1)
+		}
+		return ctx
+	}})
+
+	ix, err := Open(":memory:", c, Options{Start: start})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ix.Close()
+	ix.now = func() time.Time { return start.Add(48 * time.Hour) }
+
+	n, err := ix.Sync(ctx)
+	assert.ErrorContains(t, err, "status code: 502")
+	assert.Equal(t, 1, n)
+	checkpoint, err := ix.Checkpoint(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, start.Add(24*time.Hour), checkpoint)
+
+	n, err = ix.Sync(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	count, err := ix.Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,144 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Filter selects indexed operations. Empty fields match everything; addresses
+// may be in any form.
+type Filter struct {
+	Pool          string
+	Wallet        string
+	Asset         string // either side of the operation
+	OperationType string
+	Since         time.Time
+	Until         time.Time
+}
+
+// pageSize is the number of operations Operations reads at a time.
+var pageSize = 500
+
+// Operations yields the indexed operations matching f, oldest first. Iteration
+// stops at the first error, which is yielded.
+//
+// Operations are read a page at a time, and each page is read in full before
+// it is yielded, so the loop body may query the index too: the database has a
+// single connection, which an open result set would hold.
+func (ix *Indexer) Operations(ctx context.Context, f Filter) iter.Seq2[types.OperationInfo, error] {
+	return func(yield func(types.OperationInfo, error) bool) {
+		var where []string
+		var args []interface{}
+		if f.Pool != "" {
+			where = append(where, "pool_address = ?")
+			args = append(args, utils.AddressKey(f.Pool))
+		}
+		if f.Wallet != "" {
+			where = append(where, "wallet_address = ?")
+			args = append(args, utils.AddressKey(f.Wallet))
+		}
+		if f.Asset != "" {
+			where = append(where, "(asset0_address = ? OR asset1_address = ?)")
+			args = append(args, utils.AddressKey(f.Asset), utils.AddressKey(f.Asset))
+		}
+		if f.OperationType != "" {
+			where = append(where, "operation_type = ?")
+			args = append(args, f.OperationType)
+		}
+		if !f.Since.IsZero() {
+			where = append(where, "timestamp >= ?")
+			args = append(args, f.Since.UnixMilli())
+		}
+		if !f.Until.IsZero() {
+			where = append(where, "timestamp <= ?")
+			args = append(args, f.Until.UnixMilli())
+		}
+		// Pages follow on from the last operation of the previous one, by
+		// (timestamp, pool_tx_lt, rowid). Operations without a timestamp
+		// come first.
+		where = append(where, "(IFNULL(timestamp, -1), pool_tx_lt, rowid) > (?, ?, ?)")
+		query := `SELECT IFNULL(timestamp, -1), pool_tx_lt, rowid, data FROM operations WHERE ` + strings.Join(where, " AND ") +
+			` ORDER BY IFNULL(timestamp, -1), pool_tx_lt, rowid LIMIT ?`
+
+		last := []interface{}{int64(-2), int64(0), int64(0)}
+		for {
+			page, err := ix.operationsPage(ctx, query, append(append(args, last...), pageSize), last)
+			if err != nil {
+				yield(types.OperationInfo{}, err)
+				return
+			}
+			for _, info := range page {
+				if !yield(info, nil) {
+					return
+				}
+			}
+			if len(page) < pageSize {
+				return
+			}
+		}
+	}
+}
+
+// operationsPage runs a page query of Operations, and stores the position of
+// its last operation in last.
+func (ix *Indexer) operationsPage(ctx context.Context, query string, args []interface{}, last []interface{}) ([]types.OperationInfo, error) {
+	rows, err := ix.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("indexer: querying operations: %w", err)
+	}
+	defer rows.Close()
+	var page []types.OperationInfo
+	for rows.Next() {
+		var timestamp, lt, rowid int64
+		var data []byte
+		var info types.OperationInfo
+		if err := rows.Scan(&timestamp, &lt, &rowid, &data); err != nil {
+			return nil, fmt.Errorf("indexer: scanning operation: %w", err)
+		}
+		if err := json.Unmarshal(data, &info); err != nil {
+			return nil, fmt.Errorf("indexer: decoding operation: %w", err)
+		}
+		page = append(page, info)
+		last[0], last[1], last[2] = timestamp, lt, rowid
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("indexer: querying operations: %w", err)
+	}
+	return page, nil
+}
+
+// ByPool yields the operations of a pool.
+func (ix *Indexer) ByPool(ctx context.Context, pool string) iter.Seq2[types.OperationInfo, error] {
+	return ix.Operations(ctx, Filter{Pool: pool})
+}
+
+// ByWallet yields the operations of a wallet.
+func (ix *Indexer) ByWallet(ctx context.Context, wallet string) iter.Seq2[types.OperationInfo, error] {
+	return ix.Operations(ctx, Filter{Wallet: wallet})
+}
+
+// ByAsset yields the operations involving an asset.
+func (ix *Indexer) ByAsset(ctx context.Context, asset string) iter.Seq2[types.OperationInfo, error] {
+	return ix.Operations(ctx, Filter{Asset: asset})
+}
+
+// ByType yields the operations of a type, such as "swap" or "provide".
+func (ix *Indexer) ByType(ctx context.Context, operationType string) iter.Seq2[types.OperationInfo, error] {
+	return ix.Operations(ctx, Filter{OperationType: operationType})
+}
+
+// Count returns the number of indexed operations.
+func (ix *Indexer) Count(ctx context.Context) (int, error) {
+	var n int
+	if err := ix.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM operations`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("indexer: counting operations: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,45 @@
+// Package migrate applies schema migrations to the SQLite databases of the
+// snapshot and indexer packages.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Apply applies the migrations db has not seen yet, in order, each in its own
+// transaction. The schema version is the number of migrations applied, kept
+// in SQLite's user_version. Never edit a released migration, add a new one.
+func Apply(ctx context.Context, db *sql.DB, migrations []string) error {
+	var version int
+	if err := db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this program's %d", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		if err := apply(ctx, db, i+1, migrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func apply(ctx context.Context, db *sql.DB, version int, migration string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, migration); err != nil {
+		tx.Rollback()
+		return err
+	}
+	// PRAGMA does not take parameters.
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, version)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,32 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	migrations := []string{`CREATE TABLE a (id INTEGER)`}
+	assert.NoError(t, Apply(ctx, db, migrations))
+	assert.NoError(t, Apply(ctx, db, migrations), "applied migrations are skipped")
+
+	migrations = append(migrations, `CREATE TABLE b (id INTEGER)`, `CREATE TABLE a (id INTEGER)`)
+	assert.ErrorContains(t, Apply(ctx, db, migrations), "migration 3")
+	var version int
+	assert.NoError(t, db.QueryRow(`PRAGMA user_version`).Scan(&version))
+	assert.Equal(t, 2, version, "a failed migration is rolled back alone")
+
+	assert.ErrorContains(t, Apply(ctx, db, migrations[:1]), "newer than this program's 1")
+}
@@ -0,0 +1,291 @@
+// Command gen generates Go types, an operation table and client methods from
+// an OpenAPI 3 specification.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Enum       []string           `json:"enum"`
+	Nullable   bool               `json:"nullable"`
+	Items      *schema            `json:"items"`
+	Properties map[string]*schema `json:"properties"`
+	Required   []string           `json:"required"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type operation struct {
+	OperationID string      `json:"operationId"`
+	Summary     string      `json:"summary"`
+	Parameters  []parameter `json:"parameters"`
+	Responses   map[string]struct {
+		Content map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+type spec struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+func main() {
+	specPath := flag.String("spec", "openapi.json", "OpenAPI specification to read")
+	prefix := flag.String("prefix", "", "Path prefix to strip, already part of the client's base URL")
+	out := flag.String("out", "openapi.gen.go", "Go file to write")
+	pkg := flag.String("package", "openapi", "Package name of the generated file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Fatalf("parsing %s: %v", *specPath, err)
+	}
+
+	var body bytes.Buffer
+	generateSchemas(&body, &s)
+	generateOperations(&body, &s, *prefix)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen from %s; DO NOT EDIT.\n\n", *specPath)
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n", *pkg)
+	for _, imp := range []string{"context", "net/url", "strconv", "time"} {
+		if bytes.Contains(body.Bytes(), []byte(imp[strings.LastIndex(imp, "/")+1:]+".")) {
+			fmt.Fprintf(&buf, "%q\n", imp)
+		}
+	}
+	buf.WriteString(")\n\n")
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting generated code: %v\n%s", err, buf.Bytes())
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generateSchemas(buf *bytes.Buffer, s *spec) {
+	for _, name := range sortedKeys(s.Components.Schemas) {
+		sc := s.Components.Schemas[name]
+		typeName := goName(name)
+		if len(sc.Enum) > 0 {
+			fmt.Fprintf(buf, "type %s string\n\nconst (\n", typeName)
+			for _, v := range sc.Enum {
+				fmt.Fprintf(buf, "%s%s %s = %q\n", typeName, goName(v), typeName, v)
+			}
+			buf.WriteString(")\n\n")
+			continue
+		}
+		fmt.Fprintf(buf, "type %s %s\n\n", typeName, goType(sc))
+	}
+}
+
+func goType(sc *schema) string {
+	if sc.Ref != "" {
+		return goName(sc.Ref[strings.LastIndex(sc.Ref, "/")+1:])
+	}
+	var t string
+	switch sc.Type {
+	case "string":
+		t = "string"
+		if sc.Format == "date-time" {
+			t = "time.Time"
+		}
+	case "integer":
+		t = "int"
+		if sc.Format == "int64" {
+			t = "int64"
+		}
+	case "number":
+		t = "float64"
+	case "boolean":
+		t = "bool"
+	case "array":
+		return "[]" + goType(sc.Items)
+	case "object":
+		var b strings.Builder
+		b.WriteString("struct {\n")
+		for _, prop := range sortedKeys(sc.Properties) {
+			tag := prop
+			if !contains(sc.Required, prop) {
+				tag += ",omitempty"
+			}
+			fmt.Fprintf(&b, "%s %s `json:%q`\n", goName(prop), goType(sc.Properties[prop]), tag)
+		}
+		b.WriteString("}")
+		return b.String()
+	default:
+		return "interface{}"
+	}
+	if sc.Nullable {
+		return "*" + t
+	}
+	return t
+}
+
+func generateOperations(buf *bytes.Buffer, s *spec, prefix string) {
+	type opEntry struct {
+		path, method string
+		op           *operation
+	}
+	var ops []opEntry
+	for _, path := range sortedKeys(s.Paths) {
+		for _, method := range sortedKeys(s.Paths[path]) {
+			ops = append(ops, opEntry{path, method, s.Paths[path][method]})
+		}
+	}
+
+	buf.WriteString("// Operations lists every operation in the specification.\nvar Operations = []Operation{\n")
+	for _, e := range ops {
+		fmt.Fprintf(buf, "op%s,\n", goName(e.op.OperationID))
+	}
+	buf.WriteString("}\n\nvar (\n")
+	for _, e := range ops {
+		var pathParams, queryParams []string
+		for _, p := range e.op.Parameters {
+			switch p.In {
+			case "path":
+				pathParams = append(pathParams, fmt.Sprintf("%q", p.Name))
+			case "query":
+				queryParams = append(queryParams, fmt.Sprintf("{Name: %q, Required: %t}", p.Name, p.Required))
+			}
+		}
+		fmt.Fprintf(buf, "op%s = Operation{ID: %q, Method: %q, Path: %q, PathParams: []string{%s}, QueryParams: []Param{%s}}\n",
+			goName(e.op.OperationID), e.op.OperationID, httpMethod(e.method), strings.TrimPrefix(e.path, prefix),
+			strings.Join(pathParams, ", "), strings.Join(queryParams, ", "))
+	}
+	buf.WriteString(")\n\n")
+
+	for _, e := range ops {
+		generateMethod(buf, e.method, e.path, e.op)
+	}
+}
+
+func generateMethod(buf *bytes.Buffer, method, path string, op *operation) {
+	name := goName(op.OperationID)
+	response := "interface{}"
+	if r, ok := op.Responses["200"]; ok {
+		if c, ok := r.Content["application/json"]; ok && c.Schema != nil {
+			response = goType(c.Schema)
+		}
+	}
+
+	args := []string{"ctx context.Context"}
+	var query []parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			args = append(args, lowerFirst(goName(p.Name))+" string")
+		case "query":
+			query = append(query, p)
+		}
+	}
+	if len(query) > 0 {
+		fmt.Fprintf(buf, "// %sParams are the query parameters of %s.\ntype %sParams struct {\n", name, name, name)
+		for _, p := range query {
+			fmt.Fprintf(buf, "%s %s\n", goName(p.Name), goType(&schema{Type: p.Schema.Type, Format: p.Schema.Format, Items: p.Schema.Items}))
+		}
+		buf.WriteString("}\n\n")
+		args = append(args, "params "+name+"Params")
+	}
+
+	fmt.Fprintf(buf, "// %s calls %s %s (%s).\n", name, httpMethod(method), path, op.Summary)
+	fmt.Fprintf(buf, "func (c *Client) %s(%s) (*%s, error) {\nvalues := url.Values{}\n", name, strings.Join(args, ", "), response)
+	for _, p := range op.Parameters {
+		if p.In == "path" {
+			fmt.Fprintf(buf, "values.Set(%q, %s)\n", p.Name, lowerFirst(goName(p.Name)))
+		}
+	}
+	for _, p := range query {
+		field := "params." + goName(p.Name)
+		switch {
+		case p.Schema.Type == "array":
+			fmt.Fprintf(buf, "for _, v := range %s {\nvalues.Add(%q, v)\n}\n", field, p.Name)
+		case p.Schema.Type == "boolean":
+			fmt.Fprintf(buf, "if %s {\nvalues.Set(%q, strconv.FormatBool(%s))\n}\n", field, p.Name, field)
+		case p.Schema.Format == "date-time":
+			fmt.Fprintf(buf, "if !%s.IsZero() {\nvalues.Set(%q, %s.UTC().Format(TimeLayout))\n}\n", field, p.Name, field)
+		default:
+			fmt.Fprintf(buf, "if %s != \"\" {\nvalues.Set(%q, %s)\n}\n", field, p.Name, field)
+		}
+	}
+	fmt.Fprintf(buf, "var response %s\nif err := c.Caller.CallOperation(ctx, op%s, values, &response); err != nil {\nreturn nil, err\n}\nreturn &response, nil\n}\n\n", response, name)
+}
+
+func httpMethod(method string) string {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return strings.ToUpper(method)
+	}
+	log.Fatalf("unsupported method %s", method)
+	return ""
+}
+
+var initialisms = map[string]string{"id": "ID", "url": "URL", "api": "API"}
+
+// goName converts snake_case (or @-prefixed) identifiers to exported CamelCase.
+func goName(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '@' || r == '-' }) {
+		if v, ok := initialisms[part]; ok {
+			b.WriteString(v)
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
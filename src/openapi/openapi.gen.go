@@ -0,0 +1,704 @@
+// Code generated by gen from openapi.json; DO NOT EDIT.
+
+package openapi
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type AssetInfoSchema struct {
+	Balance            *string   `json:"balance,omitempty"`
+	Blacklisted        bool      `json:"blacklisted,omitempty"`
+	Community          bool      `json:"community,omitempty"`
+	ContractAddress    string    `json:"contract_address"`
+	Decimals           int       `json:"decimals"`
+	DefaultSymbol      bool      `json:"default_symbol,omitempty"`
+	Deprecated         bool      `json:"deprecated,omitempty"`
+	DexPriceUsd        *string   `json:"dex_price_usd,omitempty"`
+	DexUsdPrice        *string   `json:"dex_usd_price,omitempty"`
+	DisplayName        string    `json:"display_name,omitempty"`
+	ImageURL           string    `json:"image_url,omitempty"`
+	Kind               AssetKind `json:"kind"`
+	Priority           int       `json:"priority,omitempty"`
+	Symbol             string    `json:"symbol"`
+	Tags               []string  `json:"tags,omitempty"`
+	Taxable            bool      `json:"taxable,omitempty"`
+	ThirdPartyPriceUsd *string   `json:"third_party_price_usd,omitempty"`
+	ThirdPartyUsdPrice *string   `json:"third_party_usd_price,omitempty"`
+	WalletAddress      *string   `json:"wallet_address,omitempty"`
+}
+
+type AssetKind string
+
+const (
+	AssetKindTon        AssetKind = "Ton"
+	AssetKindWton       AssetKind = "Wton"
+	AssetKindJetton     AssetKind = "Jetton"
+	AssetKindNotAnAsset AssetKind = "NotAnAsset"
+)
+
+type AssetListResponse struct {
+	AssetList []AssetInfoSchema `json:"asset_list"`
+}
+
+type AssetResponse struct {
+	Asset AssetInfoSchema `json:"asset"`
+}
+
+type DexStatsResponse struct {
+	Since time.Time      `json:"since"`
+	Stats DexStatsSchema `json:"stats"`
+	Until time.Time      `json:"until"`
+}
+
+type DexStatsSchema struct {
+	Trades        int64  `json:"trades,omitempty"`
+	Tvl           string `json:"tvl,omitempty"`
+	UniqueWallets int64  `json:"unique_wallets,omitempty"`
+	VolumeUsd     string `json:"volume_usd,omitempty"`
+}
+
+type FarmInfoSchema struct {
+	Apy                *string             `json:"apy,omitempty"`
+	LockedTotalLp      string              `json:"locked_total_lp,omitempty"`
+	LockedTotalLpUsd   string              `json:"locked_total_lp_usd,omitempty"`
+	MinStakeDurationS  string              `json:"min_stake_duration_s,omitempty"`
+	MinterAddress      string              `json:"minter_address"`
+	NftInfos           []FarmNftInfoSchema `json:"nft_infos,omitempty"`
+	PoolAddress        string              `json:"pool_address"`
+	RewardTokenAddress string              `json:"reward_token_address,omitempty"`
+	Rewards            []FarmRewardSchema  `json:"rewards,omitempty"`
+	Status             string              `json:"status"`
+}
+
+type FarmListResponse struct {
+	FarmList []FarmInfoSchema `json:"farm_list"`
+}
+
+type FarmNftInfoSchema struct {
+	Address             string `json:"address,omitempty"`
+	CreateTimestamp     string `json:"create_timestamp,omitempty"`
+	MinUnstakeTimestamp string `json:"min_unstake_timestamp,omitempty"`
+	NonclaimedRewards   string `json:"nonclaimed_rewards,omitempty"`
+	Rewards             []struct {
+		Address string `json:"address,omitempty"`
+		Amount  string `json:"amount,omitempty"`
+	} `json:"rewards,omitempty"`
+	StakedTokens string `json:"staked_tokens,omitempty"`
+	Status       string `json:"status,omitempty"`
+}
+
+type FarmResponse struct {
+	Farm FarmInfoSchema `json:"farm"`
+}
+
+type FarmRewardSchema struct {
+	Address          string `json:"address,omitempty"`
+	RemainingRewards string `json:"remaining_rewards,omitempty"`
+	RewardRate24h    string `json:"reward_rate_24h,omitempty"`
+	Status           string `json:"status,omitempty"`
+}
+
+type MarketListResponse struct {
+	Pairs [][]string `json:"pairs"`
+}
+
+type OperationInfoSchema struct {
+	Asset0Info AssetInfoSchema `json:"asset0_info,omitempty"`
+	Asset1Info AssetInfoSchema `json:"asset1_info,omitempty"`
+	Operation  OperationSchema `json:"operation"`
+}
+
+type OperationSchema struct {
+	Asset0Address            string  `json:"asset0_address,omitempty"`
+	Asset0Amount             string  `json:"asset0_amount,omitempty"`
+	Asset0Delta              string  `json:"asset0_delta,omitempty"`
+	Asset0Reserve            string  `json:"asset0_reserve,omitempty"`
+	Asset1Address            string  `json:"asset1_address,omitempty"`
+	Asset1Amount             string  `json:"asset1_amount,omitempty"`
+	Asset1Delta              string  `json:"asset1_delta,omitempty"`
+	Asset1Reserve            string  `json:"asset1_reserve,omitempty"`
+	DestinationWalletAddress *string `json:"destination_wallet_address,omitempty"`
+	ExitCode                 string  `json:"exit_code,omitempty"`
+	FeeAssetAddress          *string `json:"fee_asset_address,omitempty"`
+	LpFeeAmount              string  `json:"lp_fee_amount,omitempty"`
+	LpTokenDelta             string  `json:"lp_token_delta,omitempty"`
+	LpTokenSupply            string  `json:"lp_token_supply,omitempty"`
+	OperationType            string  `json:"operation_type,omitempty"`
+	PoolAddress              string  `json:"pool_address,omitempty"`
+	PoolTxHash               string  `json:"pool_tx_hash,omitempty"`
+	PoolTxLt                 int64   `json:"pool_tx_lt,omitempty"`
+	PoolTxTimestamp          string  `json:"pool_tx_timestamp,omitempty"`
+	ProtocolFeeAmount        string  `json:"protocol_fee_amount,omitempty"`
+	ReferralAddress          *string `json:"referral_address,omitempty"`
+	ReferralFeeAmount        string  `json:"referral_fee_amount,omitempty"`
+	RouterAddress            string  `json:"router_address,omitempty"`
+	Success                  bool    `json:"success,omitempty"`
+	WalletAddress            string  `json:"wallet_address,omitempty"`
+	WalletTxHash             string  `json:"wallet_tx_hash,omitempty"`
+	WalletTxLt               string  `json:"wallet_tx_lt,omitempty"`
+	WalletTxTimestamp        string  `json:"wallet_tx_timestamp,omitempty"`
+}
+
+type OperationsResponse struct {
+	Operations []OperationInfoSchema `json:"operations"`
+}
+
+type PoolInfoSchema struct {
+	Address                    string  `json:"address"`
+	Apy1d                      *string `json:"apy_1d,omitempty"`
+	Apy30d                     *string `json:"apy_30d,omitempty"`
+	Apy7d                      *string `json:"apy_7d,omitempty"`
+	CollectedToken0ProtocolFee string  `json:"collected_token0_protocol_fee,omitempty"`
+	CollectedToken1ProtocolFee string  `json:"collected_token1_protocol_fee,omitempty"`
+	Deprecated                 bool    `json:"deprecated,omitempty"`
+	LpAccountAddress           *string `json:"lp_account_address,omitempty"`
+	LpBalance                  *string `json:"lp_balance,omitempty"`
+	LpFee                      string  `json:"lp_fee,omitempty"`
+	LpPriceUsd                 *string `json:"lp_price_usd,omitempty"`
+	LpTotalSupply              string  `json:"lp_total_supply,omitempty"`
+	LpTotalSupplyUsd           *string `json:"lp_total_supply_usd,omitempty"`
+	LpWalletAddress            *string `json:"lp_wallet_address,omitempty"`
+	ProtocolFee                string  `json:"protocol_fee,omitempty"`
+	ProtocolFeeAddress         string  `json:"protocol_fee_address,omitempty"`
+	RefFee                     string  `json:"ref_fee,omitempty"`
+	Reserve0                   string  `json:"reserve0"`
+	Reserve1                   string  `json:"reserve1"`
+	RouterAddress              string  `json:"router_address"`
+	Token0Address              string  `json:"token0_address"`
+	Token0Balance              *string `json:"token0_balance,omitempty"`
+	Token1Address              string  `json:"token1_address"`
+	Token1Balance              *string `json:"token1_balance,omitempty"`
+}
+
+type PoolListResponse struct {
+	PoolList []PoolInfoSchema `json:"pool_list"`
+}
+
+type PoolResponse struct {
+	Pool PoolInfoSchema `json:"pool"`
+}
+
+type PoolStatsResponse struct {
+	Since              time.Time         `json:"since"`
+	Stats              []PoolStatsSchema `json:"stats"`
+	UniqueWalletsCount int64             `json:"unique_wallets_count,omitempty"`
+	Until              time.Time         `json:"until"`
+}
+
+type PoolStatsSchema struct {
+	Apy            string `json:"apy,omitempty"`
+	BaseID         string `json:"base_id,omitempty"`
+	BaseLiquidity  string `json:"base_liquidity,omitempty"`
+	BaseName       string `json:"base_name,omitempty"`
+	BaseSymbol     string `json:"base_symbol,omitempty"`
+	BaseVolume     string `json:"base_volume,omitempty"`
+	LastPrice      string `json:"last_price,omitempty"`
+	LpPrice        string `json:"lp_price,omitempty"`
+	LpPriceUsd     string `json:"lp_price_usd,omitempty"`
+	PoolAddress    string `json:"pool_address,omitempty"`
+	QuoteID        string `json:"quote_id,omitempty"`
+	QuoteLiquidity string `json:"quote_liquidity,omitempty"`
+	QuoteName      string `json:"quote_name,omitempty"`
+	QuoteSymbol    string `json:"quote_symbol,omitempty"`
+	QuoteVolume    string `json:"quote_volume,omitempty"`
+	RouterAddress  string `json:"router_address,omitempty"`
+	URL            string `json:"url,omitempty"`
+}
+
+type SwapSimulationResponse struct {
+	AskAddress        string `json:"ask_address,omitempty"`
+	AskJettonWallet   string `json:"ask_jetton_wallet,omitempty"`
+	AskUnits          string `json:"ask_units,omitempty"`
+	FeeAddress        string `json:"fee_address,omitempty"`
+	FeePercent        string `json:"fee_percent,omitempty"`
+	FeeUnits          string `json:"fee_units,omitempty"`
+	MinAskUnits       string `json:"min_ask_units,omitempty"`
+	OfferAddress      string `json:"offer_address,omitempty"`
+	OfferJettonWallet string `json:"offer_jetton_wallet,omitempty"`
+	OfferUnits        string `json:"offer_units,omitempty"`
+	PoolAddress       string `json:"pool_address,omitempty"`
+	PriceImpact       string `json:"price_impact,omitempty"`
+	RouterAddress     string `json:"router_address,omitempty"`
+	SlippageTolerance string `json:"slippage_tolerance,omitempty"`
+	SwapRate          string `json:"swap_rate,omitempty"`
+}
+
+type SwapStatusResponse struct {
+	Type          string  `json:"@type"`
+	Address       *string `json:"address,omitempty"`
+	BalanceDeltas *string `json:"balance_deltas,omitempty"`
+	Coins         *string `json:"coins,omitempty"`
+	ExitCode      *string `json:"exit_code,omitempty"`
+	LogicalTime   *string `json:"logical_time,omitempty"`
+	QueryID       *string `json:"query_id,omitempty"`
+	TxHash        *string `json:"tx_hash,omitempty"`
+}
+
+// Operations lists every operation in the specification.
+var Operations = []Operation{
+	opGetAssetList,
+	opQueryAssets,
+	opSearchAssets,
+	opGetAsset,
+	opGetFarmList,
+	opGetFarm,
+	opGetFarmsByPool,
+	opGetMarketList,
+	opGetPoolList,
+	opGetPool,
+	opSimulateReverseSwap,
+	opGetDexStats,
+	opGetOperationStats,
+	opGetPoolStats,
+	opSimulateSwap,
+	opGetSwapStatus,
+	opGetWalletAssetList,
+	opGetWalletAsset,
+	opGetWalletFarmList,
+	opGetWalletFarm,
+	opGetWalletOperations,
+	opGetWalletPoolList,
+	opGetWalletPool,
+}
+
+var (
+	opGetAssetList        = Operation{ID: "get_asset_list", Method: "GET", Path: "/assets", PathParams: []string{}, QueryParams: []Param{}}
+	opQueryAssets         = Operation{ID: "query_assets", Method: "POST", Path: "/assets/query", PathParams: []string{}, QueryParams: []Param{{Name: "condition", Required: true}, {Name: "unconditional_assets", Required: false}, {Name: "wallet_address", Required: false}}}
+	opSearchAssets        = Operation{ID: "search_assets", Method: "POST", Path: "/assets/search", PathParams: []string{}, QueryParams: []Param{{Name: "search_string", Required: true}, {Name: "condition", Required: true}, {Name: "wallet_address", Required: false}}}
+	opGetAsset            = Operation{ID: "get_asset", Method: "GET", Path: "/assets/{addr_str}", PathParams: []string{"addr_str"}, QueryParams: []Param{}}
+	opGetFarmList         = Operation{ID: "get_farm_list", Method: "GET", Path: "/farms", PathParams: []string{}, QueryParams: []Param{{Name: "dex_v2", Required: false}}}
+	opGetFarm             = Operation{ID: "get_farm", Method: "GET", Path: "/farms/{addr_str}", PathParams: []string{"addr_str"}, QueryParams: []Param{}}
+	opGetFarmsByPool      = Operation{ID: "get_farms_by_pool", Method: "GET", Path: "/farms_by_pool/{pool_addr_str}", PathParams: []string{"pool_addr_str"}, QueryParams: []Param{}}
+	opGetMarketList       = Operation{ID: "get_market_list", Method: "GET", Path: "/markets", PathParams: []string{}, QueryParams: []Param{}}
+	opGetPoolList         = Operation{ID: "get_pool_list", Method: "GET", Path: "/pools", PathParams: []string{}, QueryParams: []Param{{Name: "dex_v2", Required: false}}}
+	opGetPool             = Operation{ID: "get_pool", Method: "GET", Path: "/pools/{addr_str}", PathParams: []string{"addr_str"}, QueryParams: []Param{}}
+	opSimulateReverseSwap = Operation{ID: "simulate_reverse_swap", Method: "POST", Path: "/reverse_swap/simulate", PathParams: []string{}, QueryParams: []Param{{Name: "offer_address", Required: true}, {Name: "ask_address", Required: true}, {Name: "units", Required: true}, {Name: "slippage_tolerance", Required: true}, {Name: "referral_address", Required: false}}}
+	opGetDexStats         = Operation{ID: "get_dex_stats", Method: "GET", Path: "/stats/dex", PathParams: []string{}, QueryParams: []Param{{Name: "since", Required: true}, {Name: "until", Required: true}}}
+	opGetOperationStats   = Operation{ID: "get_operation_stats", Method: "GET", Path: "/stats/operations", PathParams: []string{}, QueryParams: []Param{{Name: "since", Required: true}, {Name: "until", Required: true}}}
+	opGetPoolStats        = Operation{ID: "get_pool_stats", Method: "GET", Path: "/stats/pool", PathParams: []string{}, QueryParams: []Param{{Name: "since", Required: true}, {Name: "until", Required: true}}}
+	opSimulateSwap        = Operation{ID: "simulate_swap", Method: "POST", Path: "/swap/simulate", PathParams: []string{}, QueryParams: []Param{{Name: "offer_address", Required: true}, {Name: "ask_address", Required: true}, {Name: "units", Required: true}, {Name: "slippage_tolerance", Required: true}, {Name: "referral_address", Required: false}}}
+	opGetSwapStatus       = Operation{ID: "get_swap_status", Method: "GET", Path: "/swap/status", PathParams: []string{}, QueryParams: []Param{{Name: "router_address", Required: true}, {Name: "owner_address", Required: true}, {Name: "query_id", Required: true}, {Name: "ext_status", Required: false}}}
+	opGetWalletAssetList  = Operation{ID: "get_wallet_asset_list", Method: "GET", Path: "/wallets/{addr_str}/assets", PathParams: []string{"addr_str"}, QueryParams: []Param{}}
+	opGetWalletAsset      = Operation{ID: "get_wallet_asset", Method: "GET", Path: "/wallets/{addr_str}/assets/{asset_address}", PathParams: []string{"addr_str", "asset_address"}, QueryParams: []Param{}}
+	opGetWalletFarmList   = Operation{ID: "get_wallet_farm_list", Method: "GET", Path: "/wallets/{addr_str}/farms", PathParams: []string{"addr_str"}, QueryParams: []Param{}}
+	opGetWalletFarm       = Operation{ID: "get_wallet_farm", Method: "GET", Path: "/wallets/{addr_str}/farms/{farm_address}", PathParams: []string{"addr_str", "farm_address"}, QueryParams: []Param{}}
+	opGetWalletOperations = Operation{ID: "get_wallet_operations", Method: "GET", Path: "/wallets/{addr_str}/operations", PathParams: []string{"addr_str"}, QueryParams: []Param{{Name: "since", Required: false}, {Name: "until", Required: false}, {Name: "op_type", Required: false}}}
+	opGetWalletPoolList   = Operation{ID: "get_wallet_pool_list", Method: "GET", Path: "/wallets/{addr_str}/pools", PathParams: []string{"addr_str"}, QueryParams: []Param{}}
+	opGetWalletPool       = Operation{ID: "get_wallet_pool", Method: "GET", Path: "/wallets/{addr_str}/pools/{pool_address}", PathParams: []string{"addr_str", "pool_address"}, QueryParams: []Param{}}
+)
+
+// GetAssetList calls GET /v1/assets (List all DEX assets).
+func (c *Client) GetAssetList(ctx context.Context) (*AssetListResponse, error) {
+	values := url.Values{}
+	var response AssetListResponse
+	if err := c.Caller.CallOperation(ctx, opGetAssetList, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// QueryAssetsParams are the query parameters of QueryAssets.
+type QueryAssetsParams struct {
+	Condition           string
+	UnconditionalAssets []string
+	WalletAddress       string
+}
+
+// QueryAssets calls POST /v1/assets/query (Query assets by condition).
+func (c *Client) QueryAssets(ctx context.Context, params QueryAssetsParams) (*AssetListResponse, error) {
+	values := url.Values{}
+	if params.Condition != "" {
+		values.Set("condition", params.Condition)
+	}
+	for _, v := range params.UnconditionalAssets {
+		values.Add("unconditional_assets", v)
+	}
+	if params.WalletAddress != "" {
+		values.Set("wallet_address", params.WalletAddress)
+	}
+	var response AssetListResponse
+	if err := c.Caller.CallOperation(ctx, opQueryAssets, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SearchAssetsParams are the query parameters of SearchAssets.
+type SearchAssetsParams struct {
+	SearchString  string
+	Condition     string
+	WalletAddress string
+}
+
+// SearchAssets calls POST /v1/assets/search (Search assets).
+func (c *Client) SearchAssets(ctx context.Context, params SearchAssetsParams) (*AssetListResponse, error) {
+	values := url.Values{}
+	if params.SearchString != "" {
+		values.Set("search_string", params.SearchString)
+	}
+	if params.Condition != "" {
+		values.Set("condition", params.Condition)
+	}
+	if params.WalletAddress != "" {
+		values.Set("wallet_address", params.WalletAddress)
+	}
+	var response AssetListResponse
+	if err := c.Caller.CallOperation(ctx, opSearchAssets, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetAsset calls GET /v1/assets/{addr_str} (Get asset details).
+func (c *Client) GetAsset(ctx context.Context, addrStr string) (*AssetResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	var response AssetResponse
+	if err := c.Caller.CallOperation(ctx, opGetAsset, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetFarmListParams are the query parameters of GetFarmList.
+type GetFarmListParams struct {
+	DexV2 bool
+}
+
+// GetFarmList calls GET /v1/farms (List all farms).
+func (c *Client) GetFarmList(ctx context.Context, params GetFarmListParams) (*FarmListResponse, error) {
+	values := url.Values{}
+	if params.DexV2 {
+		values.Set("dex_v2", strconv.FormatBool(params.DexV2))
+	}
+	var response FarmListResponse
+	if err := c.Caller.CallOperation(ctx, opGetFarmList, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetFarm calls GET /v1/farms/{addr_str} (Get farm details).
+func (c *Client) GetFarm(ctx context.Context, addrStr string) (*FarmResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	var response FarmResponse
+	if err := c.Caller.CallOperation(ctx, opGetFarm, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetFarmsByPool calls GET /v1/farms_by_pool/{pool_addr_str} (List farms of a pool).
+func (c *Client) GetFarmsByPool(ctx context.Context, poolAddrStr string) (*FarmListResponse, error) {
+	values := url.Values{}
+	values.Set("pool_addr_str", poolAddrStr)
+	var response FarmListResponse
+	if err := c.Caller.CallOperation(ctx, opGetFarmsByPool, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetMarketList calls GET /v1/markets (List trading pairs).
+func (c *Client) GetMarketList(ctx context.Context) (*MarketListResponse, error) {
+	values := url.Values{}
+	var response MarketListResponse
+	if err := c.Caller.CallOperation(ctx, opGetMarketList, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetPoolListParams are the query parameters of GetPoolList.
+type GetPoolListParams struct {
+	DexV2 bool
+}
+
+// GetPoolList calls GET /v1/pools (List all pools).
+func (c *Client) GetPoolList(ctx context.Context, params GetPoolListParams) (*PoolListResponse, error) {
+	values := url.Values{}
+	if params.DexV2 {
+		values.Set("dex_v2", strconv.FormatBool(params.DexV2))
+	}
+	var response PoolListResponse
+	if err := c.Caller.CallOperation(ctx, opGetPoolList, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetPool calls GET /v1/pools/{addr_str} (Get pool details).
+func (c *Client) GetPool(ctx context.Context, addrStr string) (*PoolResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	var response PoolResponse
+	if err := c.Caller.CallOperation(ctx, opGetPool, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SimulateReverseSwapParams are the query parameters of SimulateReverseSwap.
+type SimulateReverseSwapParams struct {
+	OfferAddress      string
+	AskAddress        string
+	Units             string
+	SlippageTolerance string
+	ReferralAddress   string
+}
+
+// SimulateReverseSwap calls POST /v1/reverse_swap/simulate (Simulate a reverse swap).
+func (c *Client) SimulateReverseSwap(ctx context.Context, params SimulateReverseSwapParams) (*SwapSimulationResponse, error) {
+	values := url.Values{}
+	if params.OfferAddress != "" {
+		values.Set("offer_address", params.OfferAddress)
+	}
+	if params.AskAddress != "" {
+		values.Set("ask_address", params.AskAddress)
+	}
+	if params.Units != "" {
+		values.Set("units", params.Units)
+	}
+	if params.SlippageTolerance != "" {
+		values.Set("slippage_tolerance", params.SlippageTolerance)
+	}
+	if params.ReferralAddress != "" {
+		values.Set("referral_address", params.ReferralAddress)
+	}
+	var response SwapSimulationResponse
+	if err := c.Caller.CallOperation(ctx, opSimulateReverseSwap, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetDexStatsParams are the query parameters of GetDexStats.
+type GetDexStatsParams struct {
+	Since time.Time
+	Until time.Time
+}
+
+// GetDexStats calls GET /v1/stats/dex (DEX statistics for a time range).
+func (c *Client) GetDexStats(ctx context.Context, params GetDexStatsParams) (*DexStatsResponse, error) {
+	values := url.Values{}
+	if !params.Since.IsZero() {
+		values.Set("since", params.Since.UTC().Format(TimeLayout))
+	}
+	if !params.Until.IsZero() {
+		values.Set("until", params.Until.UTC().Format(TimeLayout))
+	}
+	var response DexStatsResponse
+	if err := c.Caller.CallOperation(ctx, opGetDexStats, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetOperationStatsParams are the query parameters of GetOperationStats.
+type GetOperationStatsParams struct {
+	Since time.Time
+	Until time.Time
+}
+
+// GetOperationStats calls GET /v1/stats/operations (Operations in a time range).
+func (c *Client) GetOperationStats(ctx context.Context, params GetOperationStatsParams) (*OperationsResponse, error) {
+	values := url.Values{}
+	if !params.Since.IsZero() {
+		values.Set("since", params.Since.UTC().Format(TimeLayout))
+	}
+	if !params.Until.IsZero() {
+		values.Set("until", params.Until.UTC().Format(TimeLayout))
+	}
+	var response OperationsResponse
+	if err := c.Caller.CallOperation(ctx, opGetOperationStats, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetPoolStatsParams are the query parameters of GetPoolStats.
+type GetPoolStatsParams struct {
+	Since time.Time
+	Until time.Time
+}
+
+// GetPoolStats calls GET /v1/stats/pool (Pool statistics for a time range).
+func (c *Client) GetPoolStats(ctx context.Context, params GetPoolStatsParams) (*PoolStatsResponse, error) {
+	values := url.Values{}
+	if !params.Since.IsZero() {
+		values.Set("since", params.Since.UTC().Format(TimeLayout))
+	}
+	if !params.Until.IsZero() {
+		values.Set("until", params.Until.UTC().Format(TimeLayout))
+	}
+	var response PoolStatsResponse
+	if err := c.Caller.CallOperation(ctx, opGetPoolStats, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SimulateSwapParams are the query parameters of SimulateSwap.
+type SimulateSwapParams struct {
+	OfferAddress      string
+	AskAddress        string
+	Units             string
+	SlippageTolerance string
+	ReferralAddress   string
+}
+
+// SimulateSwap calls POST /v1/swap/simulate (Simulate a swap).
+func (c *Client) SimulateSwap(ctx context.Context, params SimulateSwapParams) (*SwapSimulationResponse, error) {
+	values := url.Values{}
+	if params.OfferAddress != "" {
+		values.Set("offer_address", params.OfferAddress)
+	}
+	if params.AskAddress != "" {
+		values.Set("ask_address", params.AskAddress)
+	}
+	if params.Units != "" {
+		values.Set("units", params.Units)
+	}
+	if params.SlippageTolerance != "" {
+		values.Set("slippage_tolerance", params.SlippageTolerance)
+	}
+	if params.ReferralAddress != "" {
+		values.Set("referral_address", params.ReferralAddress)
+	}
+	var response SwapSimulationResponse
+	if err := c.Caller.CallOperation(ctx, opSimulateSwap, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetSwapStatusParams are the query parameters of GetSwapStatus.
+type GetSwapStatusParams struct {
+	RouterAddress string
+	OwnerAddress  string
+	QueryID       string
+	ExtStatus     string
+}
+
+// GetSwapStatus calls GET /v1/swap/status (Get the status of a swap).
+func (c *Client) GetSwapStatus(ctx context.Context, params GetSwapStatusParams) (*SwapStatusResponse, error) {
+	values := url.Values{}
+	if params.RouterAddress != "" {
+		values.Set("router_address", params.RouterAddress)
+	}
+	if params.OwnerAddress != "" {
+		values.Set("owner_address", params.OwnerAddress)
+	}
+	if params.QueryID != "" {
+		values.Set("query_id", params.QueryID)
+	}
+	if params.ExtStatus != "" {
+		values.Set("ext_status", params.ExtStatus)
+	}
+	var response SwapStatusResponse
+	if err := c.Caller.CallOperation(ctx, opGetSwapStatus, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWalletAssetList calls GET /v1/wallets/{addr_str}/assets (List a wallet's assets).
+func (c *Client) GetWalletAssetList(ctx context.Context, addrStr string) (*AssetListResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	var response AssetListResponse
+	if err := c.Caller.CallOperation(ctx, opGetWalletAssetList, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWalletAsset calls GET /v1/wallets/{addr_str}/assets/{asset_address} (Get a wallet's asset).
+func (c *Client) GetWalletAsset(ctx context.Context, addrStr string, assetAddress string) (*AssetResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	values.Set("asset_address", assetAddress)
+	var response AssetResponse
+	if err := c.Caller.CallOperation(ctx, opGetWalletAsset, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWalletFarmList calls GET /v1/wallets/{addr_str}/farms (List a wallet's farms).
+func (c *Client) GetWalletFarmList(ctx context.Context, addrStr string) (*FarmListResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	var response FarmListResponse
+	if err := c.Caller.CallOperation(ctx, opGetWalletFarmList, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWalletFarm calls GET /v1/wallets/{addr_str}/farms/{farm_address} (Get a wallet's farm).
+func (c *Client) GetWalletFarm(ctx context.Context, addrStr string, farmAddress string) (*FarmResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	values.Set("farm_address", farmAddress)
+	var response FarmResponse
+	if err := c.Caller.CallOperation(ctx, opGetWalletFarm, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWalletOperationsParams are the query parameters of GetWalletOperations.
+type GetWalletOperationsParams struct {
+	Since  time.Time
+	Until  time.Time
+	OpType string
+}
+
+// GetWalletOperations calls GET /v1/wallets/{addr_str}/operations (List a wallet's operations).
+func (c *Client) GetWalletOperations(ctx context.Context, addrStr string, params GetWalletOperationsParams) (*OperationsResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	if !params.Since.IsZero() {
+		values.Set("since", params.Since.UTC().Format(TimeLayout))
+	}
+	if !params.Until.IsZero() {
+		values.Set("until", params.Until.UTC().Format(TimeLayout))
+	}
+	if params.OpType != "" {
+		values.Set("op_type", params.OpType)
+	}
+	var response OperationsResponse
+	if err := c.Caller.CallOperation(ctx, opGetWalletOperations, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWalletPoolList calls GET /v1/wallets/{addr_str}/pools (List a wallet's pools).
+func (c *Client) GetWalletPoolList(ctx context.Context, addrStr string) (*PoolListResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	var response PoolListResponse
+	if err := c.Caller.CallOperation(ctx, opGetWalletPoolList, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWalletPool calls GET /v1/wallets/{addr_str}/pools/{pool_address} (Get a wallet's pool).
+func (c *Client) GetWalletPool(ctx context.Context, addrStr string, poolAddress string) (*PoolResponse, error) {
+	values := url.Values{}
+	values.Set("addr_str", addrStr)
+	values.Set("pool_address", poolAddress)
+	var response PoolResponse
+	if err := c.Caller.CallOperation(ctx, opGetWalletPool, values, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
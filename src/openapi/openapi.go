@@ -0,0 +1,66 @@
+// Package openapi is generated from a vendored copy of the Ston.fi OpenAPI
+// specification (openapi.json). Regenerate it with `go generate` after
+// updating the spec.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"net/url"
+	"time"
+)
+
+//go:generate go run ./gen -spec openapi.json -prefix /v1 -out openapi.gen.go
+
+// Spec is the vendored specification.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// TimeLayout is the format the API expects for date-time query parameters, in UTC.
+const TimeLayout = "2006-01-02T15:04:05"
+
+// ParseTime parses a timestamp of the API, in TimeLayout or RFC 3339.
+func ParseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(TimeLayout, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// Param is a query parameter accepted by an operation.
+type Param struct {
+	Name     string
+	Required bool
+}
+
+// Operation describes a route from the specification. Path is relative to the
+// API base URL and contains `{param}` placeholders for PathParams.
+type Operation struct {
+	ID          string
+	Method      string
+	Path        string
+	PathParams  []string
+	QueryParams []Param
+}
+
+// Caller sends a request for op, taking both path and query parameters from
+// params, and decodes the JSON response into response.
+type Caller interface {
+	CallOperation(ctx context.Context, op Operation, params url.Values, response interface{}) error
+}
+
+// Client exposes one method per operation in the specification.
+type Client struct {
+	Caller Caller
+}
+
+// Lookup returns the operation for method and path, if the specification has one.
+func Lookup(method, path string) (Operation, bool) {
+	for _, op := range Operations {
+		if op.Method == method && op.Path == path {
+			return op, true
+		}
+	}
+	return Operation{}, false
+}
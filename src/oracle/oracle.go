@@ -0,0 +1,386 @@
+// Package oracle derives manipulation-resistant USD prices of assets from
+// swaps and reserve snapshots, rather than trusting the point-in-time
+// `dex_price_usd` the API reports, which a single trade can move.
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/export"
+	"github.com/itay747/go-stonfi/src/indexer"
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/snapshot"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// ErrNoSamples is returned by Price when there is no sample to derive a
+// price from. The Price returned with it still has the API's prices.
+var ErrNoSamples = errors.New("oracle: no price samples")
+
+// OperationSource yields the operations involving asset between from and to,
+// ordered by time.
+type OperationSource func(ctx context.Context, asset string, from, to time.Time) iter.Seq2[types.OperationInfo, error]
+
+// APIOperations reads operations from `/v1/stats/operations`. Every
+// operation in the window is downloaded, so prefer IndexedOperations for
+// long windows.
+func APIOperations(c *client.StonfiClient) OperationSource {
+	return func(ctx context.Context, asset string, from, to time.Time) iter.Seq2[types.OperationInfo, error] {
+		key := utils.AddressKey(asset)
+		return func(yield func(types.OperationInfo, error) bool) {
+			for info, err := range export.Operations(ctx, c, from, to) {
+				if err != nil {
+					yield(info, err)
+					return
+				}
+				op := info.Operation
+				if utils.AddressKey(op.Asset0Address) != key && utils.AddressKey(op.Asset1Address) != key {
+					continue
+				}
+				if !yield(info, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// IndexedOperations reads operations from an indexer.
+func IndexedOperations(ix *indexer.Indexer) OperationSource {
+	return func(ctx context.Context, asset string, from, to time.Time) iter.Seq2[types.OperationInfo, error] {
+		return ix.Operations(ctx, indexer.Filter{Asset: asset, OperationType: "swap", Since: from, Until: to})
+	}
+}
+
+// Options configures an Oracle.
+type Options struct {
+	// Operations provides the swaps to sample. Without it, only reserve
+	// snapshots are sampled.
+	Operations OperationSource
+	// Snapshots provides reserve snapshots, and the prices of the other
+	// asset of each pool at the time of each sample. Without it, the
+	// current prices of the other assets are used.
+	Snapshots *snapshot.Store
+	// Window is how far back samples are taken. Defaults to 1 hour.
+	Window time.Duration
+	// OutlierThreshold is how many median absolute deviations from the
+	// median a sample may be before it is rejected. Defaults to 5.
+	OutlierThreshold float64
+	// MaxStaleness is the age of the latest sample beyond which a price is
+	// stale. Defaults to Window.
+	MaxStaleness time.Duration
+	// MaxThirdPartyDeviation is the relative difference from the third
+	// party price beyond which a price is flagged. Defaults to 0.05.
+	MaxThirdPartyDeviation float64
+}
+
+// Oracle computes prices of assets.
+type Oracle struct {
+	client *client.StonfiClient
+	opts   Options
+	now    func() time.Time
+}
+
+// New returns an Oracle.
+func New(c *client.StonfiClient, opts Options) *Oracle {
+	if opts.Window <= 0 {
+		opts.Window = time.Hour
+	}
+	if opts.OutlierThreshold <= 0 {
+		opts.OutlierThreshold = 5
+	}
+	if opts.MaxStaleness <= 0 {
+		opts.MaxStaleness = opts.Window
+	}
+	if opts.MaxThirdPartyDeviation <= 0 {
+		opts.MaxThirdPartyDeviation = 0.05
+	}
+	return &Oracle{client: c, opts: opts, now: time.Now}
+}
+
+// Price is the price of an asset with the metadata needed to judge it.
+type Price struct {
+	Asset string
+	// PriceUsd is the VWAP, or the TWAP when no swap was sampled.
+	PriceUsd float64
+	// TWAP is the time-weighted average of the reserve samples, or of the
+	// swap samples when there are none; the two kinds are not averaged
+	// together, as swaps cluster in time where reserves are sampled evenly.
+	TWAP float64
+	VWAP float64
+	From time.Time
+	To   time.Time
+
+	// DexPriceUsd and ThirdPartyPriceUsd are what the API reports now.
+	DexPriceUsd        float64
+	ThirdPartyPriceUsd float64
+	// ThirdPartyDeviation is PriceUsd relative to ThirdPartyPriceUsd, minus
+	// one, or 0 without a third party price.
+	ThirdPartyDeviation float64
+	// ThirdPartyMismatch is set when ThirdPartyDeviation exceeds
+	// Options.MaxThirdPartyDeviation.
+	ThirdPartyMismatch bool
+
+	// Samples is the number of samples used, Rejected the number rejected
+	// as outliers.
+	Samples  int
+	Rejected int
+	// Deviation is the root mean square deviation of the samples used from
+	// PriceUsd, relative to PriceUsd.
+	Deviation  float64
+	LastSample time.Time
+	// Staleness is the age of the latest sample; Stale is set when it
+	// exceeds Options.MaxStaleness.
+	Staleness time.Duration
+	Stale     bool
+	// LowConfidence is set when a sample used was priced with the current
+	// price of the other asset of its pool, for want of a snapshot of it at
+	// the time of the sample.
+	LowConfidence bool
+}
+
+// Price returns the price of asset over the configured window.
+func (o *Oracle) Price(ctx context.Context, asset string) (Price, error) {
+	return o.PriceWindow(ctx, asset, o.opts.Window)
+}
+
+// PriceWindow returns the price of asset over the window ending now.
+func (o *Oracle) PriceWindow(ctx context.Context, asset string, window time.Duration) (Price, error) {
+	to := o.now().UTC().Truncate(time.Second)
+	from := to.Add(-window)
+	target, err := o.client.GetAsset(ctx, asset)
+	if err != nil {
+		return Price{}, fmt.Errorf("oracle: fetching asset %s: %w", asset, err)
+	}
+	if target.Asset.ContractAddress == "" {
+		target.Asset.ContractAddress = asset
+	}
+	price := Price{
+		Asset:              asset,
+		From:               from,
+		To:                 to,
+		DexPriceUsd:        utils.ParseFloat(target.Asset.DexPriceUsd),
+		ThirdPartyPriceUsd: utils.ParseFloat(target.Asset.ThirdPartyPriceUsd),
+	}
+
+	s := &sampler{oracle: o, target: target.Asset, assets: map[string]types.Asset{utils.AddressKey(asset): target.Asset}}
+	samples, err := s.samples(ctx, from, to)
+	if err != nil {
+		return price, err
+	}
+	kept, rejected := RejectOutliers(samples, o.opts.OutlierThreshold)
+	price.Samples, price.Rejected = len(kept), len(rejected)
+	if len(kept) == 0 {
+		return price, fmt.Errorf("%w for %s between %s and %s", ErrNoSamples, asset, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	reserves, swaps := bySource(kept, SourceReserves), bySource(kept, SourceSwap)
+	if len(reserves) > 0 {
+		price.TWAP = TWAP(reserves, from, to)
+	} else {
+		price.TWAP = TWAP(swaps, from, to)
+	}
+	price.VWAP = VWAP(swaps)
+	price.PriceUsd = price.VWAP
+	if price.PriceUsd == 0 {
+		price.PriceUsd = price.TWAP
+	}
+	price.Deviation = deviation(kept, price.PriceUsd)
+	price.LastSample = kept[len(kept)-1].Time
+	price.Staleness = to.Sub(price.LastSample)
+	price.Stale = price.Staleness > o.opts.MaxStaleness
+	for _, sample := range kept {
+		price.LowConfidence = price.LowConfidence || sample.CurrentPrice
+	}
+	if price.ThirdPartyPriceUsd > 0 {
+		price.ThirdPartyDeviation = price.PriceUsd/price.ThirdPartyPriceUsd - 1
+		price.ThirdPartyMismatch = math.Abs(price.ThirdPartyDeviation) > o.opts.MaxThirdPartyDeviation
+	}
+	return price, nil
+}
+
+// sampler collects the samples of one price.
+type sampler struct {
+	oracle *Oracle
+	target types.Asset
+	// assets caches the assets looked up, by raw address.
+	assets map[string]types.Asset
+}
+
+// samples returns the samples between from and to, sorted by time.
+func (s *sampler) samples(ctx context.Context, from, to time.Time) ([]Sample, error) {
+	var samples []Sample
+	if source := s.oracle.opts.Operations; source != nil {
+		for info, err := range source(ctx, s.target.ContractAddress, from, to) {
+			if err != nil {
+				return nil, fmt.Errorf("oracle: reading operations: %w", err)
+			}
+			sample, ok, err := s.swapSample(ctx, info)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				samples = append(samples, sample)
+			}
+		}
+	}
+	if s.oracle.opts.Snapshots != nil {
+		reserves, err := s.reserveSamples(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, reserves...)
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+	return samples, nil
+}
+
+// swapSample prices the target from a successful swap against another asset.
+func (s *sampler) swapSample(ctx context.Context, info types.OperationInfo) (Sample, bool, error) {
+	op := info.Operation
+	if op.OperationType != "swap" || !op.Success {
+		return Sample{}, false, nil
+	}
+	t, err := openapi.ParseTime(op.PoolTxTimestamp)
+	if err != nil {
+		return Sample{}, false, fmt.Errorf("oracle: operation %s: invalid timestamp %q", op.PoolTxHash, op.PoolTxTimestamp)
+	}
+	targetDelta, otherDelta := op.Asset0Delta, op.Asset1Delta
+	targetInfo, otherInfo, other := info.Asset0Info, info.Asset1Info, op.Asset1Address
+	switch utils.AddressKey(s.target.ContractAddress) {
+	case utils.AddressKey(op.Asset0Address):
+	case utils.AddressKey(op.Asset1Address):
+		targetDelta, otherDelta = otherDelta, targetDelta
+		targetInfo, otherInfo, other = otherInfo, targetInfo, op.Asset0Address
+	default:
+		return Sample{}, false, nil
+	}
+	amount, ok := units(targetDelta, decimalsOr(targetInfo, s.target.Decimals))
+	if !ok || amount == 0 {
+		return Sample{}, false, nil
+	}
+	otherAsset, err := s.asset(ctx, other)
+	if err != nil {
+		return Sample{}, false, err
+	}
+	otherAmount, ok := units(otherDelta, decimalsOr(otherInfo, otherAsset.Decimals))
+	if !ok || otherAmount == 0 {
+		return Sample{}, false, nil
+	}
+	otherUsd, current, err := s.priceAt(ctx, otherAsset, t)
+	if err != nil || otherUsd == 0 {
+		return Sample{}, false, err
+	}
+	price := otherAmount / amount * otherUsd
+	return Sample{Time: t, PriceUsd: price, VolumeUsd: amount * price, Source: SourceSwap, Pool: op.PoolAddress, CurrentPrice: current}, true, nil
+}
+
+// reserveSamples prices the target from the reserves of every pool it is in.
+func (s *sampler) reserveSamples(ctx context.Context, from, to time.Time) ([]Sample, error) {
+	pools, err := s.oracle.client.GetPools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("oracle: fetching pools: %w", err)
+	}
+	key := utils.AddressKey(s.target.ContractAddress)
+	var samples []Sample
+	for _, pool := range pools.PoolList {
+		if pool.Deprecated {
+			continue
+		}
+		var other string
+		targetIs0 := utils.AddressKey(pool.Token0Address) == key
+		switch {
+		case targetIs0:
+			other = pool.Token1Address
+		case utils.AddressKey(pool.Token1Address) == key:
+			other = pool.Token0Address
+		default:
+			continue
+		}
+		otherAsset, err := s.asset(ctx, other)
+		if err != nil {
+			return nil, err
+		}
+		points, err := s.oracle.opts.Snapshots.PoolHistory(ctx, pool.Address, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("oracle: %w", err)
+		}
+		for _, p := range points {
+			if p.Deprecated || p.Reserve0 == nil || p.Reserve1 == nil || p.Reserve0.Sign() == 0 || p.Reserve1.Sign() == 0 {
+				continue
+			}
+			reserve, otherReserve := p.Reserve0, p.Reserve1
+			if !targetIs0 {
+				reserve, otherReserve = otherReserve, reserve
+			}
+			otherUsd, current, err := s.priceAt(ctx, otherAsset, p.Time)
+			if err != nil || otherUsd == 0 {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			ratio := utils.ToUnits(otherReserve, otherAsset.Decimals) / utils.ToUnits(reserve, s.target.Decimals)
+			samples = append(samples, Sample{Time: p.Time, PriceUsd: ratio * otherUsd, Source: SourceReserves, Pool: pool.Address, CurrentPrice: current})
+		}
+	}
+	return samples, nil
+}
+
+// asset returns the asset at address, fetching it once.
+func (s *sampler) asset(ctx context.Context, address string) (types.Asset, error) {
+	key := utils.AddressKey(address)
+	if a, ok := s.assets[key]; ok {
+		return a, nil
+	}
+	response, err := s.oracle.client.GetAsset(ctx, address)
+	if err != nil {
+		return types.Asset{}, fmt.Errorf("oracle: fetching asset %s: %w", address, err)
+	}
+	s.assets[key] = response.Asset
+	return response.Asset, nil
+}
+
+// priceAt returns the USD price of asset at t: from the latest snapshot if
+// there is a store and it has one, else the price now. It reports whether
+// the price is the current one.
+func (s *sampler) priceAt(ctx context.Context, asset types.Asset, t time.Time) (float64, bool, error) {
+	if store := s.oracle.opts.Snapshots; store != nil {
+		price, err := store.AssetPriceAt(ctx, asset.ContractAddress, t)
+		if err == nil {
+			return price.DexPriceUsd, false, nil
+		}
+		if !errors.Is(err, snapshot.ErrNoData) {
+			return 0, false, fmt.Errorf("oracle: %w", err)
+		}
+	}
+	return utils.ParseFloat(asset.DexPriceUsd), true, nil
+}
+
+// units returns the absolute value of a signed amount in smallest units as
+// whole tokens.
+func units(s string, decimals int) (float64, bool) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return 0, false
+	}
+	return math.Abs(utils.ToUnits(n, decimals)), true
+}
+
+// decimalsOr returns the decimals of an operation's asset info, which is
+// empty in some responses, or fallback.
+func decimalsOr(info types.Asset, fallback int) int {
+	if info.ContractAddress == "" && info.Decimals == 0 {
+		return fallback
+	}
+	return info.Decimals
+}
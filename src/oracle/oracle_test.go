@@ -0,0 +1,139 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/snapshot"
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+)
+
+var (
+	ton  = types.Asset{ContractAddress: tonAddress, Symbol: "TON", Decimals: 9, DexPriceUsd: "9", ThirdPartyPriceUsd: "5.5"}
+	usdt = types.Asset{ContractAddress: usdtAddress, Symbol: "USDT", Decimals: 6, DexPriceUsd: "1"}
+)
+
+func swap(timestamp, tonDelta, usdtDelta string) types.OperationInfo {
+	return types.OperationInfo{
+		Operation: types.Operation{
+			PoolTxHash:      timestamp,
+			PoolTxTimestamp: timestamp,
+			PoolAddress:     poolAddress,
+			Asset0Address:   usdtAddress,
+			Asset1Address:   tonAddress,
+			Asset0Delta:     usdtDelta,
+			Asset1Delta:     tonDelta,
+			OperationType:   "swap",
+			Success:         true,
+		},
+		Asset0Info: usdt,
+		Asset1Info: ton,
+	}
+}
+
+func TestPriceFromSwaps(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{
+		Assets: []types.Asset{ton, usdt},
+		Operations: types.Operations{
+			swap("2024-01-01T00:10:00", "1000000000", "-5000000"),  // 5
+			swap("2024-01-01T00:30:00", "-2000000000", "10200000"), // 5.1
+			swap("2024-01-01T00:40:00", "1000000000", "-50000000"), // 50, manipulated
+			swap("2024-01-01T00:50:00", "1000000000", "-4900000"),  // 4.9
+		},
+	})
+	defer s.Close()
+
+	o := New(s.Client(), Options{Operations: APIOperations(s.Client())})
+	o.now = func() time.Time { return t0.Add(time.Hour) }
+	price, err := o.Price(context.Background(), tonAddress)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 3, price.Samples)
+	assert.Equal(t, 1, price.Rejected)
+	assert.InDelta(t, 101.03/20.1, price.VWAP, 1e-9)
+	assert.InDelta(t, 5.02, price.TWAP, 1e-9)
+	assert.Equal(t, price.VWAP, price.PriceUsd)
+	assert.Equal(t, 9.0, price.DexPriceUsd, "the API's price is reported but not used")
+	assert.InDelta(t, price.PriceUsd/5.5-1, price.ThirdPartyDeviation, 1e-9)
+	assert.True(t, price.ThirdPartyMismatch)
+	assert.Equal(t, 10*time.Minute, price.Staleness)
+	assert.False(t, price.Stale)
+	assert.True(t, price.LowConfidence, "without snapshots, USDT is priced at its current price")
+	assert.Greater(t, price.Deviation, 0.0)
+	assert.Less(t, price.Deviation, 0.05)
+
+	o.opts.MaxStaleness = 5 * time.Minute
+	price, err = o.Price(context.Background(), tonAddress)
+	assert.NoError(t, err)
+	assert.True(t, price.Stale)
+}
+
+func TestPriceFromReserves(t *testing.T) {
+	ctx := context.Background()
+	pool := types.Pool{Address: poolAddress, Token0Address: usdtAddress, Token1Address: tonAddress}
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Assets: []types.Asset{ton, usdt}, Pools: []types.Pool{pool}})
+	defer s.Close()
+
+	store, err := snapshot.Open(":memory:")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+	for i, reserveTon := range []string{"1000000000000", "1250000000000"} {
+		pool.Reserve0, pool.Reserve1 = "5000000000", reserveTon // 5, then 4
+		_, err := store.Save(ctx, snapshot.Snapshot{
+			TakenAt: t0.Add(time.Duration(15+30*i) * time.Minute),
+			Pools:   []types.Pool{pool},
+			Assets:  []types.Asset{usdt},
+		})
+		assert.NoError(t, err)
+	}
+
+	o := New(s.Client(), Options{Snapshots: store})
+	o.now = func() time.Time { return t0.Add(time.Hour) }
+	price, err := o.Price(ctx, tonAddress)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 2, price.Samples)
+	assert.Zero(t, price.VWAP, "reserves carry no volume")
+	assert.InDelta(t, (5*30+4*15)/45.0, price.TWAP, 1e-9)
+	assert.Equal(t, price.TWAP, price.PriceUsd)
+	assert.Equal(t, t0.Add(45*time.Minute), price.LastSample)
+	assert.False(t, price.LowConfidence)
+
+	// Swaps are not averaged into the TWAP of the reserves.
+	s.AddOperation(swap("2024-01-01T00:50:00", "1000000000", "-4400000"))
+	o.opts.Operations = APIOperations(s.Client())
+	price, err = o.Price(ctx, tonAddress)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 3, price.Samples)
+	assert.InDelta(t, (5*30+4*15)/45.0, price.TWAP, 1e-9)
+	assert.InDelta(t, 4.4, price.VWAP, 1e-9)
+	assert.Equal(t, price.VWAP, price.PriceUsd)
+	assert.False(t, price.LowConfidence)
+}
+
+func TestPriceWithoutSamples(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Assets: []types.Asset{ton, usdt}})
+	defer s.Close()
+
+	o := New(s.Client(), Options{Operations: APIOperations(s.Client())})
+	o.now = func() time.Time { return t0.Add(time.Hour) }
+	price, err := o.Price(context.Background(), tonAddress)
+	assert.ErrorIs(t, err, ErrNoSamples)
+	assert.Equal(t, 9.0, price.DexPriceUsd)
+	assert.Zero(t, price.Samples)
+}
@@ -0,0 +1,149 @@
+package oracle
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Source is where a sample comes from.
+type Source string
+
+const (
+	SourceSwap     Source = "swap"
+	SourceReserves Source = "reserves"
+)
+
+// Sample is one observation of the USD price of an asset.
+type Sample struct {
+	Time     time.Time
+	PriceUsd float64
+	// VolumeUsd is the value traded, the weight of the sample in a VWAP.
+	// Reserve samples have none.
+	VolumeUsd float64
+	Source    Source
+	Pool      string
+	// CurrentPrice is set when the sample was priced with the current price
+	// of the other asset of its pool, rather than its price at Time.
+	CurrentPrice bool
+}
+
+// bySource returns the samples from source, in order.
+func bySource(samples []Sample, source Source) []Sample {
+	var matching []Sample
+	for _, s := range samples {
+		if s.Source == source {
+			matching = append(matching, s)
+		}
+	}
+	return matching
+}
+
+// TWAP returns the time-weighted average price of samples, sorted by time,
+// over [from, to]. Each price holds from its sample until the next one, or
+// until to for the last. Samples before from count from from. It returns 0
+// if there are no samples.
+func TWAP(samples []Sample, from, to time.Time) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var weighted, total float64
+	for i, s := range samples {
+		start := s.Time
+		if start.Before(from) {
+			start = from
+		}
+		end := to
+		if i+1 < len(samples) {
+			end = samples[i+1].Time
+		}
+		if d := end.Sub(start).Seconds(); d > 0 {
+			weighted += s.PriceUsd * d
+			total += d
+		}
+	}
+	if total == 0 {
+		// Every sample is at the end of the window.
+		return mean(samples)
+	}
+	return weighted / total
+}
+
+// VWAP returns the volume-weighted average price of samples, or 0 if none
+// has volume.
+func VWAP(samples []Sample) float64 {
+	var value, volume float64
+	for _, s := range samples {
+		value += s.PriceUsd * s.VolumeUsd
+		volume += s.VolumeUsd
+	}
+	if volume == 0 {
+		return 0
+	}
+	return value / volume
+}
+
+// minSpread is the smallest spread, relative to the median, used to reject
+// outliers, so that a run of identical prices does not reject every other
+// price however close.
+const minSpread = 0.001
+
+// RejectOutliers splits samples into those whose price is within threshold
+// scaled median absolute deviations of the median, and the rest. The order
+// of samples is kept.
+func RejectOutliers(samples []Sample, threshold float64) (kept, rejected []Sample) {
+	if len(samples) < 3 || threshold <= 0 {
+		return samples, nil
+	}
+	prices := make([]float64, len(samples))
+	for i, s := range samples {
+		prices[i] = s.PriceUsd
+	}
+	m := median(prices)
+	for i, p := range prices {
+		prices[i] = math.Abs(p - m)
+	}
+	// 1.4826 makes the MAD an estimate of the standard deviation of normally
+	// distributed prices.
+	spread := max(1.4826*median(prices), math.Abs(m)*minSpread)
+	for _, s := range samples {
+		if math.Abs(s.PriceUsd-m) > threshold*spread {
+			rejected = append(rejected, s)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+	return kept, rejected
+}
+
+// deviation returns the root mean square deviation of the sample prices from
+// price, relative to price.
+func deviation(samples []Sample, price float64) float64 {
+	if len(samples) == 0 || price == 0 {
+		return 0
+	}
+	var sum float64
+	for _, s := range samples {
+		d := s.PriceUsd - price
+		sum += d * d
+	}
+	return math.Sqrt(sum/float64(len(samples))) / price
+}
+
+func mean(samples []Sample) float64 {
+	var sum float64
+	for _, s := range samples {
+		sum += s.PriceUsd
+	}
+	return sum / float64(len(samples))
+}
+
+// median sorts values in place and returns their median.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
@@ -0,0 +1,40 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func sampleAt(minutes int, price, volume float64) Sample {
+	return Sample{Time: t0.Add(time.Duration(minutes) * time.Minute), PriceUsd: price, VolumeUsd: volume}
+}
+
+func TestTWAP(t *testing.T) {
+	samples := []Sample{sampleAt(-10, 4, 0), sampleAt(30, 6, 0)}
+	// 4 holds from the start of the window until 30 minutes, then 6.
+	assert.InDelta(t, 5, TWAP(samples, t0, t0.Add(time.Hour)), 1e-9)
+	assert.Equal(t, 7.0, TWAP([]Sample{sampleAt(60, 7, 0)}, t0, t0.Add(time.Hour)))
+	assert.Zero(t, TWAP(nil, t0, t0.Add(time.Hour)))
+}
+
+func TestVWAP(t *testing.T) {
+	assert.InDelta(t, 5.5, VWAP([]Sample{sampleAt(0, 5, 1), sampleAt(1, 6, 1), sampleAt(2, 100, 0)}), 1e-9)
+	assert.Zero(t, VWAP([]Sample{sampleAt(0, 5, 0)}))
+}
+
+func TestRejectOutliers(t *testing.T) {
+	samples := []Sample{sampleAt(0, 5, 0), sampleAt(1, 5.1, 0), sampleAt(2, 50, 0), sampleAt(3, 4.9, 0), sampleAt(4, 0.5, 0)}
+	kept, rejected := RejectOutliers(samples, 5)
+	assert.Equal(t, []Sample{samples[0], samples[1], samples[3]}, kept)
+	assert.Equal(t, []Sample{samples[2], samples[4]}, rejected)
+
+	// Identical prices do not make every other price an outlier.
+	same := []Sample{sampleAt(0, 5, 0), sampleAt(1, 5, 0), sampleAt(2, 5, 0), sampleAt(3, 5.001, 0)}
+	kept, rejected = RejectOutliers(same, 5)
+	assert.Len(t, kept, 4)
+	assert.Empty(t, rejected)
+}
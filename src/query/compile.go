@@ -0,0 +1,228 @@
+package query
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// kind is the type of a field's values.
+type kind int
+
+const (
+	number kind = iota
+	text
+	boolean
+)
+
+func (k kind) String() string {
+	return [...]string{"number", "text", "boolean"}[k]
+}
+
+// getter returns the values of a field of the item being matched. Most
+// fields have one value; the token field of a pool, for example, has each
+// token's address and symbol.
+type getter func(field string) []string
+
+type predicate func(getter) bool
+
+func (c Cond) compile(fields map[string]kind) (predicate, error) {
+	k, ok := fields[c.Field]
+	if !ok {
+		return nil, unknownField(c.Field, fields)
+	}
+	if c.Op == "" {
+		if k != boolean {
+			return nil, fmt.Errorf("%s is a %s field; compare it to a value", c.Field, k)
+		}
+		return func(get getter) bool { return anyOf(get(c.Field), isTrue) }, nil
+	}
+
+	switch k {
+	case number:
+		want, ok := parseDecimal(c.Value)
+		if !ok {
+			return nil, fmt.Errorf("%s is a number field, not comparable to %q", c.Field, c.Value)
+		}
+		var test func(int) bool
+		switch c.Op {
+		case Eq:
+			test = func(c int) bool { return c == 0 }
+		case Ne:
+			test = func(c int) bool { return c != 0 }
+		case Gt:
+			test = func(c int) bool { return c > 0 }
+		case Ge:
+			test = func(c int) bool { return c >= 0 }
+		case Lt:
+			test = func(c int) bool { return c < 0 }
+		case Le:
+			test = func(c int) bool { return c <= 0 }
+		default:
+			return nil, fmt.Errorf("%s does not apply to number field %s", c.Op, c.Field)
+		}
+		// Items without a value match no comparison.
+		return func(get getter) bool {
+			return anyOf(get(c.Field), func(v string) bool {
+				have, ok := parseDecimal(v)
+				return ok && test(have.Cmp(want))
+			})
+		}, nil
+
+	case text:
+		want := strings.ToLower(utils.AddressKey(c.Value))
+		equal := func(v string) bool { return strings.ToLower(utils.AddressKey(v)) == want }
+		switch c.Op {
+		case Eq:
+			return func(get getter) bool { return anyOf(get(c.Field), equal) }, nil
+		case Ne:
+			return func(get getter) bool { return !anyOf(get(c.Field), equal) }, nil
+		case Contains:
+			want := strings.ToLower(c.Value)
+			return func(get getter) bool {
+				return anyOf(get(c.Field), func(v string) bool { return strings.Contains(strings.ToLower(v), want) })
+			}, nil
+		default:
+			return nil, fmt.Errorf("%s does not apply to text field %s; use =, != or ~", c.Op, c.Field)
+		}
+
+	default:
+		var want bool
+		switch strings.ToLower(c.Value) {
+		case "true":
+			want = true
+		case "false":
+		default:
+			return nil, fmt.Errorf("%s is a boolean field, not comparable to %q", c.Field, c.Value)
+		}
+		switch c.Op {
+		case Eq:
+			return func(get getter) bool { return anyOf(get(c.Field), isTrue) == want }, nil
+		case Ne:
+			return func(get getter) bool { return anyOf(get(c.Field), isTrue) != want }, nil
+		default:
+			return nil, fmt.Errorf("%s does not apply to boolean field %s; use = or !=", c.Op, c.Field)
+		}
+	}
+}
+
+func (e and) compile(fields map[string]kind) (predicate, error) {
+	preds, err := compileAll(e, fields)
+	if err != nil {
+		return nil, err
+	}
+	return func(get getter) bool {
+		for _, p := range preds {
+			if !p(get) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (e or) compile(fields map[string]kind) (predicate, error) {
+	preds, err := compileAll(e, fields)
+	if err != nil {
+		return nil, err
+	}
+	return func(get getter) bool {
+		for _, p := range preds {
+			if p(get) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (e not) compile(fields map[string]kind) (predicate, error) {
+	p, err := e.x.compile(fields)
+	if err != nil {
+		return nil, err
+	}
+	return func(get getter) bool { return !p(get) }, nil
+}
+
+func compileAll(exprs []Expr, fields map[string]kind) ([]predicate, error) {
+	preds := make([]predicate, len(exprs))
+	for i, x := range exprs {
+		p, err := x.compile(fields)
+		if err != nil {
+			return nil, err
+		}
+		preds[i] = p
+	}
+	return preds, nil
+}
+
+func unknownField(name string, fields map[string]kind) error {
+	names := make([]string, 0, len(fields))
+	for n := range fields {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown field %q; fields are %s", name, strings.Join(names, ", "))
+}
+
+func anyOf(values []string, f func(string) bool) bool {
+	for _, v := range values {
+		if f(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTrue(v string) bool { return v == "true" }
+
+// parseDecimal parses a decimal number exactly. The empty string is no
+// value.
+func parseDecimal(s string) (*big.Rat, bool) {
+	if s == "" {
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// sortKey is an item's value of an order field; absent sorts last.
+type sortKey struct {
+	absent bool
+	num    *big.Rat
+	text   string
+}
+
+func keyOf(k kind, values []string) sortKey {
+	if len(values) == 0 {
+		return sortKey{absent: true}
+	}
+	if k == number {
+		n, ok := parseDecimal(values[0])
+		return sortKey{absent: !ok, num: n}
+	}
+	return sortKey{text: strings.ToLower(values[0])}
+}
+
+// compareKeys orders a before b, or not, with absent values last in either
+// direction.
+func compareKeys(a, b sortKey, desc bool) int {
+	switch {
+	case a.absent && b.absent:
+		return 0
+	case a.absent:
+		return 1
+	case b.absent:
+		return -1
+	}
+	c := strings.Compare(a.text, b.text)
+	if a.num != nil {
+		c = a.num.Cmp(b.num)
+	}
+	if desc {
+		return -c
+	}
+	return c
+}
@@ -0,0 +1,134 @@
+package query
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// field is a queryable property of an item of type T.
+type field[T any] struct {
+	kind   kind
+	values func(T) []string
+}
+
+func one[T any](k kind, get func(T) string) field[T] {
+	return field[T]{k, func(item T) []string {
+		if v := get(item); v != "" {
+			return []string{v}
+		}
+		return nil
+	}}
+}
+
+func flag[T any](get func(T) bool) field[T] {
+	return field[T]{boolean, func(item T) []string { return []string{strconv.FormatBool(get(item))} }}
+}
+
+// symbols maps asset addresses to symbols.
+func symbols(assets []types.Asset) map[string]string {
+	m := make(map[string]string, len(assets))
+	for _, a := range assets {
+		m[utils.AddressKey(a.ContractAddress)] = a.Symbol
+	}
+	return m
+}
+
+// tokens returns the addresses, and the symbols that are known, of tokens.
+func tokens(symbols map[string]string, addresses ...string) []string {
+	var values []string
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+		values = append(values, address)
+		if symbol, ok := symbols[utils.AddressKey(address)]; ok && symbol != "" {
+			values = append(values, symbol)
+		}
+	}
+	return values
+}
+
+func poolFields(symbols map[string]string) map[string]field[types.Pool] {
+	return map[string]field[types.Pool]{
+		"address": one(text, func(p types.Pool) string { return p.Address }),
+		"router":  one(text, func(p types.Pool) string { return p.RouterAddress }),
+		"token": {text, func(p types.Pool) []string {
+			return tokens(symbols, p.Token0Address, p.Token1Address)
+		}},
+		"token0":       {text, func(p types.Pool) []string { return tokens(symbols, p.Token0Address) }},
+		"token1":       {text, func(p types.Pool) []string { return tokens(symbols, p.Token1Address) }},
+		"reserve0":     one(number, func(p types.Pool) string { return p.Reserve0 }),
+		"reserve1":     one(number, func(p types.Pool) string { return p.Reserve1 }),
+		"tvl":          one(number, func(p types.Pool) string { return p.LpTotalSupplyUsd }),
+		"lp_supply":    one(number, func(p types.Pool) string { return p.LpTotalSupply }),
+		"lp_price":     one(number, func(p types.Pool) string { return p.LpPriceUsd }),
+		"lp_fee":       one(number, func(p types.Pool) string { return p.LpFee }),
+		"protocol_fee": one(number, func(p types.Pool) string { return p.ProtocolFee }),
+		"apy_1d":       one(number, func(p types.Pool) string { return p.Apy1D }),
+		"apy_7d":       one(number, func(p types.Pool) string { return p.Apy7D }),
+		"apy_30d":      one(number, func(p types.Pool) string { return p.Apy30D }),
+		"deprecated":   flag(func(p types.Pool) bool { return p.Deprecated }),
+	}
+}
+
+var assetFields = map[string]field[types.Asset]{
+	"address": one(text, func(a types.Asset) string { return a.ContractAddress }),
+	"symbol":  one(text, func(a types.Asset) string { return a.Symbol }),
+	"name":    one(text, func(a types.Asset) string { return a.DisplayName }),
+	"kind":    one(text, func(a types.Asset) string { return string(a.Kind) }),
+	"tag":     {text, func(a types.Asset) []string { return a.Tags }},
+	"price": one(number, func(a types.Asset) string {
+		if a.DexPriceUsd != "" {
+			return a.DexPriceUsd
+		}
+		return a.DexUsdPrice
+	}),
+	"decimals":       one(number, func(a types.Asset) string { return strconv.Itoa(a.Decimals) }),
+	"priority":       one(number, func(a types.Asset) string { return strconv.Itoa(a.Priority) }),
+	"default_symbol": flag(func(a types.Asset) bool { return a.DefaultSymbol }),
+	"taxable":        flag(func(a types.Asset) bool { return a.Taxable }),
+	"blacklisted":    flag(func(a types.Asset) bool { return a.Blacklisted }),
+	"community":      flag(func(a types.Asset) bool { return a.Community }),
+	"deprecated":     flag(func(a types.Asset) bool { return a.Deprecated }),
+}
+
+func farmFields(symbols map[string]string) map[string]field[types.Farm] {
+	return map[string]field[types.Farm]{
+		"address": one(text, func(f types.Farm) string { return f.MinterAddress }),
+		"pool":    one(text, func(f types.Farm) string { return f.PoolAddress }),
+		// token is any reward token.
+		"token": {text, func(f types.Farm) []string {
+			addresses := []string{f.RewardTokenAddress}
+			for _, r := range f.Rewards {
+				addresses = append(addresses, r.Address)
+			}
+			return tokens(symbols, addresses...)
+		}},
+		"status":             one(text, func(f types.Farm) string { return f.Status }),
+		"tvl":                one(number, func(f types.Farm) string { return f.LockedTotalLPUSD }),
+		"locked_lp":          one(number, func(f types.Farm) string { return f.LockedTotalLP }),
+		"apy":                one(number, func(f types.Farm) string { return f.APY }),
+		"min_stake_duration": one(number, func(f types.Farm) string { return f.MinStakeDurationS }),
+	}
+}
+
+// PoolFields returns the names of the fields of pools.
+func PoolFields() []string { return names(poolFields(nil)) }
+
+// AssetFields returns the names of the fields of assets.
+func AssetFields() []string { return names(assetFields) }
+
+// FarmFields returns the names of the fields of farms.
+func FarmFields() []string { return names(farmFields(nil)) }
+
+func names[T any](fields map[string]field[T]) []string {
+	n := make([]string, 0, len(fields))
+	for name := range fields {
+		n = append(n, name)
+	}
+	sort.Strings(n)
+	return n
+}
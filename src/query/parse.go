@@ -0,0 +1,263 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// keywords are reserved in field and operator positions; as values, they
+// must be quoted to be told apart from the DSL.
+var keywords = map[string]bool{"and": true, "or": true, "not": true, "order": true, "by": true, "asc": true, "desc": true, "limit": true}
+
+func isKeyword(s string) bool { return keywords[strings.ToLower(s)] }
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lex splits s into words, quoted strings, operators and punctuation.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokComma, ",", i})
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(s) && s[end] != c {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("query: at %d: unterminated string", i)
+			}
+			text := s[i+1 : end]
+			if c == '"' {
+				unquoted, err := strconv.Unquote(s[i : end+1])
+				if err != nil {
+					return nil, fmt.Errorf("query: at %d: invalid string: %v", i, err)
+				}
+				text = unquoted
+			}
+			tokens = append(tokens, token{tokString, text, i})
+			i = end + 1
+		case strings.IndexByte("<>=!~", c) >= 0:
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' && c != '=' && c != '~' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("query: at %d: unexpected !; use != or not", i)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		default:
+			end := i
+			for end < len(s) && !strings.ContainsRune(" \t\n\r(),\"'<>=!~", rune(s[end])) {
+				end++
+			}
+			tokens = append(tokens, token{tokWord, s[i:end], i})
+			i = end
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(s)}), nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token { return p.tokens[p.i] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// keyword reports whether the next token is the keyword kw, consuming it if
+// so.
+func (p *parser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokWord && strings.EqualFold(t.text, kw) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	at := fmt.Sprintf("%q", t.text)
+	if t.kind == tokEOF {
+		at = "end"
+	}
+	return fmt.Errorf("query: at %d (%s): %s", t.pos, at, fmt.Sprintf(format, args...))
+}
+
+// Parse parses a query:
+//
+//	query  = [expr] ["order" "by" field ["asc" | "desc"] {"," ...}] ["limit" n]
+//	expr   = term {"or" term}
+//	term   = factor {"and" factor}
+//	factor = "not" factor | "(" expr ")" | field [op value]
+//
+// The operators are =, !=, >, >=, <, <= and ~ (contains). A field alone
+// checks that a boolean field is true. Values are words or quoted strings.
+// Keywords are case-insensitive. Fields are checked once the query is run,
+// against the kind of items it runs on.
+func Parse(s string) (Query, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return Query{}, err
+	}
+	p := &parser{tokens: tokens}
+	var q Query
+	if t := p.peek(); t.kind != tokEOF && !(t.kind == tokWord && (strings.EqualFold(t.text, "order") || strings.EqualFold(t.text, "limit"))) {
+		if q.where, err = p.expr(); err != nil {
+			return Query{}, err
+		}
+	}
+	if p.keyword("order") {
+		if !p.keyword("by") {
+			return Query{}, p.errorf(p.peek(), "expected by after order")
+		}
+		for {
+			t := p.next()
+			if t.kind != tokWord || isKeyword(t.text) {
+				return Query{}, p.errorf(t, "expected a field to order by")
+			}
+			o := Order{Field: strings.ToLower(t.text)}
+			if p.keyword("desc") {
+				o.Desc = true
+			} else {
+				p.keyword("asc")
+			}
+			q.order = append(q.order, o)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.keyword("limit") {
+		t := p.next()
+		n, err := strconv.Atoi(t.text)
+		if t.kind != tokWord || err != nil || n <= 0 {
+			return Query{}, p.errorf(t, "expected a positive limit")
+		}
+		q.limit = n
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return Query{}, p.errorf(t, "unexpected input")
+	}
+	return q, nil
+}
+
+// MustParse is Parse, panicking on error, for queries fixed in code.
+func MustParse(s string) Query {
+	q, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func (p *parser) expr() (Expr, error) {
+	terms := or{}
+	for {
+		t, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+		if !p.keyword("or") {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *parser) term() (Expr, error) {
+	factors := and{}
+	for {
+		f, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		factors = append(factors, f)
+		if !p.keyword("and") {
+			break
+		}
+	}
+	if len(factors) == 1 {
+		return factors[0], nil
+	}
+	return factors, nil
+}
+
+func (p *parser) factor() (Expr, error) {
+	if p.keyword("not") {
+		x, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return not{x}, nil
+	}
+	t := p.next()
+	switch {
+	case t.kind == tokLParen:
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, p.errorf(t, "expected )")
+		}
+		return x, nil
+	case t.kind != tokWord || isKeyword(t.text):
+		return nil, p.errorf(t, "expected a field")
+	}
+	c := Cond{Field: strings.ToLower(t.text)}
+	if p.peek().kind != tokOp {
+		return c, nil
+	}
+	c.Op = Op(p.next().text)
+	v := p.next()
+	if v.kind != tokWord && v.kind != tokString {
+		return nil, p.errorf(v, "expected a value after %s", c.Op)
+	}
+	c.Value = v.text
+	return c, nil
+}
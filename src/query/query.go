@@ -0,0 +1,262 @@
+// Package query filters and sorts pools, assets and farms. A Query is built
+// in Go or parsed from a small DSL:
+//
+//	tvl>100000 and token=USDT and not deprecated order by apy_7d desc limit 20
+//
+// Numeric fields are compared as exact decimals, not as strings or floats.
+// The fields of each kind of item are listed by PoolFields, AssetFields and
+// FarmFields.
+package query
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/itay747/go-stonfi/src/types"
+)
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	Eq       Op = "="
+	Ne       Op = "!="
+	Gt       Op = ">"
+	Ge       Op = ">="
+	Lt       Op = "<"
+	Le       Op = "<="
+	Contains Op = "~"
+)
+
+// Expr is a condition on an item.
+type Expr interface {
+	// String returns the condition in the DSL.
+	String() string
+	compile(fields map[string]kind) (predicate, error)
+}
+
+// Cond compares a field to a value. Without an operator, it checks that a
+// boolean field is true.
+type Cond struct {
+	Field string
+	Op    Op
+	Value string
+}
+
+// FieldRef names a field, to build conditions on.
+type FieldRef string
+
+// Field returns a reference to the named field.
+func Field(name string) FieldRef { return FieldRef(name) }
+
+func (f FieldRef) Eq(v interface{}) Cond       { return Cond{string(f), Eq, format(v)} }
+func (f FieldRef) Ne(v interface{}) Cond       { return Cond{string(f), Ne, format(v)} }
+func (f FieldRef) Gt(v interface{}) Cond       { return Cond{string(f), Gt, format(v)} }
+func (f FieldRef) Ge(v interface{}) Cond       { return Cond{string(f), Ge, format(v)} }
+func (f FieldRef) Lt(v interface{}) Cond       { return Cond{string(f), Lt, format(v)} }
+func (f FieldRef) Le(v interface{}) Cond       { return Cond{string(f), Le, format(v)} }
+func (f FieldRef) Contains(v interface{}) Cond { return Cond{string(f), Contains, format(v)} }
+
+// IsTrue checks that a boolean field is true.
+func (f FieldRef) IsTrue() Cond { return Cond{Field: string(f)} }
+
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *big.Rat:
+		return v.RatString()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (c Cond) String() string {
+	if c.Op == "" {
+		return c.Field
+	}
+	value := c.Value
+	if value == "" || strings.ContainsAny(value, " \t()<>=!~,\"") || isKeyword(value) {
+		value = strconv.Quote(value)
+	}
+	return c.Field + string(c.Op) + value
+}
+
+type and []Expr
+type or []Expr
+type not struct{ x Expr }
+
+// And matches items every expression matches.
+func And(exprs ...Expr) Expr { return and(exprs) }
+
+// Or matches items any expression matches.
+func Or(exprs ...Expr) Expr { return or(exprs) }
+
+// Not matches items x does not.
+func Not(x Expr) Expr { return not{x} }
+
+func (e and) String() string { return join(e, " and ") }
+func (e or) String() string  { return join(e, " or ") }
+func (e not) String() string { return "not " + group(e.x) }
+
+func join(exprs []Expr, sep string) string {
+	parts := make([]string, len(exprs))
+	for i, x := range exprs {
+		parts[i] = group(x)
+	}
+	return strings.Join(parts, sep)
+}
+
+// group parenthesizes compound expressions.
+func group(x Expr) string {
+	switch x := x.(type) {
+	case and:
+		if len(x) > 1 {
+			return "(" + x.String() + ")"
+		}
+	case or:
+		if len(x) > 1 {
+			return "(" + x.String() + ")"
+		}
+	}
+	return x.String()
+}
+
+// Order sorts by a field. Items without a value for it come last.
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Query filters, sorts and limits items. The zero Query returns every item
+// in its original order.
+type Query struct {
+	where Expr
+	order []Order
+	limit int
+}
+
+// New returns an empty query.
+func New() Query { return Query{} }
+
+// Where adds a condition every item must meet.
+func (q Query) Where(x Expr) Query {
+	switch w := q.where.(type) {
+	case nil:
+		q.where = x
+	case and:
+		q.where = append(append(and{}, w...), x)
+	default:
+		q.where = and{w, x}
+	}
+	return q
+}
+
+// OrderBy adds a sort key, after those already added.
+func (q Query) OrderBy(field string, desc bool) Query {
+	q.order = append(append([]Order{}, q.order...), Order{field, desc})
+	return q
+}
+
+// Limit keeps at most n items; zero keeps all.
+func (q Query) Limit(n int) Query {
+	q.limit = n
+	return q
+}
+
+// String returns the query in the DSL.
+func (q Query) String() string {
+	var parts []string
+	if q.where != nil {
+		parts = append(parts, q.where.String())
+	}
+	if len(q.order) > 0 {
+		keys := make([]string, len(q.order))
+		for i, o := range q.order {
+			keys[i] = o.Field
+			if o.Desc {
+				keys[i] += " desc"
+			}
+		}
+		parts = append(parts, "order by "+strings.Join(keys, ", "))
+	}
+	if q.limit > 0 {
+		parts = append(parts, "limit "+strconv.Itoa(q.limit))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Pools returns the pools matching q. assets resolves the symbols of the
+// token field and may be nil, leaving only addresses to match.
+func (q Query) Pools(pools []types.Pool, assets []types.Asset) ([]types.Pool, error) {
+	return apply(q, "pools", poolFields(symbols(assets)), pools)
+}
+
+// Assets returns the assets matching q.
+func (q Query) Assets(assets []types.Asset) ([]types.Asset, error) {
+	return apply(q, "assets", assetFields, assets)
+}
+
+// Farms returns the farms matching q. assets resolves the symbols of the
+// token field and may be nil.
+func (q Query) Farms(farms []types.Farm, assets []types.Asset) ([]types.Farm, error) {
+	return apply(q, "farms", farmFields(symbols(assets)), farms)
+}
+
+// apply compiles q against fields and runs it on items.
+func apply[T any](q Query, kindName string, fields map[string]field[T], items []T) ([]T, error) {
+	kinds := make(map[string]kind, len(fields))
+	for name, f := range fields {
+		kinds[name] = f.kind
+	}
+	match := predicate(func(getter) bool { return true })
+	if q.where != nil {
+		var err error
+		if match, err = q.where.compile(kinds); err != nil {
+			return nil, fmt.Errorf("query: %s: %w", kindName, err)
+		}
+	}
+	for _, o := range q.order {
+		if _, ok := fields[o.Field]; !ok {
+			return nil, fmt.Errorf("query: %s: %w", kindName, unknownField(o.Field, kinds))
+		}
+	}
+
+	type row struct {
+		item T
+		keys []sortKey
+	}
+	var rows []row
+	for _, item := range items {
+		get := func(name string) []string { return fields[name].values(item) }
+		if !match(get) {
+			continue
+		}
+		r := row{item: item}
+		for _, o := range q.order {
+			r.keys = append(r.keys, keyOf(fields[o.Field].kind, get(o.Field)))
+		}
+		rows = append(rows, r)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for k, o := range q.order {
+			if c := compareKeys(rows[i].keys[k], rows[j].keys[k], o.Desc); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	if q.limit > 0 && len(rows) > q.limit {
+		rows = rows[:q.limit]
+	}
+	result := make([]T, len(rows))
+	for i, r := range rows {
+		result[i] = r.item
+	}
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	notAddress  = "EQAvlWFDxGF2lXm67y4yzC17wYKD9A0guwPkMs1gOsM__NOT"
+)
+
+var assets = []types.Asset{
+	{ContractAddress: tonAddress, Symbol: "TON", DexPriceUsd: "5.2", Tags: []string{"default"}},
+	{ContractAddress: usdtAddress, Symbol: "USDT", DexPriceUsd: "1", Tags: []string{"default", "stable"}},
+	{ContractAddress: notAddress, Symbol: "NOT", DexUsdPrice: "0.0089", Blacklisted: true},
+}
+
+var pools = []types.Pool{
+	{Address: "a", Token0Address: tonAddress, Token1Address: usdtAddress, LpTotalSupplyUsd: "9000000.5", Apy7D: "0.12"},
+	{Address: "b", Token0Address: notAddress, Token1Address: usdtAddress, LpTotalSupplyUsd: "120000", Apy7D: "0.4"},
+	// As strings, "99999" > "100000"; as decimals it is not.
+	{Address: "c", Token0Address: tonAddress, Token1Address: usdtAddress, LpTotalSupplyUsd: "99999", Apy7D: "0.9"},
+	{Address: "d", Token0Address: tonAddress, Token1Address: usdtAddress, LpTotalSupplyUsd: "500000", Apy7D: "0.3", Deprecated: true},
+	{Address: "e", Token0Address: tonAddress, Token1Address: notAddress, LpTotalSupplyUsd: "300000"},
+}
+
+func addresses(pools []types.Pool) []string {
+	var a []string
+	for _, p := range pools {
+		a = append(a, p.Address)
+	}
+	return a
+}
+
+func TestParseAndRun(t *testing.T) {
+	for _, tt := range []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"a", "b", "c", "d", "e"}},
+		{"tvl>100000 and token=USDT and not deprecated order by apy_7d desc limit 20", []string{"b", "a"}},
+		{"tvl >= 99999 AND tvl <= 120000", []string{"b", "c"}},
+		{"token=usdt and token=not", []string{"b"}},
+		{"token=" + usdtAddress + " and token!=TON", []string{"b"}},
+		{"deprecated or (token0=NOT and apy_7d>0.1)", []string{"b", "d"}},
+		{"deprecated = false and token1 ~ no", []string{"e"}},
+		{"order by apy_7d", []string{"a", "d", "b", "c", "e"}},
+		{"order by apy_7d desc, address limit 2", []string{"c", "b"}},
+		{"apy_7d != 0.4", []string{"a", "c", "d"}},
+	} {
+		q, err := Parse(tt.query)
+		if !assert.NoError(t, err, tt.query) {
+			continue
+		}
+		got, err := q.Pools(pools, assets)
+		if assert.NoError(t, err, tt.query) {
+			assert.Equal(t, tt.want, addresses(got), tt.query)
+		}
+	}
+}
+
+func TestAssetsAndFarms(t *testing.T) {
+	got, err := MustParse("tag=stable or price<0.01").Assets(assets)
+	if assert.NoError(t, err) && assert.Len(t, got, 2) {
+		assert.Equal(t, "USDT", got[0].Symbol)
+		assert.Equal(t, "NOT", got[1].Symbol, "dex_usd_price stands in for dex_price_usd")
+	}
+	got, err = MustParse("not blacklisted order by price desc").Assets(assets)
+	if assert.NoError(t, err) && assert.Len(t, got, 2) {
+		assert.Equal(t, "TON", got[0].Symbol)
+	}
+
+	farms := []types.Farm{
+		{MinterAddress: "f1", Status: "operational", APY: "0.5", LockedTotalLPUSD: "1000", Rewards: []types.FarmReward{{Address: notAddress}}},
+		{MinterAddress: "f2", Status: "paused", APY: "1.5", LockedTotalLPUSD: "50000", RewardTokenAddress: tonAddress},
+	}
+	gotFarms, err := MustParse("status=operational and token=NOT").Farms(farms, assets)
+	if assert.NoError(t, err) && assert.Len(t, gotFarms, 1) {
+		assert.Equal(t, "f1", gotFarms[0].MinterAddress)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	q := New().
+		Where(Field("tvl").Gt(100000)).
+		Where(Field("token").Eq("USDT")).
+		Where(Not(Field("deprecated").IsTrue())).
+		OrderBy("apy_7d", true).
+		Limit(20)
+	assert.Equal(t, "tvl>100000 and token=USDT and not deprecated order by apy_7d desc limit 20", q.String())
+	got, err := q.Pools(pools, assets)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"b", "a"}, addresses(got))
+	}
+
+	q = New().Where(Or(Field("token").Eq("NOT"), And(Field("tvl").Ge(0.5), Field("apy_7d").Lt("0.2"))))
+	assert.Equal(t, `token="NOT" or (tvl>=0.5 and apy_7d<0.2)`, q.String())
+	reparsed, err := Parse(q.String())
+	if assert.NoError(t, err) {
+		assert.Equal(t, q, reparsed)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, tt := range []struct{ query, err string }{
+		{"tvl >", "expected a value"},
+		{"(tvl > 1", "expected )"},
+		{"tvl > 1 limit 0", "positive limit"},
+		{"order apy_7d", "expected by"},
+		{"tvl > 1 tvl", "unexpected input"},
+		{"token = 'USDT", "unterminated"},
+		{"tvl ! 1", "use != or not"},
+	} {
+		_, err := Parse(tt.query)
+		assert.ErrorContains(t, err, tt.err, tt.query)
+	}
+	for _, tt := range []struct{ query, err string }{
+		{"symbol=TON", `unknown field "symbol"`},
+		{"tvl>lots", "not comparable"},
+		{"token>USDT", "does not apply"},
+		{"tvl", "compare it to a value"},
+		{"deprecated=maybe", "not comparable"},
+		{"order by nothing", `unknown field "nothing"`},
+	} {
+		_, err := MustParse(tt.query).Pools(pools, assets)
+		assert.ErrorContains(t, err, tt.err, tt.query)
+	}
+}
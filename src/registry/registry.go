@@ -0,0 +1,244 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+var (
+	ErrNotFound = errors.New("asset not found")
+	ErrExcluded = errors.New("asset excluded by policy")
+)
+
+// AmbiguousError is returned when a symbol or name matches several assets and
+// none of them is preferred over the others.
+type AmbiguousError struct {
+	Query      string
+	Candidates []types.Asset
+}
+
+func (e *AmbiguousError) Error() string {
+	addresses := make([]string, len(e.Candidates))
+	for i, a := range e.Candidates {
+		addresses[i] = fmt.Sprintf("%s (%s)", a.ContractAddress, a.DisplayName)
+	}
+	return fmt.Sprintf("%q matches %d assets: %s", e.Query, len(e.Candidates), strings.Join(addresses, ", "))
+}
+
+// Policy controls which assets the registry will resolve to.
+type Policy struct {
+	AllowBlacklisted bool
+	AllowDeprecated  bool
+	ExcludeCommunity bool
+}
+
+// DefaultPolicy resolves to any asset that is neither blacklisted nor deprecated.
+var DefaultPolicy = Policy{}
+
+func (p Policy) allows(a *types.Asset) bool {
+	return (p.AllowBlacklisted || !a.Blacklisted) &&
+		(p.AllowDeprecated || !a.Deprecated) &&
+		(!p.ExcludeCommunity || !a.Community)
+}
+
+// AssetRegistry is an in-memory index of assets by address, symbol and name.
+type AssetRegistry struct {
+	policy    Policy
+	assets    []types.Asset
+	byAddress map[string]int
+	bySymbol  map[string][]int
+}
+
+// NewAssetRegistry indexes assets, resolving according to policy.
+func NewAssetRegistry(assets []types.Asset, policy Policy) *AssetRegistry {
+	r := &AssetRegistry{
+		policy:    policy,
+		assets:    assets,
+		byAddress: make(map[string]int, len(assets)),
+		bySymbol:  make(map[string][]int),
+	}
+	for i, a := range assets {
+		r.byAddress[utils.AddressKey(a.ContractAddress)] = i
+		symbol := strings.ToUpper(a.Symbol)
+		r.bySymbol[symbol] = append(r.bySymbol[symbol], i)
+	}
+	return r
+}
+
+// LoadAssetRegistry builds a registry from the current GetAssets snapshot.
+func LoadAssetRegistry(ctx context.Context, c *client.StonfiClient, policy Policy) (*AssetRegistry, error) {
+	response, err := c.GetAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewAssetRegistry(response.AssetList, policy), nil
+}
+
+// Assets returns every asset allowed by the registry's policy.
+func (r *AssetRegistry) Assets() []types.Asset {
+	var assets []types.Asset
+	for i := range r.assets {
+		if r.policy.allows(&r.assets[i]) {
+			assets = append(assets, r.assets[i])
+		}
+	}
+	return assets
+}
+
+// Resolve looks query up as an address, then as a symbol, then as a display name.
+func (r *AssetRegistry) Resolve(query string) (*types.Asset, error) {
+	if _, err := utils.NormalizeAddress(query); err == nil {
+		return r.ByAddress(query)
+	}
+	asset, err := r.BySymbol(query)
+	if !errors.Is(err, ErrNotFound) {
+		return asset, err
+	}
+	return r.ByName(query)
+}
+
+// ByAddress finds an asset by its contract address in any form.
+func (r *AssetRegistry) ByAddress(address string) (*types.Asset, error) {
+	i, ok := r.byAddress[utils.AddressKey(address)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, address)
+	}
+	asset := &r.assets[i]
+	if !r.policy.allows(asset) {
+		return nil, fmt.Errorf("%w: %s (%s)", ErrExcluded, address, exclusionReason(asset))
+	}
+	return asset, nil
+}
+
+// BySymbol finds the asset for a ticker symbol, case-insensitively. When
+// several assets share the symbol, the one flagged as the default symbol wins,
+// then the one with the highest priority; otherwise an *AmbiguousError lists
+// the candidates. A symbol whose assets are all excluded by the policy is
+// ErrExcluded.
+func (r *AssetRegistry) BySymbol(symbol string) (*types.Asset, error) {
+	matches := r.bySymbol[strings.ToUpper(symbol)]
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, symbol)
+	}
+	var candidates []*types.Asset
+	for _, i := range matches {
+		if r.policy.allows(&r.assets[i]) {
+			candidates = append(candidates, &r.assets[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: %s (%s)", ErrExcluded, symbol, exclusionReason(&r.assets[matches[0]]))
+	}
+	return pick(symbol, candidates)
+}
+
+// ByName finds an asset by display name. An exact (case-insensitive) match is
+// preferred, then names containing the query, then names within a small edit
+// distance of it.
+func (r *AssetRegistry) ByName(name string) (*types.Asset, error) {
+	query := strings.ToLower(strings.TrimSpace(name))
+	if query == "" {
+		return nil, fmt.Errorf("%w: empty name", ErrNotFound)
+	}
+	var exact, contains, near []*types.Asset
+	for i := range r.assets {
+		asset := &r.assets[i]
+		if !r.policy.allows(asset) {
+			continue
+		}
+		display := strings.ToLower(asset.DisplayName)
+		switch {
+		case display == query:
+			exact = append(exact, asset)
+		case strings.Contains(display, query):
+			contains = append(contains, asset)
+		case levenshtein(display, query) <= len(query)/4:
+			near = append(near, asset)
+		}
+	}
+	for _, candidates := range [][]*types.Asset{exact, contains, near} {
+		if len(candidates) > 0 {
+			return pick(name, candidates)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+func pick(query string, candidates []*types.Asset) (*types.Asset, error) {
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	var defaults []*types.Asset
+	for _, a := range candidates {
+		if isDefaultSymbol(a) {
+			defaults = append(defaults, a)
+		}
+	}
+	if len(defaults) == 1 {
+		return defaults[0], nil
+	}
+	if len(defaults) > 1 {
+		candidates = defaults
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Priority > candidates[j].Priority })
+	if candidates[0].Priority > candidates[1].Priority {
+		return candidates[0], nil
+	}
+	err := &AmbiguousError{Query: query}
+	for _, a := range candidates {
+		err.Candidates = append(err.Candidates, *a)
+	}
+	return nil, err
+}
+
+func isDefaultSymbol(a *types.Asset) bool {
+	if a.DefaultSymbol {
+		return true
+	}
+	for _, tag := range a.Tags {
+		if tag == "default_symbol" {
+			return true
+		}
+	}
+	return false
+}
+
+func exclusionReason(a *types.Asset) string {
+	switch {
+	case a.Blacklisted:
+		return "blacklisted"
+	case a.Deprecated:
+		return "deprecated"
+	default:
+		return "community"
+	}
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
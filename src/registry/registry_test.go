@@ -0,0 +1,82 @@
+package registry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var testAssets = []types.Asset{
+	{ContractAddress: "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c", Symbol: "TON", DisplayName: "TON", Kind: types.AssetKindTon, DefaultSymbol: true},
+	{ContractAddress: "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs", Symbol: "USDT", DisplayName: "Tether USD", Tags: []string{"default_symbol"}},
+	{ContractAddress: "EQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwiuA", Symbol: "USDT", DisplayName: "jUSDT", Priority: 5},
+	{ContractAddress: "EQCM3B12QK1e4yZSf8GtBRT0aLMNyEsBc_DhVfRRtOEffLez", Symbol: "SCAM", DisplayName: "Scam Coin", Blacklisted: true},
+	{ContractAddress: "EQAvlWFDxGF2lXm67y4yzC17wYKD9A0guwPkMs1gOsM__NOT", Symbol: "NOT", DisplayName: "Notcoin", Priority: 10},
+	{ContractAddress: "EQDAJK0GZ2ZhJlHcuNFrGDSyg70s0OZPFCiy29CNkMRomGFZ", Symbol: "NOT", DisplayName: "Not Notcoin", Priority: 10, Community: true},
+}
+
+func TestResolve(t *testing.T) {
+	r := NewAssetRegistry(testAssets, DefaultPolicy)
+
+	t.Run("should resolve addresses in any form", func(t *testing.T) {
+		asset, err := r.Resolve("0:b113a994b5024a16719f69139328eb759596c38a25f59028b146fecdc3621dfe")
+		assert.NoError(t, err)
+		assert.Equal(t, "Tether USD", asset.DisplayName)
+
+		asset, err = r.Resolve("UQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_p0p")
+		assert.NoError(t, err)
+		assert.Equal(t, "Tether USD", asset.DisplayName)
+	})
+
+	t.Run("should prefer the default symbol", func(t *testing.T) {
+		asset, err := r.Resolve("usdt")
+		assert.NoError(t, err)
+		assert.Equal(t, "Tether USD", asset.DisplayName)
+	})
+
+	t.Run("should report ambiguous symbols", func(t *testing.T) {
+		_, err := r.Resolve("NOT")
+		var ambiguous *AmbiguousError
+		assert.True(t, errors.As(err, &ambiguous))
+		assert.Len(t, ambiguous.Candidates, 2)
+	})
+
+	t.Run("should resolve fuzzy display names", func(t *testing.T) {
+		asset, err := r.Resolve("tether")
+		assert.NoError(t, err)
+		assert.Equal(t, "USDT", asset.Symbol)
+
+		asset, err = r.Resolve("Tetehr USD")
+		assert.NoError(t, err)
+		assert.Equal(t, "USDT", asset.Symbol)
+	})
+
+	t.Run("should exclude blacklisted assets", func(t *testing.T) {
+		_, err := r.Resolve("SCAM")
+		assert.ErrorIs(t, err, ErrExcluded)
+		assert.ErrorContains(t, err, "blacklisted")
+
+		lookalike := types.Asset{ContractAddress: "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE", Symbol: "SCM", DisplayName: "Scam"}
+		_, err = NewAssetRegistry(append([]types.Asset{lookalike}, testAssets...), DefaultPolicy).Resolve("SCAM")
+		assert.ErrorIs(t, err, ErrExcluded, "an excluded symbol does not fall through to display names")
+
+		_, err = r.Resolve("EQCM3B12QK1e4yZSf8GtBRT0aLMNyEsBc_DhVfRRtOEffLez")
+		assert.ErrorIs(t, err, ErrExcluded)
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	r := NewAssetRegistry(testAssets, Policy{AllowBlacklisted: true, ExcludeCommunity: true})
+
+	asset, err := r.Resolve("SCAM")
+	assert.NoError(t, err)
+	assert.Equal(t, "Scam Coin", asset.DisplayName)
+
+	asset, err = r.Resolve("NOT")
+	assert.NoError(t, err)
+	assert.Equal(t, "Notcoin", asset.DisplayName)
+
+	assert.Len(t, r.Assets(), 5)
+}
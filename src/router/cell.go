@@ -0,0 +1,239 @@
+package router
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+const (
+	maxCellBits = 1023
+	maxCellRefs = 4
+)
+
+var errCellOverflow = errors.New("cell overflow")
+
+// Cell is a TON cell: up to 1023 bits of data and up to 4 references to other
+// cells. It is only as much of a cell as message bodies need; exotic cells
+// and hashes are not supported.
+type Cell struct {
+	data []byte
+	bits int
+	refs []*Cell
+}
+
+// Bits returns the number of data bits in the cell.
+func (c *Cell) Bits() int { return c.bits }
+
+// Refs returns the cells the cell references.
+func (c *Cell) Refs() []*Cell { return c.refs }
+
+// BOC serializes the cell and the cells it references to a bag of cells with
+// a CRC32-C checksum, the form wallets and TON Connect accept payloads in.
+func (c *Cell) BOC() []byte {
+	// Reverse postorder puts every cell before the cells it references, and
+	// the root first.
+	var cells []*Cell
+	visited := map[*Cell]bool{}
+	var walk func(*Cell)
+	walk = func(c *Cell) {
+		if visited[c] {
+			return
+		}
+		visited[c] = true
+		for _, r := range c.refs {
+			walk(r)
+		}
+		cells = append(cells, c)
+	}
+	walk(c)
+	slices.Reverse(cells)
+	index := make(map[*Cell]int, len(cells))
+	for i, c := range cells {
+		index[c] = i
+	}
+
+	sizeBytes := bytesFor(uint64(len(cells)))
+	var body []byte
+	for _, c := range cells {
+		body = append(body, byte(len(c.refs)), byte(c.bits/8+(c.bits+7)/8))
+		body = append(body, c.padded()...)
+		for _, r := range c.refs {
+			body = appendUint(body, uint64(index[r]), sizeBytes)
+		}
+	}
+	offBytes := bytesFor(uint64(len(body)))
+
+	out := []byte{0xb5, 0xee, 0x9c, 0x72, 0x40 | byte(sizeBytes), byte(offBytes)}
+	out = appendUint(out, uint64(len(cells)), sizeBytes) // cells
+	out = appendUint(out, 1, sizeBytes)                  // roots
+	out = appendUint(out, 0, sizeBytes)                  // absent
+	out = appendUint(out, uint64(len(body)), offBytes)
+	out = appendUint(out, 0, sizeBytes) // root index
+	out = append(out, body...)
+	return binary.LittleEndian.AppendUint32(out, crc32.Checksum(out, crc32.MakeTable(crc32.Castagnoli)))
+}
+
+// Base64 returns the BOC of the cell in standard base64.
+func (c *Cell) Base64() string {
+	return base64.StdEncoding.EncodeToString(c.BOC())
+}
+
+// padded returns the cell's data with its last byte completed by a 1 bit and
+// zeros, as BOCs store incomplete bytes.
+func (c *Cell) padded() []byte {
+	data := append([]byte(nil), c.data...)
+	if c.bits%8 != 0 {
+		data[len(data)-1] |= 1 << (7 - c.bits%8)
+	}
+	return data
+}
+
+// Builder builds a Cell. The first error it meets is kept and returned by
+// Cell; later stores are ignored.
+type Builder struct {
+	cell Cell
+	err  error
+}
+
+// NewBuilder returns an empty builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Bit stores a single bit.
+func (b *Builder) Bit(v bool) *Builder {
+	if v {
+		return b.Uint(1, 1)
+	}
+	return b.Uint(0, 1)
+}
+
+// Uint stores v as an unsigned integer of n bits, n at most 64.
+func (b *Builder) Uint(v uint64, n int) *Builder {
+	if n < 64 && v>>n != 0 {
+		return b.fail(fmt.Errorf("%d does not fit in %d bits", v, n))
+	}
+	return b.BigUint(new(big.Int).SetUint64(v), n)
+}
+
+// BigUint stores v as an unsigned integer of n bits.
+func (b *Builder) BigUint(v *big.Int, n int) *Builder {
+	if v.Sign() < 0 || v.BitLen() > n {
+		return b.fail(fmt.Errorf("%s does not fit in %d bits", v, n))
+	}
+	if b.err == nil && b.cell.bits+n > maxCellBits {
+		return b.fail(errCellOverflow)
+	}
+	for i := n - 1; i >= 0 && b.err == nil; i-- {
+		if b.cell.bits%8 == 0 {
+			b.cell.data = append(b.cell.data, 0)
+		}
+		if v.Bit(i) == 1 {
+			b.cell.data[len(b.cell.data)-1] |= 1 << (7 - b.cell.bits%8)
+		}
+		b.cell.bits++
+	}
+	return b
+}
+
+// Coins stores v as VarUInteger 16, the encoding of nanoton and jetton
+// amounts.
+func (b *Builder) Coins(v *big.Int) *Builder {
+	if v == nil {
+		v = new(big.Int)
+	}
+	n := (v.BitLen() + 7) / 8
+	if v.Sign() < 0 || n > 15 {
+		return b.fail(fmt.Errorf("invalid coins amount %s", v))
+	}
+	return b.Uint(uint64(n), 4).BigUint(v, n*8)
+}
+
+// Address stores address as a MsgAddressInt, or addr_none if it is empty.
+func (b *Builder) Address(address string) *Builder {
+	if address == "" {
+		return b.Uint(0, 2)
+	}
+	workchain, hash, err := parseAddress(address)
+	if err != nil {
+		return b.fail(err)
+	}
+	// addr_std$10 anycast:(Maybe Anycast) workchain_id:int8 address:bits256
+	return b.Uint(0b100, 3).Uint(uint64(uint8(workchain)), 8).BigUint(new(big.Int).SetBytes(hash), 256)
+}
+
+// Ref adds a reference to c.
+func (b *Builder) Ref(c *Cell) *Builder {
+	if b.err == nil && len(b.cell.refs) == maxCellRefs {
+		return b.fail(errCellOverflow)
+	}
+	if b.err == nil {
+		b.cell.refs = append(b.cell.refs, c)
+	}
+	return b
+}
+
+// MaybeRef stores a Maybe ^Cell: a 0 bit if c is nil, else a 1 bit and a
+// reference to c.
+func (b *Builder) MaybeRef(c *Cell) *Builder {
+	if c == nil {
+		return b.Bit(false)
+	}
+	return b.Bit(true).Ref(c)
+}
+
+// Cell returns the built cell.
+func (b *Builder) Cell() (*Cell, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &Cell{data: slices.Clone(b.cell.data), bits: b.cell.bits, refs: slices.Clone(b.cell.refs)}, nil
+}
+
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// parseAddress returns the workchain and account id of a TON address.
+func parseAddress(address string) (int8, []byte, error) {
+	raw, err := utils.NormalizeAddress(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	wc, hash, _ := strings.Cut(raw, ":")
+	workchain, err := strconv.ParseInt(wc, 10, 8)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid workchain in address %q", address)
+	}
+	accountID, _ := hex.DecodeString(hash)
+	return int8(workchain), accountID, nil
+}
+
+// bytesFor returns the number of bytes needed to hold n, at least 1.
+func bytesFor(n uint64) int {
+	size := 1
+	for n >= 1<<(8*size) {
+		size++
+	}
+	return size
+}
+
+func appendUint(b []byte, v uint64, size int) []byte {
+	for i := size - 1; i >= 0; i-- {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
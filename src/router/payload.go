@@ -0,0 +1,168 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Operation codes of the message bodies built here.
+const (
+	OpJettonTransfer = 0x0f8a7ea5
+	OpSwapV1         = 0x25938561
+	OpSwapV2         = 0x6664de2a
+)
+
+// DefaultReferralValue is the referral fee of v2 swaps that set none, in
+// basis points.
+const DefaultReferralValue = 10
+
+// DefaultDeadline is how long after it is built a v2 swap may execute, if it
+// sets no deadline.
+const DefaultDeadline = 15 * time.Minute
+
+// SwapParams describes a swap to the router that Router names.
+type SwapParams struct {
+	Router string
+	// AskJettonWallet is the router's wallet of the asked jetton, as in
+	// types.SwapSimulationResponse.AskJettonWallet.
+	AskJettonWallet string
+	// MinAskUnits is the least the swap may return; if it would return
+	// less, the offer is refunded.
+	MinAskUnits *big.Int
+	// Receiver receives the asked asset. v2 routers also send refunds and
+	// excess gas to it.
+	Receiver string
+	// Referral, if set, receives the referral fee.
+	Referral string
+	// ReferralValue is the referral fee of v2 swaps in basis points, up to
+	// 100. Zero uses DefaultReferralValue; v1 routers take the pool's fee.
+	ReferralValue int
+	// Deadline is when a v2 swap stops being executed and is refunded.
+	// Zero is DefaultDeadline from now. v1 swaps have no deadline.
+	Deadline time.Time
+}
+
+// payloadBuilder builds the forward payload of a jetton transfer to a
+// router, which asks it for a swap.
+type payloadBuilder func(p SwapParams, now time.Time) (*Cell, error)
+
+var payloadBuilders = map[Version]payloadBuilder{
+	V1: swapPayloadV1,
+	V2: swapPayloadV2,
+}
+
+// SwapPayload builds the payload that asks the router of p for a swap, in
+// the format of its version. It is the forward payload of the transfer of
+// the offered jetton to the router, see TransferBody.
+func (r *Registry) SwapPayload(p SwapParams) (*Cell, error) {
+	info, err := r.Lookup(p.Router)
+	if err != nil {
+		return nil, err
+	}
+	if p.AskJettonWallet == "" || p.Receiver == "" {
+		return nil, errors.New("router: ask jetton wallet and receiver are required")
+	}
+	if p.MinAskUnits == nil || p.MinAskUnits.Sign() < 0 {
+		return nil, errors.New("router: min ask units must not be negative")
+	}
+	payload, err := payloadBuilders[info.Version](p, r.now())
+	if err != nil {
+		return nil, fmt.Errorf("router: %s swap payload: %w", info.Version, err)
+	}
+	return payload, nil
+}
+
+// swap#25938561 token_wallet1:MsgAddress min_out:Coins to_address:MsgAddress
+// referral_address:(Maybe MsgAddress)
+func swapPayloadV1(p SwapParams, _ time.Time) (*Cell, error) {
+	b := NewBuilder().
+		Uint(OpSwapV1, 32).
+		Address(p.AskJettonWallet).
+		Coins(p.MinAskUnits).
+		Address(p.Receiver).
+		Bit(p.Referral != "")
+	if p.Referral != "" {
+		b.Address(p.Referral)
+	}
+	return b.Cell()
+}
+
+// swap#6664de2a other_token_wallet:MsgAddress refund_address:MsgAddress
+// excesses_address:MsgAddress deadline:uint64 ^[min_out:Coins
+// receiver:MsgAddress fwd_gas:Coins custom_payload:(Maybe ^Cell)
+// refund_fwd_gas:Coins refund_payload:(Maybe ^Cell) ref_fee:uint16
+// ref_address:MsgAddress]
+func swapPayloadV2(p SwapParams, now time.Time) (*Cell, error) {
+	referralValue := p.ReferralValue
+	if referralValue == 0 {
+		referralValue = DefaultReferralValue
+	}
+	if referralValue < 0 || referralValue > 100 {
+		return nil, fmt.Errorf("referral value %d is not from 0 to 100", referralValue)
+	}
+	deadline := p.Deadline
+	if deadline.IsZero() {
+		deadline = now.Add(DefaultDeadline)
+	}
+	params, err := NewBuilder().
+		Coins(p.MinAskUnits).
+		Address(p.Receiver).
+		Coins(nil).
+		MaybeRef(nil).
+		Coins(nil).
+		MaybeRef(nil).
+		Uint(uint64(referralValue), 16).
+		Address(p.Referral).
+		Cell()
+	if err != nil {
+		return nil, err
+	}
+	return NewBuilder().
+		Uint(OpSwapV2, 32).
+		Address(p.AskJettonWallet).
+		Address(p.Receiver).
+		Address(p.Receiver).
+		Uint(uint64(deadline.Unix()), 64).
+		Ref(params).
+		Cell()
+}
+
+// TransferParams describes a jetton transfer.
+type TransferParams struct {
+	QueryID uint64
+	Amount  *big.Int
+	// Destination is the owner the jettons are sent to, such as a router.
+	Destination string
+	// ResponseDestination receives the excess gas.
+	ResponseDestination string
+	// ForwardTonAmount is the nanotons sent on to Destination with
+	// ForwardPayload; swaps need enough to pay for the router's gas.
+	ForwardTonAmount *big.Int
+	ForwardPayload   *Cell
+}
+
+// TransferBody builds the body of a TEP-74 jetton transfer, to be sent to the
+// sender's jetton wallet. Its QueryID is the query id the swap status is
+// looked up by.
+//
+// transfer#0f8a7ea5 query_id:uint64 amount:Coins destination:MsgAddress
+// response_destination:MsgAddress custom_payload:(Maybe ^Cell)
+// forward_ton_amount:Coins forward_payload:(Either Cell ^Cell)
+func TransferBody(p TransferParams) (*Cell, error) {
+	b := NewBuilder().
+		Uint(OpJettonTransfer, 32).
+		Uint(p.QueryID, 64).
+		Coins(p.Amount).
+		Address(p.Destination).
+		Address(p.ResponseDestination).
+		MaybeRef(nil).
+		Coins(p.ForwardTonAmount).
+		MaybeRef(p.ForwardPayload)
+	body, err := b.Cell()
+	if err != nil {
+		return nil, fmt.Errorf("router: transfer body: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,170 @@
+// Package router tells Ston.fi routers apart. Routers come in versions, v1 and
+// v2, and each router serves pools of one type, constant product or
+// stableswap; the versions take different swap payloads and the pool types
+// use different math. A Registry classifies router addresses, and simulates,
+// builds payloads for and polls the status of swaps with the implementation
+// of each.
+//
+//	r := router.Default()
+//	r.Register(router.Info{Address: v2Router, Version: router.V2, PoolType: router.StableSwap})
+//	quote, err := r.Simulate(ctx, c, pool, offerAddress, units, false)
+package router
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is a router version.
+type Version string
+
+const (
+	V1 Version = "v1"
+	V2 Version = "v2"
+)
+
+// PoolType is the type of the pools a router serves.
+type PoolType string
+
+const (
+	// ConstantProduct pools keep the product of their reserves constant.
+	ConstantProduct PoolType = "constant_product"
+	// StableSwap pools trade assets of about the same price along a flatter
+	// curve, set by an amplification coefficient.
+	StableSwap PoolType = "stableswap"
+)
+
+// V1Router is the address of the v1 router, which serves every v1 pool.
+const V1Router = "EQB3ncyBUTjZUA5EnFKR5_EnOMI9V1tTEAAPaiU71gc4TiUt"
+
+var ErrUnknownRouter = errors.New("unknown router")
+
+// Info describes a router.
+type Info struct {
+	Address  string   `json:"address" yaml:"address"`
+	Version  Version  `json:"version" yaml:"version"`
+	PoolType PoolType `json:"pool_type" yaml:"pool_type"`
+}
+
+func (i Info) validate() error {
+	if _, err := utils.NormalizeAddress(i.Address); err != nil {
+		return err
+	}
+	switch i.Version {
+	case V1:
+		if i.PoolType != ConstantProduct {
+			return fmt.Errorf("v1 router %s must serve %s pools", i.Address, ConstantProduct)
+		}
+	case V2:
+		if i.PoolType != ConstantProduct && i.PoolType != StableSwap {
+			return fmt.Errorf("router %s: unknown pool type %q", i.Address, i.PoolType)
+		}
+	default:
+		return fmt.Errorf("router %s: unknown version %q", i.Address, i.Version)
+	}
+	return nil
+}
+
+// Registry maps router addresses to their Info. It is safe for concurrent
+// use.
+type Registry struct {
+	mu      sync.RWMutex
+	routers map[string]Info
+	now     func() time.Time
+}
+
+// New returns a registry of routers.
+func New(routers ...Info) (*Registry, error) {
+	r := &Registry{routers: map[string]Info{}, now: time.Now}
+	for _, info := range routers {
+		if err := r.Register(info); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Default returns a registry of the v1 router. The API does not tell v2
+// routers apart, so they have to be registered, or loaded with LoadFile.
+func Default() *Registry {
+	r, _ := New(Info{Address: V1Router, Version: V1, PoolType: ConstantProduct})
+	return r
+}
+
+// Register adds a router, or replaces the one at the same address.
+func (r *Registry) Register(info Info) error {
+	if err := info.validate(); err != nil {
+		return fmt.Errorf("router: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routers[utils.AddressKey(info.Address)] = info
+	return nil
+}
+
+// Lookup returns the router at address, in any of its spellings.
+func (r *Registry) Lookup(address string) (Info, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	info, ok := r.routers[utils.AddressKey(address)]
+	if !ok {
+		return Info{}, fmt.Errorf("router: %w: %s", ErrUnknownRouter, address)
+	}
+	return info, nil
+}
+
+// Classify returns the router of pool.
+func (r *Registry) Classify(pool types.Pool) (Info, error) {
+	return r.Lookup(pool.RouterAddress)
+}
+
+// Routers returns the registered routers, by address.
+func (r *Registry) Routers() []Info {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	routers := make([]Info, 0, len(r.routers))
+	for _, info := range r.routers {
+		routers = append(routers, info)
+	}
+	sort.Slice(routers, func(i, j int) bool { return routers[i].Address < routers[j].Address })
+	return routers
+}
+
+// Load registers the routers of a YAML list of routers, with the keys
+// address, version and pool_type. Either every router is registered, or none.
+func (r *Registry) Load(reader io.Reader) error {
+	var routers []Info
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&routers); err != nil && err != io.EOF {
+		return fmt.Errorf("router: decoding routers: %w", err)
+	}
+	for _, info := range routers {
+		if err := info.validate(); err != nil {
+			return fmt.Errorf("router: %w", err)
+		}
+	}
+	for _, info := range routers {
+		r.Register(info)
+	}
+	return nil
+}
+
+// LoadFile registers the routers listed in the YAML file at path.
+func (r *Registry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("router: %w", err)
+	}
+	defer f.Close()
+	return r.Load(f)
+}
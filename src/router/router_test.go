@@ -0,0 +1,213 @@
+package router
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/amm"
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	v2Router    = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	wallet      = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+)
+
+func testRegistry(t *testing.T) *Registry {
+	r := Default()
+	assert.NoError(t, r.Register(Info{Address: v2Router, Version: V2, PoolType: StableSwap}))
+	r.now = func() time.Time { return time.Unix(1700000000, 0) }
+	return r
+}
+
+func TestRegistry(t *testing.T) {
+	r := testRegistry(t)
+
+	info, err := r.Lookup("0:779dcc815138d9500e449c5291e7f12738c23d575b5310000f6a253bd607384e")
+	assert.NoError(t, err)
+	assert.Equal(t, V1, info.Version)
+	assert.Equal(t, ConstantProduct, info.PoolType)
+
+	info, err = r.Classify(types.Pool{RouterAddress: v2Router})
+	assert.NoError(t, err)
+	assert.Equal(t, StableSwap, info.PoolType)
+
+	_, err = r.Lookup(wallet)
+	assert.ErrorIs(t, err, ErrUnknownRouter)
+	assert.Error(t, r.Register(Info{Address: wallet, Version: V1, PoolType: StableSwap}))
+	assert.Error(t, r.Register(Info{Address: wallet, Version: "v3", PoolType: ConstantProduct}))
+
+	err = r.Load(strings.NewReader("- address: " + wallet + "\n  version: v2\n  pool_type: constant_product\n- address: x\n  version: v2\n  pool_type: stableswap\n"))
+	assert.Error(t, err)
+	_, err = r.Lookup(wallet)
+	assert.ErrorIs(t, err, ErrUnknownRouter, "a failed load registers nothing")
+
+	assert.NoError(t, r.Load(strings.NewReader("- address: "+wallet+"\n  version: v2\n  pool_type: constant_product\n")))
+	info, err = r.Lookup(wallet)
+	assert.NoError(t, err)
+	assert.Equal(t, Info{Address: wallet, Version: V2, PoolType: ConstantProduct}, info)
+	assert.Len(t, r.Routers(), 3)
+}
+
+func TestBOC(t *testing.T) {
+	empty, err := NewBuilder().Cell()
+	assert.NoError(t, err)
+	assert.Equal(t, "te6cckEBAQEAAgAAAEysuc0=", empty.Base64())
+
+	leaf, _ := NewBuilder().Uint(0b101, 3).Cell()
+	shared, _ := NewBuilder().Ref(leaf).Cell()
+	root, err := NewBuilder().Uint(0xff, 8).Ref(leaf).Ref(shared).Cell()
+	assert.NoError(t, err)
+	boc := root.BOC()
+	assert.Equal(t, byte(3), boc[6], "a cell referenced twice is stored once")
+	// root: 2 refs, 1 byte; shared: 1 ref; leaf: 3 bits padded to 0b1011_0000
+	assert.Equal(t, []byte{2, 2, 0xff, 2, 1, 1, 0, 2, 0, 1, 0xb0}, boc[11:len(boc)-4])
+
+	_, err = NewBuilder().Uint(8, 3).Cell()
+	assert.Error(t, err)
+	_, err = NewBuilder().BigUint(new(big.Int), 1024).Cell()
+	assert.ErrorIs(t, err, errCellOverflow)
+}
+
+func TestSwapPayload(t *testing.T) {
+	r := testRegistry(t)
+	params := SwapParams{
+		Router:          V1Router,
+		AskJettonWallet: usdtAddress,
+		MinAskUnits:     big.NewInt(1000),
+		Receiver:        wallet,
+	}
+
+	v1, err := r.SwapPayload(params)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(OpSwapV1), binary.BigEndian.Uint32(v1.data))
+	assert.Equal(t, 32+267+4+16+267+1, v1.Bits())
+	assert.Empty(t, v1.Refs())
+
+	params.Referral = tonAddress
+	v1, err = r.SwapPayload(params)
+	assert.NoError(t, err)
+	assert.Equal(t, 32+267+4+16+267+1+267, v1.Bits())
+
+	params.Router = v2Router
+	v2, err := r.SwapPayload(params)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(OpSwapV2), binary.BigEndian.Uint32(v2.data))
+	assert.Equal(t, 32+267*3+64, v2.Bits())
+	assert.Equal(t, uint64(1700000000+15*60), bitsAt(v2, 32+267*3, 64), "deadline")
+	if assert.Len(t, v2.Refs(), 1) {
+		assert.Equal(t, 4+16+267+4+1+4+1+16+267, v2.Refs()[0].Bits())
+	}
+
+	params.ReferralValue = 101
+	_, err = r.SwapPayload(params)
+	assert.Error(t, err)
+
+	params.Router = wallet
+	_, err = r.SwapPayload(params)
+	assert.ErrorIs(t, err, ErrUnknownRouter)
+
+	body, err := TransferBody(TransferParams{QueryID: 7, Amount: big.NewInt(1), Destination: V1Router, ResponseDestination: wallet, ForwardTonAmount: big.NewInt(1), ForwardPayload: v1})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(OpJettonTransfer), binary.BigEndian.Uint32(body.data))
+	assert.Equal(t, uint64(7), binary.BigEndian.Uint64(body.data[4:]))
+	assert.Equal(t, []*Cell{v1}, body.Refs())
+}
+
+// bitsAt reads n bits of c from offset as an unsigned integer.
+func bitsAt(c *Cell, offset, n int) uint64 {
+	var v uint64
+	for i := offset; i < offset+n; i++ {
+		v = v<<1 | uint64(c.data[i/8]>>(7-i%8)&1)
+	}
+	return v
+}
+
+func TestSimulate(t *testing.T) {
+	r := testRegistry(t)
+	v1Pool := types.Pool{
+		Address:       "EQCGScrZe1xbyWqWDvdI6mzP-GAcAWFv6ZXuaJOuSqemxku4",
+		RouterAddress: V1Router,
+		Token0Address: tonAddress,
+		Token1Address: usdtAddress,
+		Reserve0:      "1000000",
+		Reserve1:      "2000000",
+		LpFee:         "20",
+		ProtocolFee:   "10",
+		RefFee:        "10",
+	}
+	stablePool := v1Pool
+	stablePool.Address, stablePool.RouterAddress = "EQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwiuA", v2Router
+
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{
+		Assets: []types.Asset{{ContractAddress: tonAddress, Decimals: 9}, {ContractAddress: usdtAddress, Decimals: 6}},
+		Pools:  []types.Pool{stablePool},
+	})
+	defer s.Close()
+	c := s.Client()
+
+	q, err := r.Simulate(context.Background(), c, v1Pool, tonAddress, big.NewInt(1000), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "1993", q.AskUnits.String())
+	assert.Empty(t, s.Requests(), "constant product pools are simulated locally")
+
+	q, err = r.Simulate(context.Background(), c, stablePool, usdtAddress, big.NewInt(2000), false)
+	assert.NoError(t, err)
+	assert.Equal(t, "997", q.AskUnits.String())
+	assert.Len(t, s.Requests(), 1)
+
+	_, err = r.Simulate(context.Background(), c, stablePool, wallet, big.NewInt(1), false)
+	assert.ErrorIs(t, err, amm.ErrAssetNotInPool)
+
+	stablePool.RouterAddress = tonAddress
+	_, err = r.Simulate(context.Background(), c, stablePool, tonAddress, big.NewInt(1), false)
+	assert.ErrorIs(t, err, ErrUnknownRouter)
+}
+
+func TestWaitSwap(t *testing.T) {
+	var polls atomic.Int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		assert.Equal(t, "42", q.Get("query_id"))
+		if polls.Add(1) < 3 || q.Get("router_address") != V1Router {
+			json.NewEncoder(w).Encode(map[string]string{"@type": "NotFound"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"@type": "Found", "exit_code": "swap_ok", "tx_hash": "abc"})
+	}))
+	defer api.Close()
+	c := client.NewStonfiClientWithOptions(client.StonfiClientOptions{BaseURL: api.URL + "/v1"})
+	r := testRegistry(t)
+
+	_, err := r.WaitSwap(context.Background(), c, SwapRef{Router: V1Router, Owner: wallet, QueryID: 42}, 0)
+	assert.Error(t, err)
+	assert.Zero(t, polls.Load())
+
+	status, err := r.WaitSwap(context.Background(), c, SwapRef{Router: V1Router, Owner: wallet, QueryID: 42}, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, status.Succeeded())
+	assert.Equal(t, "abc", status.TxHash)
+	assert.Equal(t, int32(3), polls.Load())
+
+	deadline := r.now().Add(-ExpiryGrace - time.Second)
+	_, err = r.WaitSwap(context.Background(), c, SwapRef{Router: v2Router, Owner: wallet, QueryID: 42, Deadline: deadline}, time.Millisecond)
+	assert.ErrorIs(t, err, ErrSwapExpired)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = r.WaitSwap(ctx, c, SwapRef{Router: v2Router, Owner: wallet, QueryID: 42, Deadline: r.now()}, time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "v2 swaps are polled until ExpiryGrace after their deadline")
+}
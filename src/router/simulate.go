@@ -0,0 +1,74 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/itay747/go-stonfi/src/amm"
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// simulator quotes a swap of offerUnits of offerAddress through a pool.
+type simulator func(ctx context.Context, c *client.StonfiClient, pool types.Pool, offerAddress string, offerUnits *big.Int, withReferral bool) (*amm.Quote, error)
+
+var simulators = map[PoolType]simulator{
+	ConstantProduct: simulateConstantProduct,
+	StableSwap:      simulateStableSwap,
+}
+
+// Simulate quotes a swap of offerUnits of offerAddress through pool, with the
+// math of the pool's type. Constant product pools are simulated locally;
+// stableswap pools are simulated by the API, as the pool data lacks their
+// amplification coefficient.
+func (r *Registry) Simulate(ctx context.Context, c *client.StonfiClient, pool types.Pool, offerAddress string, offerUnits *big.Int, withReferral bool) (*amm.Quote, error) {
+	info, err := r.Classify(pool)
+	if err != nil {
+		return nil, err
+	}
+	return simulators[info.PoolType](ctx, c, pool, offerAddress, offerUnits, withReferral)
+}
+
+func simulateConstantProduct(_ context.Context, _ *client.StonfiClient, pool types.Pool, offerAddress string, offerUnits *big.Int, withReferral bool) (*amm.Quote, error) {
+	return amm.SimulateSwap(pool, offerAddress, offerUnits, withReferral)
+}
+
+// simulateStableSwap asks the API for a quote of the pair. The API picks the
+// pool of the pair itself, so the quote is rejected if it is for another
+// router. Its fees are reported as a whole, as ProtocolFeeUnits.
+func simulateStableSwap(ctx context.Context, c *client.StonfiClient, pool types.Pool, offerAddress string, offerUnits *big.Int, withReferral bool) (*amm.Quote, error) {
+	if withReferral {
+		return nil, fmt.Errorf("router: stableswap simulation with a referral is not supported")
+	}
+	if _, err := amm.Orient(pool, offerAddress); err != nil {
+		return nil, err
+	}
+	askAddress := pool.Token0Address
+	if utils.AddressKey(offerAddress) == utils.AddressKey(pool.Token0Address) {
+		askAddress = pool.Token1Address
+	}
+	response, err := c.SimulateSwap(ctx, offerAddress, askAddress, offerUnits.String(), "0")
+	if err != nil {
+		return nil, fmt.Errorf("router: simulating stableswap: %w", err)
+	}
+	if utils.AddressKey(response.RouterAddress) != utils.AddressKey(pool.RouterAddress) {
+		return nil, fmt.Errorf("router: simulation used router %s, not %s", response.RouterAddress, pool.RouterAddress)
+	}
+	q := &amm.Quote{OfferUnits: new(big.Int).Set(offerUnits), LpFeeUnits: new(big.Int), RefFeeUnits: new(big.Int)}
+	var ok bool
+	if q.AskUnits, ok = new(big.Int).SetString(response.AskUnits, 10); !ok {
+		return nil, fmt.Errorf("router: invalid ask_units %q", response.AskUnits)
+	}
+	if q.ProtocolFeeUnits, ok = new(big.Int).SetString(response.FeeUnits, 10); !ok {
+		return nil, fmt.Errorf("router: invalid fee_units %q", response.FeeUnits)
+	}
+	if response.PriceImpact != "" {
+		if q.PriceImpact, err = strconv.ParseFloat(response.PriceImpact, 64); err != nil {
+			return nil, fmt.Errorf("router: invalid price_impact %q", response.PriceImpact)
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,117 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/openapi"
+)
+
+// ExpiryGrace is how long after its deadline WaitSwap keeps looking for a v2
+// swap, for the transactions and the API's index to catch up.
+const ExpiryGrace = 2 * time.Minute
+
+var ErrSwapExpired = errors.New("swap not found after its deadline")
+
+// SwapRef identifies a swap sent to a router.
+type SwapRef struct {
+	Router string
+	// Owner is the wallet that sent the offered asset.
+	Owner string
+	// QueryID is the query id of the transfer that carried the swap.
+	QueryID uint64
+	// Deadline is the deadline of a v2 swap; zero if unknown.
+	Deadline time.Time
+}
+
+// SwapStatus is what the API knows of a swap.
+type SwapStatus struct {
+	// Found is false until the router has processed the swap.
+	Found bool
+	// ExitCode is the router's outcome of the swap, such as swap_ok.
+	ExitCode      string
+	Coins         string
+	LogicalTime   string
+	TxHash        string
+	BalanceDeltas string
+}
+
+// Succeeded reports whether the swap was executed rather than refunded.
+func (s SwapStatus) Succeeded() bool {
+	return s.Found && strings.HasPrefix(s.ExitCode, "swap_ok")
+}
+
+// expiries returns when a swap to a router of each version can no longer
+// appear, or the zero time if it can appear at any time.
+var expiries = map[Version]func(SwapRef) time.Time{
+	V1: func(SwapRef) time.Time { return time.Time{} },
+	V2: func(ref SwapRef) time.Time {
+		if ref.Deadline.IsZero() {
+			return time.Time{}
+		}
+		return ref.Deadline.Add(ExpiryGrace)
+	},
+}
+
+// SwapStatus fetches the status of a swap.
+func (r *Registry) SwapStatus(ctx context.Context, c *client.StonfiClient, ref SwapRef) (SwapStatus, error) {
+	if _, err := r.Lookup(ref.Router); err != nil {
+		return SwapStatus{}, err
+	}
+	response, err := c.API().GetSwapStatus(ctx, openapi.GetSwapStatusParams{
+		RouterAddress: ref.Router,
+		OwnerAddress:  ref.Owner,
+		QueryID:       strconv.FormatUint(ref.QueryID, 10),
+	})
+	if err != nil {
+		return SwapStatus{}, fmt.Errorf("router: swap status: %w", err)
+	}
+	status := SwapStatus{Found: response.Type == "Found"}
+	for _, f := range []struct {
+		dst *string
+		src *string
+	}{{&status.ExitCode, response.ExitCode}, {&status.Coins, response.Coins}, {&status.LogicalTime, response.LogicalTime},
+		{&status.TxHash, response.TxHash}, {&status.BalanceDeltas, response.BalanceDeltas}} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	return status, nil
+}
+
+// WaitSwap polls the status of a swap every interval until the router has
+// processed it. A v2 swap with a deadline is given up on with ErrSwapExpired
+// once it is not found ExpiryGrace after its deadline; v1 swaps are polled
+// until ctx is done. The interval must be positive.
+func (r *Registry) WaitSwap(ctx context.Context, c *client.StonfiClient, ref SwapRef, interval time.Duration) (SwapStatus, error) {
+	if interval <= 0 {
+		return SwapStatus{}, errors.New("router: poll interval must be positive")
+	}
+	info, err := r.Lookup(ref.Router)
+	if err != nil {
+		return SwapStatus{}, err
+	}
+	expiry := expiries[info.Version](ref)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		polled := r.now()
+		status, err := r.SwapStatus(ctx, c, ref)
+		if err != nil || status.Found {
+			return status, err
+		}
+		if !expiry.IsZero() && polled.After(expiry) {
+			return status, fmt.Errorf("router: %w (query id %d)", ErrSwapExpired, ref.QueryID)
+		}
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
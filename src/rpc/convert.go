@@ -0,0 +1,296 @@
+package rpc
+
+import (
+	"github.com/itay747/go-stonfi/src/rpc/stonfipb"
+	"github.com/itay747/go-stonfi/src/types"
+)
+
+// AssetProto converts an asset to its message.
+func AssetProto(a types.Asset) *stonfipb.Asset {
+	return &stonfipb.Asset{
+		ContractAddress:    a.ContractAddress,
+		Symbol:             a.Symbol,
+		DisplayName:        a.DisplayName,
+		DexPriceUsd:        a.DexPriceUsd,
+		ImageUrl:           a.ImageURL,
+		DexUsdPrice:        a.DexUsdPrice,
+		Kind:               string(a.Kind),
+		ThirdPartyPriceUsd: a.ThirdPartyPriceUsd,
+		ThirdPartyUsdPrice: a.ThirdPartyUsdPrice,
+		Tags:               a.Tags,
+		Decimals:           int32(a.Decimals),
+		Priority:           int32(a.Priority),
+		DefaultSymbol:      a.DefaultSymbol,
+		Taxable:            a.Taxable,
+		Blacklisted:        a.Blacklisted,
+		Community:          a.Community,
+		Deprecated:         a.Deprecated,
+	}
+}
+
+// AssetOf converts a message back to an asset.
+func AssetOf(m *stonfipb.Asset) types.Asset {
+	return types.Asset{
+		ContractAddress:    m.GetContractAddress(),
+		Symbol:             m.GetSymbol(),
+		DisplayName:        m.GetDisplayName(),
+		DexPriceUsd:        m.GetDexPriceUsd(),
+		ImageURL:           m.GetImageUrl(),
+		DexUsdPrice:        m.GetDexUsdPrice(),
+		Kind:               types.AssetKind(m.GetKind()),
+		ThirdPartyPriceUsd: m.GetThirdPartyPriceUsd(),
+		ThirdPartyUsdPrice: m.GetThirdPartyUsdPrice(),
+		Tags:               m.GetTags(),
+		Decimals:           int(m.GetDecimals()),
+		Priority:           int(m.GetPriority()),
+		DefaultSymbol:      m.GetDefaultSymbol(),
+		Taxable:            m.GetTaxable(),
+		Blacklisted:        m.GetBlacklisted(),
+		Community:          m.GetCommunity(),
+		Deprecated:         m.GetDeprecated(),
+	}
+}
+
+// PoolProto converts a pool to its message.
+func PoolProto(p types.Pool) *stonfipb.Pool {
+	return &stonfipb.Pool{
+		Address:                    p.Address,
+		RouterAddress:              p.RouterAddress,
+		Reserve0:                   p.Reserve0,
+		Reserve1:                   p.Reserve1,
+		Token0Address:              p.Token0Address,
+		Token1Address:              p.Token1Address,
+		LpTotalSupply:              p.LpTotalSupply,
+		LpTotalSupplyUsd:           p.LpTotalSupplyUsd,
+		LpFee:                      p.LpFee,
+		ProtocolFee:                p.ProtocolFee,
+		RefFee:                     p.RefFee,
+		ProtocolFeeAddress:         p.ProtocolFeeAddress,
+		CollectedToken0ProtocolFee: p.CollectedToken0ProtocolFee,
+		CollectedToken1ProtocolFee: p.CollectedToken1ProtocolFee,
+		LpPriceUsd:                 p.LpPriceUsd,
+		Apy_1D:                     p.Apy1D,
+		Apy_7D:                     p.Apy7D,
+		Apy_30D:                    p.Apy30D,
+		Deprecated:                 p.Deprecated,
+	}
+}
+
+// PoolOf converts a message back to a pool.
+func PoolOf(m *stonfipb.Pool) types.Pool {
+	return types.Pool{
+		Address:                    m.GetAddress(),
+		RouterAddress:              m.GetRouterAddress(),
+		Reserve0:                   m.GetReserve0(),
+		Reserve1:                   m.GetReserve1(),
+		Token0Address:              m.GetToken0Address(),
+		Token1Address:              m.GetToken1Address(),
+		LpTotalSupply:              m.GetLpTotalSupply(),
+		LpTotalSupplyUsd:           m.GetLpTotalSupplyUsd(),
+		LpFee:                      m.GetLpFee(),
+		ProtocolFee:                m.GetProtocolFee(),
+		RefFee:                     m.GetRefFee(),
+		ProtocolFeeAddress:         m.GetProtocolFeeAddress(),
+		CollectedToken0ProtocolFee: m.GetCollectedToken0ProtocolFee(),
+		CollectedToken1ProtocolFee: m.GetCollectedToken1ProtocolFee(),
+		LpPriceUsd:                 m.GetLpPriceUsd(),
+		Apy1D:                      m.GetApy_1D(),
+		Apy7D:                      m.GetApy_7D(),
+		Apy30D:                     m.GetApy_30D(),
+		Deprecated:                 m.GetDeprecated(),
+	}
+}
+
+// FarmProto converts a farm to its message.
+func FarmProto(f types.Farm) *stonfipb.Farm {
+	m := &stonfipb.Farm{
+		MinterAddress:      f.MinterAddress,
+		PoolAddress:        f.PoolAddress,
+		RewardTokenAddress: f.RewardTokenAddress,
+		Status:             f.Status,
+		MinStakeDurationS:  f.MinStakeDurationS,
+		LockedTotalLp:      f.LockedTotalLP,
+		LockedTotalLpUsd:   f.LockedTotalLPUSD,
+		Apy:                f.APY,
+	}
+	for _, n := range f.NftInfos {
+		nft := &stonfipb.FarmNftInfo{
+			Address:             n.Address,
+			Status:              n.Status,
+			CreateTimestamp:     n.CreateTimestamp,
+			MinUnstakeTimestamp: n.MinUnstakeTimestamp,
+			StakedTokens:        n.StakedTokens,
+			NonclaimedRewards:   n.NonclaimedRewards,
+		}
+		for _, r := range n.Rewards {
+			nft.Rewards = append(nft.Rewards, &stonfipb.FarmNftReward{Address: r.Address, Amount: r.Amount})
+		}
+		m.NftInfos = append(m.NftInfos, nft)
+	}
+	for _, r := range f.Rewards {
+		m.Rewards = append(m.Rewards, &stonfipb.FarmReward{
+			Address:          r.Address,
+			Status:           r.Status,
+			RemainingRewards: r.RemainingRewards,
+			RewardRate_24H:   r.RewardRate24H,
+		})
+	}
+	return m
+}
+
+// FarmOf converts a message back to a farm.
+func FarmOf(m *stonfipb.Farm) types.Farm {
+	f := types.Farm{
+		MinterAddress:      m.GetMinterAddress(),
+		PoolAddress:        m.GetPoolAddress(),
+		RewardTokenAddress: m.GetRewardTokenAddress(),
+		Status:             m.GetStatus(),
+		MinStakeDurationS:  m.GetMinStakeDurationS(),
+		LockedTotalLP:      m.GetLockedTotalLp(),
+		LockedTotalLPUSD:   m.GetLockedTotalLpUsd(),
+		APY:                m.GetApy(),
+	}
+	for _, n := range m.GetNftInfos() {
+		nft := types.FarmNftInfo{
+			Address:             n.GetAddress(),
+			Status:              n.GetStatus(),
+			CreateTimestamp:     n.GetCreateTimestamp(),
+			MinUnstakeTimestamp: n.GetMinUnstakeTimestamp(),
+			StakedTokens:        n.GetStakedTokens(),
+			NonclaimedRewards:   n.GetNonclaimedRewards(),
+		}
+		for _, r := range n.GetRewards() {
+			nft.Rewards = append(nft.Rewards, types.FarmNftReward{Address: r.GetAddress(), Amount: r.GetAmount()})
+		}
+		f.NftInfos = append(f.NftInfos, nft)
+	}
+	for _, r := range m.GetRewards() {
+		f.Rewards = append(f.Rewards, types.FarmReward{
+			Address:          r.GetAddress(),
+			Status:           r.GetStatus(),
+			RemainingRewards: r.GetRemainingRewards(),
+			RewardRate24H:    r.GetRewardRate_24H(),
+		})
+	}
+	return f
+}
+
+// OperationProto converts an operation and its assets to their message.
+func OperationProto(info types.OperationInfo) *stonfipb.OperationInfo {
+	op := info.Operation
+	return &stonfipb.OperationInfo{
+		Operation: &stonfipb.Operation{
+			ProtocolFeeAmount:        op.ProtocolFeeAmount,
+			FeeAssetAddress:          op.FeeAssetAddress,
+			RouterAddress:            op.RouterAddress,
+			Asset0Reserve:            op.Asset0Reserve,
+			PoolTxTimestamp:          op.PoolTxTimestamp,
+			DestinationWalletAddress: op.DestinationWalletAddress,
+			OperationType:            op.OperationType,
+			WalletTxHash:             op.WalletTxHash,
+			ExitCode:                 op.ExitCode,
+			Asset0Address:            op.Asset0Address,
+			Asset0Amount:             op.Asset0Amount,
+			Asset0Delta:              op.Asset0Delta,
+			WalletTxTimestamp:        op.WalletTxTimestamp,
+			WalletTxLt:               op.WalletTxLt,
+			LpTokenSupply:            op.LpTokenSupply,
+			Asset1Delta:              op.Asset1Delta,
+			Asset1Reserve:            op.Asset1Reserve,
+			LpTokenDelta:             op.LpTokenDelta,
+			Asset1Amount:             op.Asset1Amount,
+			PoolAddress:              op.PoolAddress,
+			LpFeeAmount:              op.LpFeeAmount,
+			PoolTxHash:               op.PoolTxHash,
+			ReferralFeeAmount:        op.ReferralFeeAmount,
+			ReferralAddress:          op.ReferralAddress,
+			WalletAddress:            op.WalletAddress,
+			Asset1Address:            op.Asset1Address,
+			PoolTxLt:                 op.PoolTxLt,
+			Success:                  op.Success,
+		},
+		Asset0Info: AssetProto(info.Asset0Info),
+		Asset1Info: AssetProto(info.Asset1Info),
+	}
+}
+
+// OperationOf converts a message back to an operation and its assets.
+func OperationOf(m *stonfipb.OperationInfo) types.OperationInfo {
+	op := m.GetOperation()
+	return types.OperationInfo{
+		Operation: types.Operation{
+			ProtocolFeeAmount:        op.GetProtocolFeeAmount(),
+			FeeAssetAddress:          op.GetFeeAssetAddress(),
+			RouterAddress:            op.GetRouterAddress(),
+			Asset0Reserve:            op.GetAsset0Reserve(),
+			PoolTxTimestamp:          op.GetPoolTxTimestamp(),
+			DestinationWalletAddress: op.GetDestinationWalletAddress(),
+			OperationType:            op.GetOperationType(),
+			WalletTxHash:             op.GetWalletTxHash(),
+			ExitCode:                 op.GetExitCode(),
+			Asset0Address:            op.GetAsset0Address(),
+			Asset0Amount:             op.GetAsset0Amount(),
+			Asset0Delta:              op.GetAsset0Delta(),
+			WalletTxTimestamp:        op.GetWalletTxTimestamp(),
+			WalletTxLt:               op.GetWalletTxLt(),
+			LpTokenSupply:            op.GetLpTokenSupply(),
+			Asset1Delta:              op.GetAsset1Delta(),
+			Asset1Reserve:            op.GetAsset1Reserve(),
+			LpTokenDelta:             op.GetLpTokenDelta(),
+			Asset1Amount:             op.GetAsset1Amount(),
+			PoolAddress:              op.GetPoolAddress(),
+			LpFeeAmount:              op.GetLpFeeAmount(),
+			PoolTxHash:               op.GetPoolTxHash(),
+			ReferralFeeAmount:        op.GetReferralFeeAmount(),
+			ReferralAddress:          op.GetReferralAddress(),
+			WalletAddress:            op.GetWalletAddress(),
+			Asset1Address:            op.GetAsset1Address(),
+			PoolTxLt:                 op.GetPoolTxLt(),
+			Success:                  op.GetSuccess(),
+		},
+		Asset0Info: AssetOf(m.GetAsset0Info()),
+		Asset1Info: AssetOf(m.GetAsset1Info()),
+	}
+}
+
+// SwapSimulationProto converts a swap simulation to its message.
+func SwapSimulationProto(s types.SwapSimulationResponse) *stonfipb.SwapSimulation {
+	return &stonfipb.SwapSimulation{
+		AskAddress:        s.AskAddress,
+		AskJettonWallet:   s.AskJettonWallet,
+		AskUnits:          s.AskUnits,
+		FeeAddress:        s.FeeAddress,
+		FeePercent:        s.FeePercent,
+		FeeUnits:          s.FeeUnits,
+		MinAskUnits:       s.MinAskUnits,
+		OfferAddress:      s.OfferAddress,
+		OfferJettonWallet: s.OfferJettonWallet,
+		OfferUnits:        s.OfferUnits,
+		PoolAddress:       s.PoolAddress,
+		PriceImpact:       s.PriceImpact,
+		RouterAddress:     s.RouterAddress,
+		SlippageTolerance: s.SlippageTolerance,
+		SwapRate:          s.SwapRate,
+	}
+}
+
+// SwapSimulationOf converts a message back to a swap simulation.
+func SwapSimulationOf(m *stonfipb.SwapSimulation) types.SwapSimulationResponse {
+	return types.SwapSimulationResponse{
+		AskAddress:        m.GetAskAddress(),
+		AskJettonWallet:   m.GetAskJettonWallet(),
+		AskUnits:          m.GetAskUnits(),
+		FeeAddress:        m.GetFeeAddress(),
+		FeePercent:        m.GetFeePercent(),
+		FeeUnits:          m.GetFeeUnits(),
+		MinAskUnits:       m.GetMinAskUnits(),
+		OfferAddress:      m.GetOfferAddress(),
+		OfferJettonWallet: m.GetOfferJettonWallet(),
+		OfferUnits:        m.GetOfferUnits(),
+		PoolAddress:       m.GetPoolAddress(),
+		PriceImpact:       m.GetPriceImpact(),
+		RouterAddress:     m.GetRouterAddress(),
+		SlippageTolerance: m.GetSlippageTolerance(),
+		SwapRate:          m.GetSwapRate(),
+	}
+}
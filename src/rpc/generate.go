@@ -0,0 +1,4 @@
+package rpc
+
+// Generating needs buf, protoc-gen-go and protoc-gen-go-grpc on the PATH.
+//go:generate buf generate --template buf.gen.yaml --path stonfi.proto
@@ -0,0 +1,347 @@
+// Package rpc serves Ston.fi DEX data over gRPC, from a StonfiClient. The
+// service is defined in stonfi.proto; stonfipb holds the generated messages
+// and Go client, and this package the server and the conversions between the
+// messages and the types package.
+//
+//	g := grpc.NewServer()
+//	rpc.New(client.NewStonfiClient(), rpc.Options{}).Register(g)
+//	g.Serve(listener)
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/rpc/stonfipb"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxInterval bounds the polling interval of streams, so that a poll of
+// operations never spans more than the API's 24 hour limit.
+const maxInterval = time.Hour
+
+// Options configures a Server.
+type Options struct {
+	// Interval is the polling interval of streams that ask for none.
+	// Defaults to 30 seconds.
+	Interval time.Duration
+	// MinInterval is the shortest interval a stream may ask for. Defaults
+	// to 5 seconds.
+	MinInterval time.Duration
+	// Overlap is how far before the previous poll each poll of operations
+	// starts, to pick up operations the API reports late. Defaults to 5
+	// minutes.
+	Overlap time.Duration
+}
+
+// Server implements stonfipb.DexServer.
+type Server struct {
+	stonfipb.UnimplementedDexServer
+	client *client.StonfiClient
+	opts   Options
+	now    func() time.Time
+}
+
+// New returns a server answering from c.
+func New(c *client.StonfiClient, opts Options) *Server {
+	if opts.MinInterval <= 0 {
+		opts.MinInterval = 5 * time.Second
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	if opts.Overlap <= 0 {
+		opts.Overlap = 5 * time.Minute
+	}
+	return &Server{client: c, opts: opts, now: time.Now}
+}
+
+// Register registers the server on g.
+func (s *Server) Register(g *grpc.Server) {
+	stonfipb.RegisterDexServer(g, s)
+}
+
+func (s *Server) GetAsset(ctx context.Context, req *stonfipb.GetAssetRequest) (*stonfipb.Asset, error) {
+	if req.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	response, err := call(ctx, func(ctx context.Context) (*types.AssetResponse, error) {
+		return s.client.GetAsset(ctx, req.GetAddress())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return AssetProto(response.Asset), nil
+}
+
+func (s *Server) ListAssets(ctx context.Context, req *stonfipb.ListAssetsRequest) (*stonfipb.ListAssetsResponse, error) {
+	response, err := call(ctx, s.client.GetAssets)
+	if err != nil {
+		return nil, err
+	}
+	m := &stonfipb.ListAssetsResponse{}
+	for _, a := range response.AssetList {
+		m.Assets = append(m.Assets, AssetProto(a))
+	}
+	return m, nil
+}
+
+func (s *Server) GetPool(ctx context.Context, req *stonfipb.GetPoolRequest) (*stonfipb.Pool, error) {
+	if req.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	response, err := call(ctx, func(ctx context.Context) (*types.PoolResponse, error) {
+		return s.client.GetPool(ctx, req.GetAddress())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return PoolProto(response.Pool), nil
+}
+
+func (s *Server) ListPools(ctx context.Context, req *stonfipb.ListPoolsRequest) (*stonfipb.ListPoolsResponse, error) {
+	response, err := call(ctx, s.client.GetPools)
+	if err != nil {
+		return nil, err
+	}
+	m := &stonfipb.ListPoolsResponse{}
+	for _, p := range response.PoolList {
+		if p.Deprecated && !req.GetIncludeDeprecated() {
+			continue
+		}
+		m.Pools = append(m.Pools, PoolProto(p))
+	}
+	return m, nil
+}
+
+func (s *Server) GetFarm(ctx context.Context, req *stonfipb.GetFarmRequest) (*stonfipb.Farm, error) {
+	if req.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+	response, err := call(ctx, func(ctx context.Context) (*types.FarmResponse, error) {
+		return s.client.GetFarm(ctx, req.GetAddress())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FarmProto(response.Farm), nil
+}
+
+func (s *Server) ListFarms(ctx context.Context, req *stonfipb.ListFarmsRequest) (*stonfipb.ListFarmsResponse, error) {
+	response, err := call(ctx, func(ctx context.Context) (*types.FarmListResponse, error) {
+		if req.GetPoolAddress() != "" {
+			return s.client.GetFarmsByPool(ctx, req.GetPoolAddress())
+		}
+		return s.client.GetFarms(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	m := &stonfipb.ListFarmsResponse{}
+	for _, f := range response.Farms {
+		m.Farms = append(m.Farms, FarmProto(f))
+	}
+	return m, nil
+}
+
+func (s *Server) SimulateSwap(ctx context.Context, req *stonfipb.SimulateSwapRequest) (*stonfipb.SwapSimulation, error) {
+	if req.GetOfferAddress() == "" || req.GetAskAddress() == "" || req.GetUnits() == "" {
+		return nil, status.Error(codes.InvalidArgument, "offer_address, ask_address and units are required")
+	}
+	slippage := req.GetSlippageTolerance()
+	if slippage == "" {
+		slippage = "0.001"
+	}
+	simulate := s.client.SimulateSwap
+	if req.GetReverse() {
+		simulate = s.client.SimulateReverseSwap
+	}
+	response, err := call(ctx, func(ctx context.Context) (*types.SwapSimulationResponse, error) {
+		return simulate(ctx, req.GetOfferAddress(), req.GetAskAddress(), req.GetUnits(), slippage)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return SwapSimulationProto(*response), nil
+}
+
+// WatchPools polls the pools every interval and sends those that changed
+// since the previous poll. A failed poll ends the stream with its status.
+func (s *Server) WatchPools(req *stonfipb.WatchPoolsRequest, stream stonfipb.Dex_WatchPoolsServer) error {
+	interval, err := s.interval(req.GetInterval())
+	if err != nil {
+		return err
+	}
+	watched := map[string]bool{}
+	for _, address := range req.GetAddresses() {
+		watched[utils.AddressKey(address)] = true
+	}
+	ctx := stream.Context()
+	var last map[string]types.Pool
+	return s.poll(ctx, interval, func() error {
+		response, err := call(ctx, s.client.GetPools)
+		if err != nil {
+			return err
+		}
+		detected := timestamppb.New(s.now())
+		current := map[string]types.Pool{}
+		for _, p := range response.PoolList {
+			key := utils.AddressKey(p.Address)
+			if len(watched) > 0 && !watched[key] {
+				continue
+			}
+			current[key] = p
+			update := &stonfipb.PoolUpdate{Pool: PoolProto(p), DetectedAt: detected}
+			if last == nil && !req.GetSendInitial() {
+				continue
+			}
+			if previous, ok := last[key]; ok {
+				if previous == p {
+					continue
+				}
+				update.Previous = PoolProto(previous)
+			}
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		}
+		last = current
+		return nil
+	})
+}
+
+// WatchOperations polls the operations every interval and sends the ones
+// matching the request that it has not sent yet. Operations the first poll
+// finds, from before the stream began, are not sent. A failed poll ends the
+// stream with its status.
+func (s *Server) WatchOperations(req *stonfipb.WatchOperationsRequest, stream stonfipb.Dex_WatchOperationsServer) error {
+	interval, err := s.interval(req.GetInterval())
+	if err != nil {
+		return err
+	}
+	pool, wallet := utils.AddressKey(req.GetPoolAddress()), utils.AddressKey(req.GetWalletAddress())
+	kinds := map[string]bool{}
+	for _, kind := range req.GetOperationTypes() {
+		kinds[kind] = true
+	}
+	ctx := stream.Context()
+	// seen holds the operations sent, or from before the stream, by pool
+	// transaction hash, with the time they are forgotten at.
+	seen := map[string]time.Time{}
+	var since time.Time
+	baseline := true
+	return s.poll(ctx, interval, func() error {
+		until := s.now().UTC().Truncate(time.Second)
+		from := until.Add(-s.opts.Overlap)
+		if !since.IsZero() {
+			from = since.Add(-s.opts.Overlap)
+		}
+		response, err := call(ctx, func(ctx context.Context) (*types.OperationsStatsResponse, error) {
+			return s.client.GetHistoricalSwaps(ctx, from, until)
+		})
+		if err != nil {
+			return err
+		}
+		for hash, forget := range seen {
+			if forget.Before(from) {
+				delete(seen, hash)
+			}
+		}
+		for _, info := range response.Operations {
+			op := info.Operation
+			if _, ok := seen[op.PoolTxHash]; ok {
+				continue
+			}
+			forget := until
+			if t, err := time.Parse(openapi.TimeLayout, op.PoolTxTimestamp); err == nil && t.After(until) {
+				forget = t
+			}
+			seen[op.PoolTxHash] = forget
+			if baseline ||
+				(pool != "" && utils.AddressKey(op.PoolAddress) != pool) ||
+				(wallet != "" && utils.AddressKey(op.WalletAddress) != wallet) ||
+				(len(kinds) > 0 && !kinds[op.OperationType]) {
+				continue
+			}
+			if err := stream.Send(OperationProto(info)); err != nil {
+				return err
+			}
+		}
+		since, baseline = until, false
+		return nil
+	})
+}
+
+// interval returns the polling interval a stream asks for, or the default.
+func (s *Server) interval(d *durationpb.Duration) (time.Duration, error) {
+	if d == nil {
+		return s.opts.Interval, nil
+	}
+	if err := d.CheckValid(); err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "interval: %v", err)
+	}
+	interval := d.AsDuration()
+	if interval < s.opts.MinInterval || interval > maxInterval {
+		return 0, status.Errorf(codes.InvalidArgument, "interval must be from %s to %s", s.opts.MinInterval, maxInterval)
+	}
+	return interval, nil
+}
+
+// poll runs f now and every interval until ctx is done or f fails.
+func (s *Server) poll(ctx context.Context, interval time.Duration, f func() error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := f(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// call calls f and turns its error into a gRPC status.
+func call[T any](ctx context.Context, f func(context.Context) (T, error)) (T, error) {
+	v, err := f(ctx)
+	if err != nil {
+		return v, statusError(err)
+	}
+	return v, nil
+}
+
+// statusError maps an upstream status code to a gRPC code.
+func statusError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	code := 0
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		code = apiErr.StatusCode
+	}
+	switch {
+	case code == http.StatusNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case code == http.StatusTooManyRequests:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case code >= 400 && code < 500:
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
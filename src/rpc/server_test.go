@@ -0,0 +1,193 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/rpc/stonfipb"
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	wallet      = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+)
+
+var fixtures = stonfitest.Fixtures{
+	Assets: []types.Asset{
+		{ContractAddress: tonAddress, Symbol: "TON", Decimals: 9, Kind: types.AssetKindTon, Tags: []string{"default"}},
+		{ContractAddress: usdtAddress, Symbol: "USDT", Decimals: 6, Kind: types.AssetKindJetton},
+	},
+	Pools: []types.Pool{
+		{Address: poolAddress, Token0Address: tonAddress, Token1Address: usdtAddress, Reserve0: "1000000000000", Reserve1: "5000000000", LpFee: "20", ProtocolFee: "10", Apy7D: "0.12"},
+		{Address: "old", Token0Address: tonAddress, Token1Address: "other", Reserve0: "1", Reserve1: "1", Deprecated: true},
+	},
+	Farms: []types.Farm{{
+		MinterAddress: "minter", PoolAddress: poolAddress, Status: "operational",
+		Rewards: []types.FarmReward{{Address: usdtAddress, RemainingRewards: "1000", RewardRate24H: "10"}},
+	}},
+}
+
+func newClient(t *testing.T, opts Options) (*stonfitest.Server, stonfipb.DexClient) {
+	upstream := stonfitest.NewServerWithFixtures(fixtures)
+	listener := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	New(upstream.Client(), opts).Register(g)
+	go g.Serve(listener)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		g.Stop()
+		upstream.Close()
+	})
+	return upstream, stonfipb.NewDexClient(conn)
+}
+
+func TestLookups(t *testing.T) {
+	_, c := newClient(t, Options{})
+	ctx := context.Background()
+
+	asset, err := c.GetAsset(ctx, &stonfipb.GetAssetRequest{Address: tonAddress})
+	if assert.NoError(t, err) {
+		assert.Equal(t, fixtures.Assets[0], AssetOf(asset))
+	}
+	_, err = c.GetAsset(ctx, &stonfipb.GetAssetRequest{Address: "EQnope"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = c.GetAsset(ctx, &stonfipb.GetAssetRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	pools, err := c.ListPools(ctx, &stonfipb.ListPoolsRequest{})
+	if assert.NoError(t, err) && assert.Len(t, pools.Pools, 1, "deprecated pools are left out") {
+		assert.Equal(t, fixtures.Pools[0], PoolOf(pools.Pools[0]))
+	}
+	pools, err = c.ListPools(ctx, &stonfipb.ListPoolsRequest{IncludeDeprecated: true})
+	if assert.NoError(t, err) {
+		assert.Len(t, pools.Pools, 2)
+	}
+
+	farms, err := c.ListFarms(ctx, &stonfipb.ListFarmsRequest{PoolAddress: poolAddress})
+	if assert.NoError(t, err) && assert.Len(t, farms.Farms, 1) {
+		assert.Equal(t, fixtures.Farms[0], FarmOf(farms.Farms[0]))
+	}
+
+	sim, err := c.SimulateSwap(ctx, &stonfipb.SimulateSwapRequest{OfferAddress: tonAddress, AskAddress: usdtAddress, Units: "1000000000"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, poolAddress, sim.PoolAddress)
+		assert.NotEmpty(t, sim.AskUnits)
+		assert.Equal(t, "0.001", sim.SlippageTolerance)
+	}
+	reverse, err := c.SimulateSwap(ctx, &stonfipb.SimulateSwapRequest{OfferAddress: tonAddress, AskAddress: usdtAddress, Units: sim.AskUnits, Reverse: true})
+	if assert.NoError(t, err) {
+		assert.Equal(t, sim.AskUnits, reverse.AskUnits)
+	}
+}
+
+func TestWatchPools(t *testing.T) {
+	upstream, c := newClient(t, Options{MinInterval: time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.WatchPools(ctx, &stonfipb.WatchPoolsRequest{Interval: durationpb.New(time.Nanosecond)})
+	assert.NoError(t, err, "stream errors come with the first message")
+	stream, _ := c.WatchPools(ctx, &stonfipb.WatchPoolsRequest{Interval: durationpb.New(time.Nanosecond)})
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	stream, err = c.WatchPools(ctx, &stonfipb.WatchPoolsRequest{
+		Addresses:   []string{poolAddress},
+		Interval:    durationpb.New(10 * time.Millisecond),
+		SendInitial: true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	update, err := stream.Recv()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "1000000000000", update.Pool.Reserve0)
+	assert.Nil(t, update.Previous)
+
+	upstream.SetReserves(poolAddress, "1100000000000", "4600000000")
+	update, err = stream.Recv()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1100000000000", update.Pool.Reserve0)
+		assert.Equal(t, "1000000000000", update.Previous.Reserve0)
+		assert.NotNil(t, update.DetectedAt)
+	}
+}
+
+func TestWatchOperations(t *testing.T) {
+	upstream, c := newClient(t, Options{MinInterval: time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	operation := func(hash, pool, kind string) types.OperationInfo {
+		return types.OperationInfo{Operation: types.Operation{
+			PoolTxHash:      hash,
+			PoolTxTimestamp: time.Now().UTC().Add(-time.Second).Format(openapi.TimeLayout),
+			PoolAddress:     pool,
+			WalletAddress:   wallet,
+			OperationType:   kind,
+			Success:         true,
+		}}
+	}
+	upstream.AddOperation(operation("before", poolAddress, "swap"))
+
+	stream, err := c.WatchOperations(ctx, &stonfipb.WatchOperationsRequest{
+		PoolAddress:    poolAddress,
+		OperationTypes: []string{"swap"},
+		Interval:       durationpb.New(10 * time.Millisecond),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	// Let the first poll take the baseline.
+	for len(upstream.Requests()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	upstream.AddOperation(operation("other-pool", "old", "swap"))
+	upstream.AddOperation(operation("provide", poolAddress, "provide"))
+	upstream.AddOperation(operation("new", poolAddress, "swap"))
+
+	info, err := stream.Recv()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "new", OperationOf(info).Operation.PoolTxHash)
+	}
+	upstream.AddOperation(operation("newer", poolAddress, "swap"))
+	info, err = stream.Recv()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "newer", info.Operation.PoolTxHash, "each operation is sent once")
+	}
+}
+
+func TestConversionsRoundTrip(t *testing.T) {
+	info := types.OperationInfo{
+		Operation:  types.Operation{PoolTxHash: "h", PoolTxLt: 7, Asset0Delta: "-1", WalletTxLt: "8", Success: true},
+		Asset0Info: fixtures.Assets[0],
+		Asset1Info: fixtures.Assets[1],
+	}
+	assert.Equal(t, info, OperationOf(OperationProto(info)))
+	sim := types.SwapSimulationResponse{AskUnits: "1", OfferUnits: "2", PriceImpact: "0.01", SwapRate: "0.5"}
+	assert.Equal(t, sim, SwapSimulationOf(SwapSimulationProto(sim)))
+	farm := types.Farm{NftInfos: []types.FarmNftInfo{{Address: "nft", Rewards: []types.FarmNftReward{{Address: "r", Amount: "3"}}}}}
+	assert.Equal(t, farm, FarmOf(FarmProto(farm)))
+}
@@ -0,0 +1,2146 @@
+// The Ston.fi DEX data service. Messages mirror the types of the
+// github.com/itay747/go-stonfi/src/types package, field for field; amounts
+// stay decimal strings in the tokens' smallest units, as the API sends them.
+//
+// Regenerate the Go code with `go generate ./src/rpc`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: stonfi.proto
+
+package stonfipb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Asset struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ContractAddress string                 `protobuf:"bytes,1,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+	Symbol          string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	DisplayName     string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	DexPriceUsd     string                 `protobuf:"bytes,4,opt,name=dex_price_usd,json=dexPriceUsd,proto3" json:"dex_price_usd,omitempty"`
+	ImageUrl        string                 `protobuf:"bytes,5,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	DexUsdPrice     string                 `protobuf:"bytes,6,opt,name=dex_usd_price,json=dexUsdPrice,proto3" json:"dex_usd_price,omitempty"`
+	// Ton, Wton or Jetton.
+	Kind               string   `protobuf:"bytes,7,opt,name=kind,proto3" json:"kind,omitempty"`
+	ThirdPartyPriceUsd string   `protobuf:"bytes,8,opt,name=third_party_price_usd,json=thirdPartyPriceUsd,proto3" json:"third_party_price_usd,omitempty"`
+	ThirdPartyUsdPrice string   `protobuf:"bytes,9,opt,name=third_party_usd_price,json=thirdPartyUsdPrice,proto3" json:"third_party_usd_price,omitempty"`
+	Tags               []string `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	Decimals           int32    `protobuf:"varint,11,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	Priority           int32    `protobuf:"varint,12,opt,name=priority,proto3" json:"priority,omitempty"`
+	DefaultSymbol      bool     `protobuf:"varint,13,opt,name=default_symbol,json=defaultSymbol,proto3" json:"default_symbol,omitempty"`
+	Taxable            bool     `protobuf:"varint,14,opt,name=taxable,proto3" json:"taxable,omitempty"`
+	Blacklisted        bool     `protobuf:"varint,15,opt,name=blacklisted,proto3" json:"blacklisted,omitempty"`
+	Community          bool     `protobuf:"varint,16,opt,name=community,proto3" json:"community,omitempty"`
+	Deprecated         bool     `protobuf:"varint,17,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Asset) Reset() {
+	*x = Asset{}
+	mi := &file_stonfi_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Asset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Asset) ProtoMessage() {}
+
+func (x *Asset) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Asset.ProtoReflect.Descriptor instead.
+func (*Asset) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Asset) GetContractAddress() string {
+	if x != nil {
+		return x.ContractAddress
+	}
+	return ""
+}
+
+func (x *Asset) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Asset) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Asset) GetDexPriceUsd() string {
+	if x != nil {
+		return x.DexPriceUsd
+	}
+	return ""
+}
+
+func (x *Asset) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Asset) GetDexUsdPrice() string {
+	if x != nil {
+		return x.DexUsdPrice
+	}
+	return ""
+}
+
+func (x *Asset) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Asset) GetThirdPartyPriceUsd() string {
+	if x != nil {
+		return x.ThirdPartyPriceUsd
+	}
+	return ""
+}
+
+func (x *Asset) GetThirdPartyUsdPrice() string {
+	if x != nil {
+		return x.ThirdPartyUsdPrice
+	}
+	return ""
+}
+
+func (x *Asset) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Asset) GetDecimals() int32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *Asset) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Asset) GetDefaultSymbol() bool {
+	if x != nil {
+		return x.DefaultSymbol
+	}
+	return false
+}
+
+func (x *Asset) GetTaxable() bool {
+	if x != nil {
+		return x.Taxable
+	}
+	return false
+}
+
+func (x *Asset) GetBlacklisted() bool {
+	if x != nil {
+		return x.Blacklisted
+	}
+	return false
+}
+
+func (x *Asset) GetCommunity() bool {
+	if x != nil {
+		return x.Community
+	}
+	return false
+}
+
+func (x *Asset) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+type Pool struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	Address                    string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	RouterAddress              string                 `protobuf:"bytes,2,opt,name=router_address,json=routerAddress,proto3" json:"router_address,omitempty"`
+	Reserve0                   string                 `protobuf:"bytes,3,opt,name=reserve0,proto3" json:"reserve0,omitempty"`
+	Reserve1                   string                 `protobuf:"bytes,4,opt,name=reserve1,proto3" json:"reserve1,omitempty"`
+	Token0Address              string                 `protobuf:"bytes,5,opt,name=token0_address,json=token0Address,proto3" json:"token0_address,omitempty"`
+	Token1Address              string                 `protobuf:"bytes,6,opt,name=token1_address,json=token1Address,proto3" json:"token1_address,omitempty"`
+	LpTotalSupply              string                 `protobuf:"bytes,7,opt,name=lp_total_supply,json=lpTotalSupply,proto3" json:"lp_total_supply,omitempty"`
+	LpTotalSupplyUsd           string                 `protobuf:"bytes,8,opt,name=lp_total_supply_usd,json=lpTotalSupplyUsd,proto3" json:"lp_total_supply_usd,omitempty"`
+	LpFee                      string                 `protobuf:"bytes,9,opt,name=lp_fee,json=lpFee,proto3" json:"lp_fee,omitempty"`
+	ProtocolFee                string                 `protobuf:"bytes,10,opt,name=protocol_fee,json=protocolFee,proto3" json:"protocol_fee,omitempty"`
+	RefFee                     string                 `protobuf:"bytes,11,opt,name=ref_fee,json=refFee,proto3" json:"ref_fee,omitempty"`
+	ProtocolFeeAddress         string                 `protobuf:"bytes,12,opt,name=protocol_fee_address,json=protocolFeeAddress,proto3" json:"protocol_fee_address,omitempty"`
+	CollectedToken0ProtocolFee string                 `protobuf:"bytes,13,opt,name=collected_token0_protocol_fee,json=collectedToken0ProtocolFee,proto3" json:"collected_token0_protocol_fee,omitempty"`
+	CollectedToken1ProtocolFee string                 `protobuf:"bytes,14,opt,name=collected_token1_protocol_fee,json=collectedToken1ProtocolFee,proto3" json:"collected_token1_protocol_fee,omitempty"`
+	LpPriceUsd                 string                 `protobuf:"bytes,15,opt,name=lp_price_usd,json=lpPriceUsd,proto3" json:"lp_price_usd,omitempty"`
+	Apy_1D                     string                 `protobuf:"bytes,16,opt,name=apy_1d,json=apy1d,proto3" json:"apy_1d,omitempty"`
+	Apy_7D                     string                 `protobuf:"bytes,17,opt,name=apy_7d,json=apy7d,proto3" json:"apy_7d,omitempty"`
+	Apy_30D                    string                 `protobuf:"bytes,18,opt,name=apy_30d,json=apy30d,proto3" json:"apy_30d,omitempty"`
+	Deprecated                 bool                   `protobuf:"varint,19,opt,name=deprecated,proto3" json:"deprecated,omitempty"`
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *Pool) Reset() {
+	*x = Pool{}
+	mi := &file_stonfi_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pool) ProtoMessage() {}
+
+func (x *Pool) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pool.ProtoReflect.Descriptor instead.
+func (*Pool) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Pool) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Pool) GetRouterAddress() string {
+	if x != nil {
+		return x.RouterAddress
+	}
+	return ""
+}
+
+func (x *Pool) GetReserve0() string {
+	if x != nil {
+		return x.Reserve0
+	}
+	return ""
+}
+
+func (x *Pool) GetReserve1() string {
+	if x != nil {
+		return x.Reserve1
+	}
+	return ""
+}
+
+func (x *Pool) GetToken0Address() string {
+	if x != nil {
+		return x.Token0Address
+	}
+	return ""
+}
+
+func (x *Pool) GetToken1Address() string {
+	if x != nil {
+		return x.Token1Address
+	}
+	return ""
+}
+
+func (x *Pool) GetLpTotalSupply() string {
+	if x != nil {
+		return x.LpTotalSupply
+	}
+	return ""
+}
+
+func (x *Pool) GetLpTotalSupplyUsd() string {
+	if x != nil {
+		return x.LpTotalSupplyUsd
+	}
+	return ""
+}
+
+func (x *Pool) GetLpFee() string {
+	if x != nil {
+		return x.LpFee
+	}
+	return ""
+}
+
+func (x *Pool) GetProtocolFee() string {
+	if x != nil {
+		return x.ProtocolFee
+	}
+	return ""
+}
+
+func (x *Pool) GetRefFee() string {
+	if x != nil {
+		return x.RefFee
+	}
+	return ""
+}
+
+func (x *Pool) GetProtocolFeeAddress() string {
+	if x != nil {
+		return x.ProtocolFeeAddress
+	}
+	return ""
+}
+
+func (x *Pool) GetCollectedToken0ProtocolFee() string {
+	if x != nil {
+		return x.CollectedToken0ProtocolFee
+	}
+	return ""
+}
+
+func (x *Pool) GetCollectedToken1ProtocolFee() string {
+	if x != nil {
+		return x.CollectedToken1ProtocolFee
+	}
+	return ""
+}
+
+func (x *Pool) GetLpPriceUsd() string {
+	if x != nil {
+		return x.LpPriceUsd
+	}
+	return ""
+}
+
+func (x *Pool) GetApy_1D() string {
+	if x != nil {
+		return x.Apy_1D
+	}
+	return ""
+}
+
+func (x *Pool) GetApy_7D() string {
+	if x != nil {
+		return x.Apy_7D
+	}
+	return ""
+}
+
+func (x *Pool) GetApy_30D() string {
+	if x != nil {
+		return x.Apy_30D
+	}
+	return ""
+}
+
+func (x *Pool) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+type Farm struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	MinterAddress      string                 `protobuf:"bytes,1,opt,name=minter_address,json=minterAddress,proto3" json:"minter_address,omitempty"`
+	PoolAddress        string                 `protobuf:"bytes,2,opt,name=pool_address,json=poolAddress,proto3" json:"pool_address,omitempty"`
+	RewardTokenAddress string                 `protobuf:"bytes,3,opt,name=reward_token_address,json=rewardTokenAddress,proto3" json:"reward_token_address,omitempty"`
+	Status             string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	MinStakeDurationS  string                 `protobuf:"bytes,5,opt,name=min_stake_duration_s,json=minStakeDurationS,proto3" json:"min_stake_duration_s,omitempty"`
+	LockedTotalLp      string                 `protobuf:"bytes,6,opt,name=locked_total_lp,json=lockedTotalLp,proto3" json:"locked_total_lp,omitempty"`
+	LockedTotalLpUsd   string                 `protobuf:"bytes,7,opt,name=locked_total_lp_usd,json=lockedTotalLpUsd,proto3" json:"locked_total_lp_usd,omitempty"`
+	Apy                string                 `protobuf:"bytes,8,opt,name=apy,proto3" json:"apy,omitempty"`
+	NftInfos           []*FarmNftInfo         `protobuf:"bytes,9,rep,name=nft_infos,json=nftInfos,proto3" json:"nft_infos,omitempty"`
+	Rewards            []*FarmReward          `protobuf:"bytes,10,rep,name=rewards,proto3" json:"rewards,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Farm) Reset() {
+	*x = Farm{}
+	mi := &file_stonfi_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Farm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Farm) ProtoMessage() {}
+
+func (x *Farm) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Farm.ProtoReflect.Descriptor instead.
+func (*Farm) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Farm) GetMinterAddress() string {
+	if x != nil {
+		return x.MinterAddress
+	}
+	return ""
+}
+
+func (x *Farm) GetPoolAddress() string {
+	if x != nil {
+		return x.PoolAddress
+	}
+	return ""
+}
+
+func (x *Farm) GetRewardTokenAddress() string {
+	if x != nil {
+		return x.RewardTokenAddress
+	}
+	return ""
+}
+
+func (x *Farm) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Farm) GetMinStakeDurationS() string {
+	if x != nil {
+		return x.MinStakeDurationS
+	}
+	return ""
+}
+
+func (x *Farm) GetLockedTotalLp() string {
+	if x != nil {
+		return x.LockedTotalLp
+	}
+	return ""
+}
+
+func (x *Farm) GetLockedTotalLpUsd() string {
+	if x != nil {
+		return x.LockedTotalLpUsd
+	}
+	return ""
+}
+
+func (x *Farm) GetApy() string {
+	if x != nil {
+		return x.Apy
+	}
+	return ""
+}
+
+func (x *Farm) GetNftInfos() []*FarmNftInfo {
+	if x != nil {
+		return x.NftInfos
+	}
+	return nil
+}
+
+func (x *Farm) GetRewards() []*FarmReward {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+type FarmReward struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Address          string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Status           string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	RemainingRewards string                 `protobuf:"bytes,3,opt,name=remaining_rewards,json=remainingRewards,proto3" json:"remaining_rewards,omitempty"`
+	RewardRate_24H   string                 `protobuf:"bytes,4,opt,name=reward_rate_24h,json=rewardRate24h,proto3" json:"reward_rate_24h,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *FarmReward) Reset() {
+	*x = FarmReward{}
+	mi := &file_stonfi_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FarmReward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FarmReward) ProtoMessage() {}
+
+func (x *FarmReward) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FarmReward.ProtoReflect.Descriptor instead.
+func (*FarmReward) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FarmReward) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *FarmReward) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *FarmReward) GetRemainingRewards() string {
+	if x != nil {
+		return x.RemainingRewards
+	}
+	return ""
+}
+
+func (x *FarmReward) GetRewardRate_24H() string {
+	if x != nil {
+		return x.RewardRate_24H
+	}
+	return ""
+}
+
+type FarmNftInfo struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Address             string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Status              string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	CreateTimestamp     string                 `protobuf:"bytes,3,opt,name=create_timestamp,json=createTimestamp,proto3" json:"create_timestamp,omitempty"`
+	MinUnstakeTimestamp string                 `protobuf:"bytes,4,opt,name=min_unstake_timestamp,json=minUnstakeTimestamp,proto3" json:"min_unstake_timestamp,omitempty"`
+	StakedTokens        string                 `protobuf:"bytes,5,opt,name=staked_tokens,json=stakedTokens,proto3" json:"staked_tokens,omitempty"`
+	NonclaimedRewards   string                 `protobuf:"bytes,6,opt,name=nonclaimed_rewards,json=nonclaimedRewards,proto3" json:"nonclaimed_rewards,omitempty"`
+	Rewards             []*FarmNftReward       `protobuf:"bytes,7,rep,name=rewards,proto3" json:"rewards,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *FarmNftInfo) Reset() {
+	*x = FarmNftInfo{}
+	mi := &file_stonfi_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FarmNftInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FarmNftInfo) ProtoMessage() {}
+
+func (x *FarmNftInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FarmNftInfo.ProtoReflect.Descriptor instead.
+func (*FarmNftInfo) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FarmNftInfo) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *FarmNftInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *FarmNftInfo) GetCreateTimestamp() string {
+	if x != nil {
+		return x.CreateTimestamp
+	}
+	return ""
+}
+
+func (x *FarmNftInfo) GetMinUnstakeTimestamp() string {
+	if x != nil {
+		return x.MinUnstakeTimestamp
+	}
+	return ""
+}
+
+func (x *FarmNftInfo) GetStakedTokens() string {
+	if x != nil {
+		return x.StakedTokens
+	}
+	return ""
+}
+
+func (x *FarmNftInfo) GetNonclaimedRewards() string {
+	if x != nil {
+		return x.NonclaimedRewards
+	}
+	return ""
+}
+
+func (x *FarmNftInfo) GetRewards() []*FarmNftReward {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+type FarmNftReward struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FarmNftReward) Reset() {
+	*x = FarmNftReward{}
+	mi := &file_stonfi_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FarmNftReward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FarmNftReward) ProtoMessage() {}
+
+func (x *FarmNftReward) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FarmNftReward.ProtoReflect.Descriptor instead.
+func (*FarmNftReward) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FarmNftReward) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *FarmNftReward) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+type Operation struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	ProtocolFeeAmount        string                 `protobuf:"bytes,1,opt,name=protocol_fee_amount,json=protocolFeeAmount,proto3" json:"protocol_fee_amount,omitempty"`
+	FeeAssetAddress          string                 `protobuf:"bytes,2,opt,name=fee_asset_address,json=feeAssetAddress,proto3" json:"fee_asset_address,omitempty"`
+	RouterAddress            string                 `protobuf:"bytes,3,opt,name=router_address,json=routerAddress,proto3" json:"router_address,omitempty"`
+	Asset0Reserve            string                 `protobuf:"bytes,4,opt,name=asset0_reserve,json=asset0Reserve,proto3" json:"asset0_reserve,omitempty"`
+	PoolTxTimestamp          string                 `protobuf:"bytes,5,opt,name=pool_tx_timestamp,json=poolTxTimestamp,proto3" json:"pool_tx_timestamp,omitempty"`
+	DestinationWalletAddress string                 `protobuf:"bytes,6,opt,name=destination_wallet_address,json=destinationWalletAddress,proto3" json:"destination_wallet_address,omitempty"`
+	OperationType            string                 `protobuf:"bytes,7,opt,name=operation_type,json=operationType,proto3" json:"operation_type,omitempty"`
+	WalletTxHash             string                 `protobuf:"bytes,8,opt,name=wallet_tx_hash,json=walletTxHash,proto3" json:"wallet_tx_hash,omitempty"`
+	ExitCode                 string                 `protobuf:"bytes,9,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Asset0Address            string                 `protobuf:"bytes,10,opt,name=asset0_address,json=asset0Address,proto3" json:"asset0_address,omitempty"`
+	Asset0Amount             string                 `protobuf:"bytes,11,opt,name=asset0_amount,json=asset0Amount,proto3" json:"asset0_amount,omitempty"`
+	Asset0Delta              string                 `protobuf:"bytes,12,opt,name=asset0_delta,json=asset0Delta,proto3" json:"asset0_delta,omitempty"`
+	WalletTxTimestamp        string                 `protobuf:"bytes,13,opt,name=wallet_tx_timestamp,json=walletTxTimestamp,proto3" json:"wallet_tx_timestamp,omitempty"`
+	WalletTxLt               string                 `protobuf:"bytes,14,opt,name=wallet_tx_lt,json=walletTxLt,proto3" json:"wallet_tx_lt,omitempty"`
+	LpTokenSupply            string                 `protobuf:"bytes,15,opt,name=lp_token_supply,json=lpTokenSupply,proto3" json:"lp_token_supply,omitempty"`
+	Asset1Delta              string                 `protobuf:"bytes,16,opt,name=asset1_delta,json=asset1Delta,proto3" json:"asset1_delta,omitempty"`
+	Asset1Reserve            string                 `protobuf:"bytes,17,opt,name=asset1_reserve,json=asset1Reserve,proto3" json:"asset1_reserve,omitempty"`
+	LpTokenDelta             string                 `protobuf:"bytes,18,opt,name=lp_token_delta,json=lpTokenDelta,proto3" json:"lp_token_delta,omitempty"`
+	Asset1Amount             string                 `protobuf:"bytes,19,opt,name=asset1_amount,json=asset1Amount,proto3" json:"asset1_amount,omitempty"`
+	PoolAddress              string                 `protobuf:"bytes,20,opt,name=pool_address,json=poolAddress,proto3" json:"pool_address,omitempty"`
+	LpFeeAmount              string                 `protobuf:"bytes,21,opt,name=lp_fee_amount,json=lpFeeAmount,proto3" json:"lp_fee_amount,omitempty"`
+	PoolTxHash               string                 `protobuf:"bytes,22,opt,name=pool_tx_hash,json=poolTxHash,proto3" json:"pool_tx_hash,omitempty"`
+	ReferralFeeAmount        string                 `protobuf:"bytes,23,opt,name=referral_fee_amount,json=referralFeeAmount,proto3" json:"referral_fee_amount,omitempty"`
+	ReferralAddress          string                 `protobuf:"bytes,24,opt,name=referral_address,json=referralAddress,proto3" json:"referral_address,omitempty"`
+	WalletAddress            string                 `protobuf:"bytes,25,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	Asset1Address            string                 `protobuf:"bytes,26,opt,name=asset1_address,json=asset1Address,proto3" json:"asset1_address,omitempty"`
+	PoolTxLt                 int64                  `protobuf:"varint,27,opt,name=pool_tx_lt,json=poolTxLt,proto3" json:"pool_tx_lt,omitempty"`
+	Success                  bool                   `protobuf:"varint,28,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_stonfi_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Operation) GetProtocolFeeAmount() string {
+	if x != nil {
+		return x.ProtocolFeeAmount
+	}
+	return ""
+}
+
+func (x *Operation) GetFeeAssetAddress() string {
+	if x != nil {
+		return x.FeeAssetAddress
+	}
+	return ""
+}
+
+func (x *Operation) GetRouterAddress() string {
+	if x != nil {
+		return x.RouterAddress
+	}
+	return ""
+}
+
+func (x *Operation) GetAsset0Reserve() string {
+	if x != nil {
+		return x.Asset0Reserve
+	}
+	return ""
+}
+
+func (x *Operation) GetPoolTxTimestamp() string {
+	if x != nil {
+		return x.PoolTxTimestamp
+	}
+	return ""
+}
+
+func (x *Operation) GetDestinationWalletAddress() string {
+	if x != nil {
+		return x.DestinationWalletAddress
+	}
+	return ""
+}
+
+func (x *Operation) GetOperationType() string {
+	if x != nil {
+		return x.OperationType
+	}
+	return ""
+}
+
+func (x *Operation) GetWalletTxHash() string {
+	if x != nil {
+		return x.WalletTxHash
+	}
+	return ""
+}
+
+func (x *Operation) GetExitCode() string {
+	if x != nil {
+		return x.ExitCode
+	}
+	return ""
+}
+
+func (x *Operation) GetAsset0Address() string {
+	if x != nil {
+		return x.Asset0Address
+	}
+	return ""
+}
+
+func (x *Operation) GetAsset0Amount() string {
+	if x != nil {
+		return x.Asset0Amount
+	}
+	return ""
+}
+
+func (x *Operation) GetAsset0Delta() string {
+	if x != nil {
+		return x.Asset0Delta
+	}
+	return ""
+}
+
+func (x *Operation) GetWalletTxTimestamp() string {
+	if x != nil {
+		return x.WalletTxTimestamp
+	}
+	return ""
+}
+
+func (x *Operation) GetWalletTxLt() string {
+	if x != nil {
+		return x.WalletTxLt
+	}
+	return ""
+}
+
+func (x *Operation) GetLpTokenSupply() string {
+	if x != nil {
+		return x.LpTokenSupply
+	}
+	return ""
+}
+
+func (x *Operation) GetAsset1Delta() string {
+	if x != nil {
+		return x.Asset1Delta
+	}
+	return ""
+}
+
+func (x *Operation) GetAsset1Reserve() string {
+	if x != nil {
+		return x.Asset1Reserve
+	}
+	return ""
+}
+
+func (x *Operation) GetLpTokenDelta() string {
+	if x != nil {
+		return x.LpTokenDelta
+	}
+	return ""
+}
+
+func (x *Operation) GetAsset1Amount() string {
+	if x != nil {
+		return x.Asset1Amount
+	}
+	return ""
+}
+
+func (x *Operation) GetPoolAddress() string {
+	if x != nil {
+		return x.PoolAddress
+	}
+	return ""
+}
+
+func (x *Operation) GetLpFeeAmount() string {
+	if x != nil {
+		return x.LpFeeAmount
+	}
+	return ""
+}
+
+func (x *Operation) GetPoolTxHash() string {
+	if x != nil {
+		return x.PoolTxHash
+	}
+	return ""
+}
+
+func (x *Operation) GetReferralFeeAmount() string {
+	if x != nil {
+		return x.ReferralFeeAmount
+	}
+	return ""
+}
+
+func (x *Operation) GetReferralAddress() string {
+	if x != nil {
+		return x.ReferralAddress
+	}
+	return ""
+}
+
+func (x *Operation) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *Operation) GetAsset1Address() string {
+	if x != nil {
+		return x.Asset1Address
+	}
+	return ""
+}
+
+func (x *Operation) GetPoolTxLt() int64 {
+	if x != nil {
+		return x.PoolTxLt
+	}
+	return 0
+}
+
+func (x *Operation) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type OperationInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operation     *Operation             `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Asset0Info    *Asset                 `protobuf:"bytes,2,opt,name=asset0_info,json=asset0Info,proto3" json:"asset0_info,omitempty"`
+	Asset1Info    *Asset                 `protobuf:"bytes,3,opt,name=asset1_info,json=asset1Info,proto3" json:"asset1_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationInfo) Reset() {
+	*x = OperationInfo{}
+	mi := &file_stonfi_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationInfo) ProtoMessage() {}
+
+func (x *OperationInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OperationInfo.ProtoReflect.Descriptor instead.
+func (*OperationInfo) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OperationInfo) GetOperation() *Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+func (x *OperationInfo) GetAsset0Info() *Asset {
+	if x != nil {
+		return x.Asset0Info
+	}
+	return nil
+}
+
+func (x *OperationInfo) GetAsset1Info() *Asset {
+	if x != nil {
+		return x.Asset1Info
+	}
+	return nil
+}
+
+type SwapSimulation struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	AskAddress        string                 `protobuf:"bytes,1,opt,name=ask_address,json=askAddress,proto3" json:"ask_address,omitempty"`
+	AskJettonWallet   string                 `protobuf:"bytes,2,opt,name=ask_jetton_wallet,json=askJettonWallet,proto3" json:"ask_jetton_wallet,omitempty"`
+	AskUnits          string                 `protobuf:"bytes,3,opt,name=ask_units,json=askUnits,proto3" json:"ask_units,omitempty"`
+	FeeAddress        string                 `protobuf:"bytes,4,opt,name=fee_address,json=feeAddress,proto3" json:"fee_address,omitempty"`
+	FeePercent        string                 `protobuf:"bytes,5,opt,name=fee_percent,json=feePercent,proto3" json:"fee_percent,omitempty"`
+	FeeUnits          string                 `protobuf:"bytes,6,opt,name=fee_units,json=feeUnits,proto3" json:"fee_units,omitempty"`
+	MinAskUnits       string                 `protobuf:"bytes,7,opt,name=min_ask_units,json=minAskUnits,proto3" json:"min_ask_units,omitempty"`
+	OfferAddress      string                 `protobuf:"bytes,8,opt,name=offer_address,json=offerAddress,proto3" json:"offer_address,omitempty"`
+	OfferJettonWallet string                 `protobuf:"bytes,9,opt,name=offer_jetton_wallet,json=offerJettonWallet,proto3" json:"offer_jetton_wallet,omitempty"`
+	OfferUnits        string                 `protobuf:"bytes,10,opt,name=offer_units,json=offerUnits,proto3" json:"offer_units,omitempty"`
+	PoolAddress       string                 `protobuf:"bytes,11,opt,name=pool_address,json=poolAddress,proto3" json:"pool_address,omitempty"`
+	PriceImpact       string                 `protobuf:"bytes,12,opt,name=price_impact,json=priceImpact,proto3" json:"price_impact,omitempty"`
+	RouterAddress     string                 `protobuf:"bytes,13,opt,name=router_address,json=routerAddress,proto3" json:"router_address,omitempty"`
+	SlippageTolerance string                 `protobuf:"bytes,14,opt,name=slippage_tolerance,json=slippageTolerance,proto3" json:"slippage_tolerance,omitempty"`
+	SwapRate          string                 `protobuf:"bytes,15,opt,name=swap_rate,json=swapRate,proto3" json:"swap_rate,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SwapSimulation) Reset() {
+	*x = SwapSimulation{}
+	mi := &file_stonfi_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwapSimulation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwapSimulation) ProtoMessage() {}
+
+func (x *SwapSimulation) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwapSimulation.ProtoReflect.Descriptor instead.
+func (*SwapSimulation) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SwapSimulation) GetAskAddress() string {
+	if x != nil {
+		return x.AskAddress
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetAskJettonWallet() string {
+	if x != nil {
+		return x.AskJettonWallet
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetAskUnits() string {
+	if x != nil {
+		return x.AskUnits
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetFeeAddress() string {
+	if x != nil {
+		return x.FeeAddress
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetFeePercent() string {
+	if x != nil {
+		return x.FeePercent
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetFeeUnits() string {
+	if x != nil {
+		return x.FeeUnits
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetMinAskUnits() string {
+	if x != nil {
+		return x.MinAskUnits
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetOfferAddress() string {
+	if x != nil {
+		return x.OfferAddress
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetOfferJettonWallet() string {
+	if x != nil {
+		return x.OfferJettonWallet
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetOfferUnits() string {
+	if x != nil {
+		return x.OfferUnits
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetPoolAddress() string {
+	if x != nil {
+		return x.PoolAddress
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetPriceImpact() string {
+	if x != nil {
+		return x.PriceImpact
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetRouterAddress() string {
+	if x != nil {
+		return x.RouterAddress
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetSlippageTolerance() string {
+	if x != nil {
+		return x.SlippageTolerance
+	}
+	return ""
+}
+
+func (x *SwapSimulation) GetSwapRate() string {
+	if x != nil {
+		return x.SwapRate
+	}
+	return ""
+}
+
+type GetAssetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssetRequest) Reset() {
+	*x = GetAssetRequest{}
+	mi := &file_stonfi_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetRequest) ProtoMessage() {}
+
+func (x *GetAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetRequest.ProtoReflect.Descriptor instead.
+func (*GetAssetRequest) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetAssetRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type ListAssetsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAssetsRequest) Reset() {
+	*x = ListAssetsRequest{}
+	mi := &file_stonfi_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAssetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAssetsRequest) ProtoMessage() {}
+
+func (x *ListAssetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAssetsRequest.ProtoReflect.Descriptor instead.
+func (*ListAssetsRequest) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{10}
+}
+
+type ListAssetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Assets        []*Asset               `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAssetsResponse) Reset() {
+	*x = ListAssetsResponse{}
+	mi := &file_stonfi_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAssetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAssetsResponse) ProtoMessage() {}
+
+func (x *ListAssetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAssetsResponse.ProtoReflect.Descriptor instead.
+func (*ListAssetsResponse) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListAssetsResponse) GetAssets() []*Asset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+type GetPoolRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPoolRequest) Reset() {
+	*x = GetPoolRequest{}
+	mi := &file_stonfi_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPoolRequest) ProtoMessage() {}
+
+func (x *GetPoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPoolRequest.ProtoReflect.Descriptor instead.
+func (*GetPoolRequest) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetPoolRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type ListPoolsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IncludeDeprecated bool                   `protobuf:"varint,1,opt,name=include_deprecated,json=includeDeprecated,proto3" json:"include_deprecated,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListPoolsRequest) Reset() {
+	*x = ListPoolsRequest{}
+	mi := &file_stonfi_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoolsRequest) ProtoMessage() {}
+
+func (x *ListPoolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoolsRequest.ProtoReflect.Descriptor instead.
+func (*ListPoolsRequest) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListPoolsRequest) GetIncludeDeprecated() bool {
+	if x != nil {
+		return x.IncludeDeprecated
+	}
+	return false
+}
+
+type ListPoolsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pools         []*Pool                `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoolsResponse) Reset() {
+	*x = ListPoolsResponse{}
+	mi := &file_stonfi_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoolsResponse) ProtoMessage() {}
+
+func (x *ListPoolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoolsResponse.ProtoReflect.Descriptor instead.
+func (*ListPoolsResponse) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListPoolsResponse) GetPools() []*Pool {
+	if x != nil {
+		return x.Pools
+	}
+	return nil
+}
+
+type GetFarmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFarmRequest) Reset() {
+	*x = GetFarmRequest{}
+	mi := &file_stonfi_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFarmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFarmRequest) ProtoMessage() {}
+
+func (x *GetFarmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFarmRequest.ProtoReflect.Descriptor instead.
+func (*GetFarmRequest) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetFarmRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type ListFarmsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PoolAddress   string                 `protobuf:"bytes,1,opt,name=pool_address,json=poolAddress,proto3" json:"pool_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFarmsRequest) Reset() {
+	*x = ListFarmsRequest{}
+	mi := &file_stonfi_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFarmsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFarmsRequest) ProtoMessage() {}
+
+func (x *ListFarmsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFarmsRequest.ProtoReflect.Descriptor instead.
+func (*ListFarmsRequest) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListFarmsRequest) GetPoolAddress() string {
+	if x != nil {
+		return x.PoolAddress
+	}
+	return ""
+}
+
+type ListFarmsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Farms         []*Farm                `protobuf:"bytes,1,rep,name=farms,proto3" json:"farms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFarmsResponse) Reset() {
+	*x = ListFarmsResponse{}
+	mi := &file_stonfi_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFarmsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFarmsResponse) ProtoMessage() {}
+
+func (x *ListFarmsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFarmsResponse.ProtoReflect.Descriptor instead.
+func (*ListFarmsResponse) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListFarmsResponse) GetFarms() []*Farm {
+	if x != nil {
+		return x.Farms
+	}
+	return nil
+}
+
+type SimulateSwapRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	OfferAddress string                 `protobuf:"bytes,1,opt,name=offer_address,json=offerAddress,proto3" json:"offer_address,omitempty"`
+	AskAddress   string                 `protobuf:"bytes,2,opt,name=ask_address,json=askAddress,proto3" json:"ask_address,omitempty"`
+	// units is what is offered, or with reverse, what is asked.
+	Units             string `protobuf:"bytes,3,opt,name=units,proto3" json:"units,omitempty"`
+	SlippageTolerance string `protobuf:"bytes,4,opt,name=slippage_tolerance,json=slippageTolerance,proto3" json:"slippage_tolerance,omitempty"`
+	Reverse           bool   `protobuf:"varint,5,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SimulateSwapRequest) Reset() {
+	*x = SimulateSwapRequest{}
+	mi := &file_stonfi_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimulateSwapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimulateSwapRequest) ProtoMessage() {}
+
+func (x *SimulateSwapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimulateSwapRequest.ProtoReflect.Descriptor instead.
+func (*SimulateSwapRequest) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SimulateSwapRequest) GetOfferAddress() string {
+	if x != nil {
+		return x.OfferAddress
+	}
+	return ""
+}
+
+func (x *SimulateSwapRequest) GetAskAddress() string {
+	if x != nil {
+		return x.AskAddress
+	}
+	return ""
+}
+
+func (x *SimulateSwapRequest) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *SimulateSwapRequest) GetSlippageTolerance() string {
+	if x != nil {
+		return x.SlippageTolerance
+	}
+	return ""
+}
+
+func (x *SimulateSwapRequest) GetReverse() bool {
+	if x != nil {
+		return x.Reverse
+	}
+	return false
+}
+
+type WatchPoolsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// addresses limits the stream to these pools; empty watches every pool.
+	Addresses []string             `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Interval  *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// send_initial sends every watched pool once before the changes.
+	SendInitial   bool `protobuf:"varint,3,opt,name=send_initial,json=sendInitial,proto3" json:"send_initial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchPoolsRequest) Reset() {
+	*x = WatchPoolsRequest{}
+	mi := &file_stonfi_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPoolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPoolsRequest) ProtoMessage() {}
+
+func (x *WatchPoolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPoolsRequest.ProtoReflect.Descriptor instead.
+func (*WatchPoolsRequest) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchPoolsRequest) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *WatchPoolsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *WatchPoolsRequest) GetSendInitial() bool {
+	if x != nil {
+		return x.SendInitial
+	}
+	return false
+}
+
+type PoolUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pool  *Pool                  `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	// previous is unset for the initial updates and for new pools.
+	Previous      *Pool                  `protobuf:"bytes,2,opt,name=previous,proto3" json:"previous,omitempty"`
+	DetectedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=detected_at,json=detectedAt,proto3" json:"detected_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PoolUpdate) Reset() {
+	*x = PoolUpdate{}
+	mi := &file_stonfi_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolUpdate) ProtoMessage() {}
+
+func (x *PoolUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolUpdate.ProtoReflect.Descriptor instead.
+func (*PoolUpdate) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PoolUpdate) GetPool() *Pool {
+	if x != nil {
+		return x.Pool
+	}
+	return nil
+}
+
+func (x *PoolUpdate) GetPrevious() *Pool {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *PoolUpdate) GetDetectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DetectedAt
+	}
+	return nil
+}
+
+type WatchOperationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Each filter set must match.
+	PoolAddress    string               `protobuf:"bytes,1,opt,name=pool_address,json=poolAddress,proto3" json:"pool_address,omitempty"`
+	WalletAddress  string               `protobuf:"bytes,2,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	OperationTypes []string             `protobuf:"bytes,3,rep,name=operation_types,json=operationTypes,proto3" json:"operation_types,omitempty"`
+	Interval       *durationpb.Duration `protobuf:"bytes,4,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchOperationsRequest) Reset() {
+	*x = WatchOperationsRequest{}
+	mi := &file_stonfi_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchOperationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOperationsRequest) ProtoMessage() {}
+
+func (x *WatchOperationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stonfi_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOperationsRequest.ProtoReflect.Descriptor instead.
+func (*WatchOperationsRequest) Descriptor() ([]byte, []int) {
+	return file_stonfi_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WatchOperationsRequest) GetPoolAddress() string {
+	if x != nil {
+		return x.PoolAddress
+	}
+	return ""
+}
+
+func (x *WatchOperationsRequest) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *WatchOperationsRequest) GetOperationTypes() []string {
+	if x != nil {
+		return x.OperationTypes
+	}
+	return nil
+}
+
+func (x *WatchOperationsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+var File_stonfi_proto protoreflect.FileDescriptor
+
+const file_stonfi_proto_rawDesc = "" +
+	"\n" +
+	"\fstonfi.proto\x12\tstonfi.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb9\x04\n" +
+	"\x05Asset\x12)\n" +
+	"\x10contract_address\x18\x01 \x01(\tR\x0fcontractAddress\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\"\n" +
+	"\rdex_price_usd\x18\x04 \x01(\tR\vdexPriceUsd\x12\x1b\n" +
+	"\timage_url\x18\x05 \x01(\tR\bimageUrl\x12\"\n" +
+	"\rdex_usd_price\x18\x06 \x01(\tR\vdexUsdPrice\x12\x12\n" +
+	"\x04kind\x18\a \x01(\tR\x04kind\x121\n" +
+	"\x15third_party_price_usd\x18\b \x01(\tR\x12thirdPartyPriceUsd\x121\n" +
+	"\x15third_party_usd_price\x18\t \x01(\tR\x12thirdPartyUsdPrice\x12\x12\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tR\x04tags\x12\x1a\n" +
+	"\bdecimals\x18\v \x01(\x05R\bdecimals\x12\x1a\n" +
+	"\bpriority\x18\f \x01(\x05R\bpriority\x12%\n" +
+	"\x0edefault_symbol\x18\r \x01(\bR\rdefaultSymbol\x12\x18\n" +
+	"\ataxable\x18\x0e \x01(\bR\ataxable\x12 \n" +
+	"\vblacklisted\x18\x0f \x01(\bR\vblacklisted\x12\x1c\n" +
+	"\tcommunity\x18\x10 \x01(\bR\tcommunity\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\x11 \x01(\bR\n" +
+	"deprecated\"\xb8\x05\n" +
+	"\x04Pool\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12%\n" +
+	"\x0erouter_address\x18\x02 \x01(\tR\rrouterAddress\x12\x1a\n" +
+	"\breserve0\x18\x03 \x01(\tR\breserve0\x12\x1a\n" +
+	"\breserve1\x18\x04 \x01(\tR\breserve1\x12%\n" +
+	"\x0etoken0_address\x18\x05 \x01(\tR\rtoken0Address\x12%\n" +
+	"\x0etoken1_address\x18\x06 \x01(\tR\rtoken1Address\x12&\n" +
+	"\x0flp_total_supply\x18\a \x01(\tR\rlpTotalSupply\x12-\n" +
+	"\x13lp_total_supply_usd\x18\b \x01(\tR\x10lpTotalSupplyUsd\x12\x15\n" +
+	"\x06lp_fee\x18\t \x01(\tR\x05lpFee\x12!\n" +
+	"\fprotocol_fee\x18\n" +
+	" \x01(\tR\vprotocolFee\x12\x17\n" +
+	"\aref_fee\x18\v \x01(\tR\x06refFee\x120\n" +
+	"\x14protocol_fee_address\x18\f \x01(\tR\x12protocolFeeAddress\x12A\n" +
+	"\x1dcollected_token0_protocol_fee\x18\r \x01(\tR\x1acollectedToken0ProtocolFee\x12A\n" +
+	"\x1dcollected_token1_protocol_fee\x18\x0e \x01(\tR\x1acollectedToken1ProtocolFee\x12 \n" +
+	"\flp_price_usd\x18\x0f \x01(\tR\n" +
+	"lpPriceUsd\x12\x15\n" +
+	"\x06apy_1d\x18\x10 \x01(\tR\x05apy1d\x12\x15\n" +
+	"\x06apy_7d\x18\x11 \x01(\tR\x05apy7d\x12\x17\n" +
+	"\aapy_30d\x18\x12 \x01(\tR\x06apy30d\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\x13 \x01(\bR\n" +
+	"deprecated\"\x9a\x03\n" +
+	"\x04Farm\x12%\n" +
+	"\x0eminter_address\x18\x01 \x01(\tR\rminterAddress\x12!\n" +
+	"\fpool_address\x18\x02 \x01(\tR\vpoolAddress\x120\n" +
+	"\x14reward_token_address\x18\x03 \x01(\tR\x12rewardTokenAddress\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12/\n" +
+	"\x14min_stake_duration_s\x18\x05 \x01(\tR\x11minStakeDurationS\x12&\n" +
+	"\x0flocked_total_lp\x18\x06 \x01(\tR\rlockedTotalLp\x12-\n" +
+	"\x13locked_total_lp_usd\x18\a \x01(\tR\x10lockedTotalLpUsd\x12\x10\n" +
+	"\x03apy\x18\b \x01(\tR\x03apy\x123\n" +
+	"\tnft_infos\x18\t \x03(\v2\x16.stonfi.v1.FarmNftInfoR\bnftInfos\x12/\n" +
+	"\arewards\x18\n" +
+	" \x03(\v2\x15.stonfi.v1.FarmRewardR\arewards\"\x93\x01\n" +
+	"\n" +
+	"FarmReward\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12+\n" +
+	"\x11remaining_rewards\x18\x03 \x01(\tR\x10remainingRewards\x12&\n" +
+	"\x0freward_rate_24h\x18\x04 \x01(\tR\rrewardRate24h\"\xa6\x02\n" +
+	"\vFarmNftInfo\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12)\n" +
+	"\x10create_timestamp\x18\x03 \x01(\tR\x0fcreateTimestamp\x122\n" +
+	"\x15min_unstake_timestamp\x18\x04 \x01(\tR\x13minUnstakeTimestamp\x12#\n" +
+	"\rstaked_tokens\x18\x05 \x01(\tR\fstakedTokens\x12-\n" +
+	"\x12nonclaimed_rewards\x18\x06 \x01(\tR\x11nonclaimedRewards\x122\n" +
+	"\arewards\x18\a \x03(\v2\x18.stonfi.v1.FarmNftRewardR\arewards\"A\n" +
+	"\rFarmNftReward\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\"\xd1\b\n" +
+	"\tOperation\x12.\n" +
+	"\x13protocol_fee_amount\x18\x01 \x01(\tR\x11protocolFeeAmount\x12*\n" +
+	"\x11fee_asset_address\x18\x02 \x01(\tR\x0ffeeAssetAddress\x12%\n" +
+	"\x0erouter_address\x18\x03 \x01(\tR\rrouterAddress\x12%\n" +
+	"\x0easset0_reserve\x18\x04 \x01(\tR\rasset0Reserve\x12*\n" +
+	"\x11pool_tx_timestamp\x18\x05 \x01(\tR\x0fpoolTxTimestamp\x12<\n" +
+	"\x1adestination_wallet_address\x18\x06 \x01(\tR\x18destinationWalletAddress\x12%\n" +
+	"\x0eoperation_type\x18\a \x01(\tR\roperationType\x12$\n" +
+	"\x0ewallet_tx_hash\x18\b \x01(\tR\fwalletTxHash\x12\x1b\n" +
+	"\texit_code\x18\t \x01(\tR\bexitCode\x12%\n" +
+	"\x0easset0_address\x18\n" +
+	" \x01(\tR\rasset0Address\x12#\n" +
+	"\rasset0_amount\x18\v \x01(\tR\fasset0Amount\x12!\n" +
+	"\fasset0_delta\x18\f \x01(\tR\vasset0Delta\x12.\n" +
+	"\x13wallet_tx_timestamp\x18\r \x01(\tR\x11walletTxTimestamp\x12 \n" +
+	"\fwallet_tx_lt\x18\x0e \x01(\tR\n" +
+	"walletTxLt\x12&\n" +
+	"\x0flp_token_supply\x18\x0f \x01(\tR\rlpTokenSupply\x12!\n" +
+	"\fasset1_delta\x18\x10 \x01(\tR\vasset1Delta\x12%\n" +
+	"\x0easset1_reserve\x18\x11 \x01(\tR\rasset1Reserve\x12$\n" +
+	"\x0elp_token_delta\x18\x12 \x01(\tR\flpTokenDelta\x12#\n" +
+	"\rasset1_amount\x18\x13 \x01(\tR\fasset1Amount\x12!\n" +
+	"\fpool_address\x18\x14 \x01(\tR\vpoolAddress\x12\"\n" +
+	"\rlp_fee_amount\x18\x15 \x01(\tR\vlpFeeAmount\x12 \n" +
+	"\fpool_tx_hash\x18\x16 \x01(\tR\n" +
+	"poolTxHash\x12.\n" +
+	"\x13referral_fee_amount\x18\x17 \x01(\tR\x11referralFeeAmount\x12)\n" +
+	"\x10referral_address\x18\x18 \x01(\tR\x0freferralAddress\x12%\n" +
+	"\x0ewallet_address\x18\x19 \x01(\tR\rwalletAddress\x12%\n" +
+	"\x0easset1_address\x18\x1a \x01(\tR\rasset1Address\x12\x1c\n" +
+	"\n" +
+	"pool_tx_lt\x18\x1b \x01(\x03R\bpoolTxLt\x12\x18\n" +
+	"\asuccess\x18\x1c \x01(\bR\asuccess\"\xa9\x01\n" +
+	"\rOperationInfo\x122\n" +
+	"\toperation\x18\x01 \x01(\v2\x14.stonfi.v1.OperationR\toperation\x121\n" +
+	"\vasset0_info\x18\x02 \x01(\v2\x10.stonfi.v1.AssetR\n" +
+	"asset0Info\x121\n" +
+	"\vasset1_info\x18\x03 \x01(\v2\x10.stonfi.v1.AssetR\n" +
+	"asset1Info\"\xac\x04\n" +
+	"\x0eSwapSimulation\x12\x1f\n" +
+	"\vask_address\x18\x01 \x01(\tR\n" +
+	"askAddress\x12*\n" +
+	"\x11ask_jetton_wallet\x18\x02 \x01(\tR\x0faskJettonWallet\x12\x1b\n" +
+	"\task_units\x18\x03 \x01(\tR\baskUnits\x12\x1f\n" +
+	"\vfee_address\x18\x04 \x01(\tR\n" +
+	"feeAddress\x12\x1f\n" +
+	"\vfee_percent\x18\x05 \x01(\tR\n" +
+	"feePercent\x12\x1b\n" +
+	"\tfee_units\x18\x06 \x01(\tR\bfeeUnits\x12\"\n" +
+	"\rmin_ask_units\x18\a \x01(\tR\vminAskUnits\x12#\n" +
+	"\roffer_address\x18\b \x01(\tR\fofferAddress\x12.\n" +
+	"\x13offer_jetton_wallet\x18\t \x01(\tR\x11offerJettonWallet\x12\x1f\n" +
+	"\voffer_units\x18\n" +
+	" \x01(\tR\n" +
+	"offerUnits\x12!\n" +
+	"\fpool_address\x18\v \x01(\tR\vpoolAddress\x12!\n" +
+	"\fprice_impact\x18\f \x01(\tR\vpriceImpact\x12%\n" +
+	"\x0erouter_address\x18\r \x01(\tR\rrouterAddress\x12-\n" +
+	"\x12slippage_tolerance\x18\x0e \x01(\tR\x11slippageTolerance\x12\x1b\n" +
+	"\tswap_rate\x18\x0f \x01(\tR\bswapRate\"+\n" +
+	"\x0fGetAssetRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\"\x13\n" +
+	"\x11ListAssetsRequest\">\n" +
+	"\x12ListAssetsResponse\x12(\n" +
+	"\x06assets\x18\x01 \x03(\v2\x10.stonfi.v1.AssetR\x06assets\"*\n" +
+	"\x0eGetPoolRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\"A\n" +
+	"\x10ListPoolsRequest\x12-\n" +
+	"\x12include_deprecated\x18\x01 \x01(\bR\x11includeDeprecated\":\n" +
+	"\x11ListPoolsResponse\x12%\n" +
+	"\x05pools\x18\x01 \x03(\v2\x0f.stonfi.v1.PoolR\x05pools\"*\n" +
+	"\x0eGetFarmRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\"5\n" +
+	"\x10ListFarmsRequest\x12!\n" +
+	"\fpool_address\x18\x01 \x01(\tR\vpoolAddress\":\n" +
+	"\x11ListFarmsResponse\x12%\n" +
+	"\x05farms\x18\x01 \x03(\v2\x0f.stonfi.v1.FarmR\x05farms\"\xba\x01\n" +
+	"\x13SimulateSwapRequest\x12#\n" +
+	"\roffer_address\x18\x01 \x01(\tR\fofferAddress\x12\x1f\n" +
+	"\vask_address\x18\x02 \x01(\tR\n" +
+	"askAddress\x12\x14\n" +
+	"\x05units\x18\x03 \x01(\tR\x05units\x12-\n" +
+	"\x12slippage_tolerance\x18\x04 \x01(\tR\x11slippageTolerance\x12\x18\n" +
+	"\areverse\x18\x05 \x01(\bR\areverse\"\x8b\x01\n" +
+	"\x11WatchPoolsRequest\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12!\n" +
+	"\fsend_initial\x18\x03 \x01(\bR\vsendInitial\"\x9b\x01\n" +
+	"\n" +
+	"PoolUpdate\x12#\n" +
+	"\x04pool\x18\x01 \x01(\v2\x0f.stonfi.v1.PoolR\x04pool\x12+\n" +
+	"\bprevious\x18\x02 \x01(\v2\x0f.stonfi.v1.PoolR\bprevious\x12;\n" +
+	"\vdetected_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"detectedAt\"\xc2\x01\n" +
+	"\x16WatchOperationsRequest\x12!\n" +
+	"\fpool_address\x18\x01 \x01(\tR\vpoolAddress\x12%\n" +
+	"\x0ewallet_address\x18\x02 \x01(\tR\rwalletAddress\x12'\n" +
+	"\x0foperation_types\x18\x03 \x03(\tR\x0eoperationTypes\x125\n" +
+	"\binterval\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\binterval2\xea\x04\n" +
+	"\x03Dex\x128\n" +
+	"\bGetAsset\x12\x1a.stonfi.v1.GetAssetRequest\x1a\x10.stonfi.v1.Asset\x12I\n" +
+	"\n" +
+	"ListAssets\x12\x1c.stonfi.v1.ListAssetsRequest\x1a\x1d.stonfi.v1.ListAssetsResponse\x125\n" +
+	"\aGetPool\x12\x19.stonfi.v1.GetPoolRequest\x1a\x0f.stonfi.v1.Pool\x12F\n" +
+	"\tListPools\x12\x1b.stonfi.v1.ListPoolsRequest\x1a\x1c.stonfi.v1.ListPoolsResponse\x125\n" +
+	"\aGetFarm\x12\x19.stonfi.v1.GetFarmRequest\x1a\x0f.stonfi.v1.Farm\x12F\n" +
+	"\tListFarms\x12\x1b.stonfi.v1.ListFarmsRequest\x1a\x1c.stonfi.v1.ListFarmsResponse\x12I\n" +
+	"\fSimulateSwap\x12\x1e.stonfi.v1.SimulateSwapRequest\x1a\x19.stonfi.v1.SwapSimulation\x12C\n" +
+	"\n" +
+	"WatchPools\x12\x1c.stonfi.v1.WatchPoolsRequest\x1a\x15.stonfi.v1.PoolUpdate0\x01\x12P\n" +
+	"\x0fWatchOperations\x12!.stonfi.v1.WatchOperationsRequest\x1a\x18.stonfi.v1.OperationInfo0\x01B/Z-github.com/itay747/go-stonfi/src/rpc/stonfipbb\x06proto3"
+
+var (
+	file_stonfi_proto_rawDescOnce sync.Once
+	file_stonfi_proto_rawDescData []byte
+)
+
+func file_stonfi_proto_rawDescGZIP() []byte {
+	file_stonfi_proto_rawDescOnce.Do(func() {
+		file_stonfi_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_stonfi_proto_rawDesc), len(file_stonfi_proto_rawDesc)))
+	})
+	return file_stonfi_proto_rawDescData
+}
+
+var file_stonfi_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_stonfi_proto_goTypes = []any{
+	(*Asset)(nil),                  // 0: stonfi.v1.Asset
+	(*Pool)(nil),                   // 1: stonfi.v1.Pool
+	(*Farm)(nil),                   // 2: stonfi.v1.Farm
+	(*FarmReward)(nil),             // 3: stonfi.v1.FarmReward
+	(*FarmNftInfo)(nil),            // 4: stonfi.v1.FarmNftInfo
+	(*FarmNftReward)(nil),          // 5: stonfi.v1.FarmNftReward
+	(*Operation)(nil),              // 6: stonfi.v1.Operation
+	(*OperationInfo)(nil),          // 7: stonfi.v1.OperationInfo
+	(*SwapSimulation)(nil),         // 8: stonfi.v1.SwapSimulation
+	(*GetAssetRequest)(nil),        // 9: stonfi.v1.GetAssetRequest
+	(*ListAssetsRequest)(nil),      // 10: stonfi.v1.ListAssetsRequest
+	(*ListAssetsResponse)(nil),     // 11: stonfi.v1.ListAssetsResponse
+	(*GetPoolRequest)(nil),         // 12: stonfi.v1.GetPoolRequest
+	(*ListPoolsRequest)(nil),       // 13: stonfi.v1.ListPoolsRequest
+	(*ListPoolsResponse)(nil),      // 14: stonfi.v1.ListPoolsResponse
+	(*GetFarmRequest)(nil),         // 15: stonfi.v1.GetFarmRequest
+	(*ListFarmsRequest)(nil),       // 16: stonfi.v1.ListFarmsRequest
+	(*ListFarmsResponse)(nil),      // 17: stonfi.v1.ListFarmsResponse
+	(*SimulateSwapRequest)(nil),    // 18: stonfi.v1.SimulateSwapRequest
+	(*WatchPoolsRequest)(nil),      // 19: stonfi.v1.WatchPoolsRequest
+	(*PoolUpdate)(nil),             // 20: stonfi.v1.PoolUpdate
+	(*WatchOperationsRequest)(nil), // 21: stonfi.v1.WatchOperationsRequest
+	(*durationpb.Duration)(nil),    // 22: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),  // 23: google.protobuf.Timestamp
+}
+var file_stonfi_proto_depIdxs = []int32{
+	4,  // 0: stonfi.v1.Farm.nft_infos:type_name -> stonfi.v1.FarmNftInfo
+	3,  // 1: stonfi.v1.Farm.rewards:type_name -> stonfi.v1.FarmReward
+	5,  // 2: stonfi.v1.FarmNftInfo.rewards:type_name -> stonfi.v1.FarmNftReward
+	6,  // 3: stonfi.v1.OperationInfo.operation:type_name -> stonfi.v1.Operation
+	0,  // 4: stonfi.v1.OperationInfo.asset0_info:type_name -> stonfi.v1.Asset
+	0,  // 5: stonfi.v1.OperationInfo.asset1_info:type_name -> stonfi.v1.Asset
+	0,  // 6: stonfi.v1.ListAssetsResponse.assets:type_name -> stonfi.v1.Asset
+	1,  // 7: stonfi.v1.ListPoolsResponse.pools:type_name -> stonfi.v1.Pool
+	2,  // 8: stonfi.v1.ListFarmsResponse.farms:type_name -> stonfi.v1.Farm
+	22, // 9: stonfi.v1.WatchPoolsRequest.interval:type_name -> google.protobuf.Duration
+	1,  // 10: stonfi.v1.PoolUpdate.pool:type_name -> stonfi.v1.Pool
+	1,  // 11: stonfi.v1.PoolUpdate.previous:type_name -> stonfi.v1.Pool
+	23, // 12: stonfi.v1.PoolUpdate.detected_at:type_name -> google.protobuf.Timestamp
+	22, // 13: stonfi.v1.WatchOperationsRequest.interval:type_name -> google.protobuf.Duration
+	9,  // 14: stonfi.v1.Dex.GetAsset:input_type -> stonfi.v1.GetAssetRequest
+	10, // 15: stonfi.v1.Dex.ListAssets:input_type -> stonfi.v1.ListAssetsRequest
+	12, // 16: stonfi.v1.Dex.GetPool:input_type -> stonfi.v1.GetPoolRequest
+	13, // 17: stonfi.v1.Dex.ListPools:input_type -> stonfi.v1.ListPoolsRequest
+	15, // 18: stonfi.v1.Dex.GetFarm:input_type -> stonfi.v1.GetFarmRequest
+	16, // 19: stonfi.v1.Dex.ListFarms:input_type -> stonfi.v1.ListFarmsRequest
+	18, // 20: stonfi.v1.Dex.SimulateSwap:input_type -> stonfi.v1.SimulateSwapRequest
+	19, // 21: stonfi.v1.Dex.WatchPools:input_type -> stonfi.v1.WatchPoolsRequest
+	21, // 22: stonfi.v1.Dex.WatchOperations:input_type -> stonfi.v1.WatchOperationsRequest
+	0,  // 23: stonfi.v1.Dex.GetAsset:output_type -> stonfi.v1.Asset
+	11, // 24: stonfi.v1.Dex.ListAssets:output_type -> stonfi.v1.ListAssetsResponse
+	1,  // 25: stonfi.v1.Dex.GetPool:output_type -> stonfi.v1.Pool
+	14, // 26: stonfi.v1.Dex.ListPools:output_type -> stonfi.v1.ListPoolsResponse
+	2,  // 27: stonfi.v1.Dex.GetFarm:output_type -> stonfi.v1.Farm
+	17, // 28: stonfi.v1.Dex.ListFarms:output_type -> stonfi.v1.ListFarmsResponse
+	8,  // 29: stonfi.v1.Dex.SimulateSwap:output_type -> stonfi.v1.SwapSimulation
+	20, // 30: stonfi.v1.Dex.WatchPools:output_type -> stonfi.v1.PoolUpdate
+	7,  // 31: stonfi.v1.Dex.WatchOperations:output_type -> stonfi.v1.OperationInfo
+	23, // [23:32] is the sub-list for method output_type
+	14, // [14:23] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_stonfi_proto_init() }
+func file_stonfi_proto_init() {
+	if File_stonfi_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stonfi_proto_rawDesc), len(file_stonfi_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_stonfi_proto_goTypes,
+		DependencyIndexes: file_stonfi_proto_depIdxs,
+		MessageInfos:      file_stonfi_proto_msgTypes,
+	}.Build()
+	File_stonfi_proto = out.File
+	file_stonfi_proto_goTypes = nil
+	file_stonfi_proto_depIdxs = nil
+}
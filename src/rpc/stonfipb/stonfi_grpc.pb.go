@@ -0,0 +1,446 @@
+// The Ston.fi DEX data service. Messages mirror the types of the
+// github.com/itay747/go-stonfi/src/types package, field for field; amounts
+// stay decimal strings in the tokens' smallest units, as the API sends them.
+//
+// Regenerate the Go code with `go generate ./src/rpc`.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: stonfi.proto
+
+package stonfipb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Dex_GetAsset_FullMethodName        = "/stonfi.v1.Dex/GetAsset"
+	Dex_ListAssets_FullMethodName      = "/stonfi.v1.Dex/ListAssets"
+	Dex_GetPool_FullMethodName         = "/stonfi.v1.Dex/GetPool"
+	Dex_ListPools_FullMethodName       = "/stonfi.v1.Dex/ListPools"
+	Dex_GetFarm_FullMethodName         = "/stonfi.v1.Dex/GetFarm"
+	Dex_ListFarms_FullMethodName       = "/stonfi.v1.Dex/ListFarms"
+	Dex_SimulateSwap_FullMethodName    = "/stonfi.v1.Dex/SimulateSwap"
+	Dex_WatchPools_FullMethodName      = "/stonfi.v1.Dex/WatchPools"
+	Dex_WatchOperations_FullMethodName = "/stonfi.v1.Dex/WatchOperations"
+)
+
+// DexClient is the client API for Dex service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DexClient interface {
+	GetAsset(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*Asset, error)
+	ListAssets(ctx context.Context, in *ListAssetsRequest, opts ...grpc.CallOption) (*ListAssetsResponse, error)
+	GetPool(ctx context.Context, in *GetPoolRequest, opts ...grpc.CallOption) (*Pool, error)
+	ListPools(ctx context.Context, in *ListPoolsRequest, opts ...grpc.CallOption) (*ListPoolsResponse, error)
+	GetFarm(ctx context.Context, in *GetFarmRequest, opts ...grpc.CallOption) (*Farm, error)
+	// ListFarms lists every farm, or those of pool_address if set.
+	ListFarms(ctx context.Context, in *ListFarmsRequest, opts ...grpc.CallOption) (*ListFarmsResponse, error)
+	SimulateSwap(ctx context.Context, in *SimulateSwapRequest, opts ...grpc.CallOption) (*SwapSimulation, error)
+	// WatchPools streams the pools as they change, polling every interval.
+	WatchPools(ctx context.Context, in *WatchPoolsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PoolUpdate], error)
+	// WatchOperations streams operations as the API reports them, polling
+	// every interval. Each operation is sent once.
+	WatchOperations(ctx context.Context, in *WatchOperationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OperationInfo], error)
+}
+
+type dexClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDexClient(cc grpc.ClientConnInterface) DexClient {
+	return &dexClient{cc}
+}
+
+func (c *dexClient) GetAsset(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*Asset, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Asset)
+	err := c.cc.Invoke(ctx, Dex_GetAsset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dexClient) ListAssets(ctx context.Context, in *ListAssetsRequest, opts ...grpc.CallOption) (*ListAssetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAssetsResponse)
+	err := c.cc.Invoke(ctx, Dex_ListAssets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dexClient) GetPool(ctx context.Context, in *GetPoolRequest, opts ...grpc.CallOption) (*Pool, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pool)
+	err := c.cc.Invoke(ctx, Dex_GetPool_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dexClient) ListPools(ctx context.Context, in *ListPoolsRequest, opts ...grpc.CallOption) (*ListPoolsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPoolsResponse)
+	err := c.cc.Invoke(ctx, Dex_ListPools_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dexClient) GetFarm(ctx context.Context, in *GetFarmRequest, opts ...grpc.CallOption) (*Farm, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Farm)
+	err := c.cc.Invoke(ctx, Dex_GetFarm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dexClient) ListFarms(ctx context.Context, in *ListFarmsRequest, opts ...grpc.CallOption) (*ListFarmsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFarmsResponse)
+	err := c.cc.Invoke(ctx, Dex_ListFarms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dexClient) SimulateSwap(ctx context.Context, in *SimulateSwapRequest, opts ...grpc.CallOption) (*SwapSimulation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SwapSimulation)
+	err := c.cc.Invoke(ctx, Dex_SimulateSwap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dexClient) WatchPools(ctx context.Context, in *WatchPoolsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PoolUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Dex_ServiceDesc.Streams[0], Dex_WatchPools_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchPoolsRequest, PoolUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Dex_WatchPoolsClient = grpc.ServerStreamingClient[PoolUpdate]
+
+func (c *dexClient) WatchOperations(ctx context.Context, in *WatchOperationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OperationInfo], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Dex_ServiceDesc.Streams[1], Dex_WatchOperations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchOperationsRequest, OperationInfo]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Dex_WatchOperationsClient = grpc.ServerStreamingClient[OperationInfo]
+
+// DexServer is the server API for Dex service.
+// All implementations must embed UnimplementedDexServer
+// for forward compatibility.
+type DexServer interface {
+	GetAsset(context.Context, *GetAssetRequest) (*Asset, error)
+	ListAssets(context.Context, *ListAssetsRequest) (*ListAssetsResponse, error)
+	GetPool(context.Context, *GetPoolRequest) (*Pool, error)
+	ListPools(context.Context, *ListPoolsRequest) (*ListPoolsResponse, error)
+	GetFarm(context.Context, *GetFarmRequest) (*Farm, error)
+	// ListFarms lists every farm, or those of pool_address if set.
+	ListFarms(context.Context, *ListFarmsRequest) (*ListFarmsResponse, error)
+	SimulateSwap(context.Context, *SimulateSwapRequest) (*SwapSimulation, error)
+	// WatchPools streams the pools as they change, polling every interval.
+	WatchPools(*WatchPoolsRequest, grpc.ServerStreamingServer[PoolUpdate]) error
+	// WatchOperations streams operations as the API reports them, polling
+	// every interval. Each operation is sent once.
+	WatchOperations(*WatchOperationsRequest, grpc.ServerStreamingServer[OperationInfo]) error
+	mustEmbedUnimplementedDexServer()
+}
+
+// UnimplementedDexServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDexServer struct{}
+
+func (UnimplementedDexServer) GetAsset(context.Context, *GetAssetRequest) (*Asset, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAsset not implemented")
+}
+func (UnimplementedDexServer) ListAssets(context.Context, *ListAssetsRequest) (*ListAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAssets not implemented")
+}
+func (UnimplementedDexServer) GetPool(context.Context, *GetPoolRequest) (*Pool, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPool not implemented")
+}
+func (UnimplementedDexServer) ListPools(context.Context, *ListPoolsRequest) (*ListPoolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPools not implemented")
+}
+func (UnimplementedDexServer) GetFarm(context.Context, *GetFarmRequest) (*Farm, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFarm not implemented")
+}
+func (UnimplementedDexServer) ListFarms(context.Context, *ListFarmsRequest) (*ListFarmsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFarms not implemented")
+}
+func (UnimplementedDexServer) SimulateSwap(context.Context, *SimulateSwapRequest) (*SwapSimulation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateSwap not implemented")
+}
+func (UnimplementedDexServer) WatchPools(*WatchPoolsRequest, grpc.ServerStreamingServer[PoolUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPools not implemented")
+}
+func (UnimplementedDexServer) WatchOperations(*WatchOperationsRequest, grpc.ServerStreamingServer[OperationInfo]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOperations not implemented")
+}
+func (UnimplementedDexServer) mustEmbedUnimplementedDexServer() {}
+func (UnimplementedDexServer) testEmbeddedByValue()             {}
+
+// UnsafeDexServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DexServer will
+// result in compilation errors.
+type UnsafeDexServer interface {
+	mustEmbedUnimplementedDexServer()
+}
+
+func RegisterDexServer(s grpc.ServiceRegistrar, srv DexServer) {
+	// If the following call pancis, it indicates UnimplementedDexServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Dex_ServiceDesc, srv)
+}
+
+func _Dex_GetAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DexServer).GetAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Dex_GetAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DexServer).GetAsset(ctx, req.(*GetAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dex_ListAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DexServer).ListAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Dex_ListAssets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DexServer).ListAssets(ctx, req.(*ListAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dex_GetPool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DexServer).GetPool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Dex_GetPool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DexServer).GetPool(ctx, req.(*GetPoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dex_ListPools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPoolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DexServer).ListPools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Dex_ListPools_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DexServer).ListPools(ctx, req.(*ListPoolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dex_GetFarm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFarmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DexServer).GetFarm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Dex_GetFarm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DexServer).GetFarm(ctx, req.(*GetFarmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dex_ListFarms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFarmsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DexServer).ListFarms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Dex_ListFarms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DexServer).ListFarms(ctx, req.(*ListFarmsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dex_SimulateSwap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateSwapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DexServer).SimulateSwap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Dex_SimulateSwap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DexServer).SimulateSwap(ctx, req.(*SimulateSwapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dex_WatchPools_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPoolsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DexServer).WatchPools(m, &grpc.GenericServerStream[WatchPoolsRequest, PoolUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Dex_WatchPoolsServer = grpc.ServerStreamingServer[PoolUpdate]
+
+func _Dex_WatchOperations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchOperationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DexServer).WatchOperations(m, &grpc.GenericServerStream[WatchOperationsRequest, OperationInfo]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Dex_WatchOperationsServer = grpc.ServerStreamingServer[OperationInfo]
+
+// Dex_ServiceDesc is the grpc.ServiceDesc for Dex service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Dex_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "stonfi.v1.Dex",
+	HandlerType: (*DexServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAsset",
+			Handler:    _Dex_GetAsset_Handler,
+		},
+		{
+			MethodName: "ListAssets",
+			Handler:    _Dex_ListAssets_Handler,
+		},
+		{
+			MethodName: "GetPool",
+			Handler:    _Dex_GetPool_Handler,
+		},
+		{
+			MethodName: "ListPools",
+			Handler:    _Dex_ListPools_Handler,
+		},
+		{
+			MethodName: "GetFarm",
+			Handler:    _Dex_GetFarm_Handler,
+		},
+		{
+			MethodName: "ListFarms",
+			Handler:    _Dex_ListFarms_Handler,
+		},
+		{
+			MethodName: "SimulateSwap",
+			Handler:    _Dex_SimulateSwap_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPools",
+			Handler:       _Dex_WatchPools_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchOperations",
+			Handler:       _Dex_WatchOperations_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "stonfi.proto",
+}
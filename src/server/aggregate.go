@@ -0,0 +1,366 @@
+package server
+
+import (
+	"context"
+	"iter"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/arbitrage"
+	"github.com/itay747/go-stonfi/src/candles"
+	"github.com/itay747/go-stonfi/src/depth"
+	"github.com/itay747/go-stonfi/src/export"
+	"github.com/itay747/go-stonfi/src/farms"
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+const (
+	// maxRouteHops and maxRouteEdges bound the route search, which simulates
+	// up to maxRouteEdges to the power of the hops paths.
+	maxRouteHops = 3
+	// maxRouteEdges is the number of pools out of each asset the route
+	// search follows, the deepest first.
+	maxRouteEdges = 8
+	// maxCandleSpan bounds the operations a candles request fetches.
+	maxCandleSpan = 7 * 24 * time.Hour
+)
+
+var (
+	getPools        = mustLookup("GET", "/pools")
+	getPool         = mustLookup("GET", "/pools/{addr_str}")
+	getWalletAssets = mustLookup("GET", "/wallets/{addr_str}/assets")
+	getWalletFarms  = mustLookup("GET", "/wallets/{addr_str}/farms")
+	getAsset        = mustLookup("GET", "/assets/{addr_str}")
+)
+
+func mustLookup(method, path string) openapi.Operation {
+	op, ok := openapi.Lookup(method, path)
+	if !ok {
+		panic("server: no operation " + method + " " + path)
+	}
+	return op
+}
+
+// Hop is one swap of a route.
+type Hop struct {
+	Pool        string  `json:"pool_address"`
+	Router      string  `json:"router_address"`
+	Offer       string  `json:"offer_address"`
+	Ask         string  `json:"ask_address"`
+	OfferUnits  string  `json:"offer_units"`
+	AskUnits    string  `json:"ask_units"`
+	PriceImpact float64 `json:"price_impact"`
+}
+
+// Route is a path of swaps from one asset to another, simulated on the
+// pools' current reserves.
+type Route struct {
+	Hops       []Hop  `json:"hops"`
+	OfferUnits string `json:"offer_units"`
+	AskUnits   string `json:"ask_units"`
+	// PriceImpact compounds the impact of every hop.
+	PriceImpact float64 `json:"price_impact"`
+}
+
+// routes serves the best routes, by output, for offering units of
+// offer_address for ask_address, through at most max_hops pools (default 2).
+func (s *Server) routes(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.aggregate(w, r, func(ctx context.Context) (interface{}, error) {
+		offer, ask := q.Get("offer_address"), q.Get("ask_address")
+		if offer == "" || ask == "" {
+			return nil, badRequest("offer_address and ask_address are required")
+		}
+		units, ok := new(big.Int).SetString(q.Get("units"), 10)
+		if !ok || units.Sign() <= 0 {
+			return nil, badRequest("units must be a positive integer")
+		}
+		hops, err := intParam(q, "max_hops", 2, 1, maxRouteHops)
+		if err != nil {
+			return nil, err
+		}
+		limit, err := intParam(q, "limit", 5, 1, 100)
+		if err != nil {
+			return nil, err
+		}
+		var pools types.PoolListResponse
+		if err := s.get(ctx, getPools, url.Values{}, &pools); err != nil {
+			return nil, err
+		}
+		return findRoutes(arbitrage.NewGraph(pools.PoolList, false), offer, ask, units, hops, limit), nil
+	})
+}
+
+// findRoutes simulates the paths of at most maxHops distinct pools and
+// assets from offer to ask, and returns the limit best. Out of each asset,
+// only the maxRouteEdges pools with the deepest reserves of it are followed,
+// along with any pool straight to ask.
+func findRoutes(g *arbitrage.Graph, offer, ask string, units *big.Int, maxHops, limit int) []Route {
+	target := utils.AddressKey(ask)
+	pruned := map[string][]*arbitrage.Edge{}
+	edges := func(asset string) []*arbitrage.Edge {
+		if edges, ok := pruned[asset]; ok {
+			return edges
+		}
+		edges := g.Edges(asset)
+		if len(edges) > maxRouteEdges {
+			edges = slices.Clone(edges)
+			// Edges out of one asset all offer it, so their offer reserves
+			// compare.
+			sort.SliceStable(edges, func(i, j int) bool {
+				return edges[i].Side.OfferReserve.Cmp(edges[j].Side.OfferReserve) > 0
+			})
+			kept := edges[:maxRouteEdges:maxRouteEdges]
+			for _, e := range edges[maxRouteEdges:] {
+				if e.Ask == target {
+					kept = append(kept, e)
+				}
+			}
+			edges = kept
+		}
+		pruned[asset] = edges
+		return edges
+	}
+	routes := []Route{}
+	visited := map[string]bool{utils.AddressKey(offer): true}
+	var path []Hop
+	var walk func(asset string, in *big.Int, kept float64)
+	walk = func(asset string, in *big.Int, kept float64) {
+		for _, e := range edges(asset) {
+			if visited[e.Ask] {
+				continue
+			}
+			q, err := e.Side.Swap(in, false)
+			if err != nil || q.AskUnits.Sign() <= 0 {
+				continue
+			}
+			path = append(path, Hop{
+				Pool: e.Pool.Address, Router: e.Pool.RouterAddress, Offer: e.Pool.Token0Address, Ask: e.Pool.Token1Address,
+				OfferUnits: in.String(), AskUnits: q.AskUnits.String(), PriceImpact: q.PriceImpact,
+			})
+			if utils.AddressKey(e.Pool.Token0Address) != e.Offer {
+				path[len(path)-1].Offer, path[len(path)-1].Ask = e.Pool.Token1Address, e.Pool.Token0Address
+			}
+			if e.Ask == target {
+				routes = append(routes, Route{
+					Hops:        append([]Hop(nil), path...),
+					OfferUnits:  units.String(),
+					AskUnits:    q.AskUnits.String(),
+					PriceImpact: 1 - kept*(1-q.PriceImpact),
+				})
+			} else if len(path) < maxHops {
+				visited[e.Ask] = true
+				walk(e.Ask, q.AskUnits, kept*(1-q.PriceImpact))
+				visited[e.Ask] = false
+			}
+			path = path[:len(path)-1]
+		}
+	}
+	walk(utils.AddressKey(offer), units, 1)
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, _ := new(big.Int).SetString(routes[i].AskUnits, 10)
+		b, _ := new(big.Int).SetString(routes[j].AskUnits, 10)
+		if c := a.Cmp(b); c != 0 {
+			return c > 0
+		}
+		return len(routes[i].Hops) < len(routes[j].Hops)
+	})
+	if len(routes) > limit {
+		routes = routes[:limit]
+	}
+	return routes
+}
+
+// Holding is a wallet's balance of an asset.
+type Holding struct {
+	Address  string  `json:"contract_address"`
+	Symbol   string  `json:"symbol"`
+	Balance  string  `json:"balance"`
+	Amount   float64 `json:"amount"`
+	PriceUsd float64 `json:"price_usd"`
+	ValueUsd float64 `json:"value_usd"`
+}
+
+// Portfolio is what a wallet holds: its balances, LP tokens included, and
+// its farm stakes.
+type Portfolio struct {
+	Wallet    string           `json:"wallet_address"`
+	Assets    []Holding        `json:"assets"`
+	Farms     []farms.Position `json:"farms"`
+	AssetsUsd float64          `json:"assets_usd"`
+	// FarmsUsd is the value of the LP tokens staked and the rewards pending.
+	FarmsUsd float64 `json:"farms_usd"`
+	TotalUsd float64 `json:"total_usd"`
+}
+
+func (s *Server) portfolio(w http.ResponseWriter, r *http.Request) {
+	wallet := r.PathValue("addr_str")
+	s.aggregate(w, r, func(ctx context.Context) (interface{}, error) {
+		var balances types.SearchAssetsResponse
+		if err := s.get(ctx, getWalletAssets, url.Values{"addr_str": {wallet}}, &balances); err != nil {
+			return nil, err
+		}
+		p := Portfolio{Wallet: wallet, Assets: []Holding{}, Farms: []farms.Position{}}
+		for _, a := range balances.AssetList {
+			units, ok := new(big.Int).SetString(a.Balance, 10)
+			if !ok || units.Sign() <= 0 {
+				continue
+			}
+			h := Holding{Address: a.ContractAddress, Symbol: a.Symbol, Balance: a.Balance}
+			h.Amount = utils.ToUnits(units, a.Decimals)
+			h.PriceUsd = utils.ParseFloat(a.DexPriceUsd)
+			h.ValueUsd = h.Amount * h.PriceUsd
+			p.Assets = append(p.Assets, h)
+			p.AssetsUsd += h.ValueUsd
+		}
+		sort.SliceStable(p.Assets, func(i, j int) bool { return p.Assets[i].ValueUsd > p.Assets[j].ValueUsd })
+
+		positions, err := s.farmPositions(ctx, wallet)
+		if err != nil {
+			return nil, err
+		}
+		for _, position := range positions {
+			if position.Unstaked() {
+				continue
+			}
+			p.Farms = append(p.Farms, position)
+			p.FarmsUsd += position.StakedUsd + position.PendingUsd
+		}
+		p.TotalUsd = p.AssetsUsd + p.FarmsUsd
+		return p, nil
+	})
+}
+
+// farmPositions returns the stakes of wallet, fetching its farms and their
+// reward tokens through the cache.
+func (s *Server) farmPositions(ctx context.Context, wallet string) ([]farms.Position, error) {
+	var walletFarms types.FarmListResponse
+	if err := s.get(ctx, getWalletFarms, url.Values{"addr_str": {wallet}}, &walletFarms); err != nil {
+		return nil, err
+	}
+	var assets []types.Asset
+	for _, token := range farms.RewardTokens(walletFarms.Farms) {
+		var asset types.AssetResponse
+		if err := s.get(ctx, getAsset, url.Values{"addr_str": {token}}, &asset); err != nil {
+			return nil, err
+		}
+		if asset.Asset.ContractAddress == "" {
+			asset.Asset.ContractAddress = token
+		}
+		assets = append(assets, asset.Asset)
+	}
+	return farms.WalletPositions(walletFarms.Farms, assets, s.now())
+}
+
+// depth serves the depth of a pool at the levels, in percent, of the
+// levels parameter, with a curve of curve points.
+func (s *Server) depth(w http.ResponseWriter, r *http.Request) {
+	address := r.PathValue("addr_str")
+	q := r.URL.Query()
+	s.aggregate(w, r, func(ctx context.Context) (interface{}, error) {
+		opts := depth.Options{CurvePoints: -1}
+		if v := q.Get("levels"); v != "" {
+			for _, level := range strings.Split(v, ",") {
+				percent, err := strconv.ParseFloat(strings.TrimSpace(level), 64)
+				if err != nil || percent <= 0 || percent >= 100 {
+					return nil, badRequest("invalid level %q, want a percentage", level)
+				}
+				opts.Levels = append(opts.Levels, percent/100)
+			}
+		}
+		points, err := intParam(q, "curve", 0, 0, 1000)
+		if err != nil {
+			return nil, err
+		}
+		if points > 0 {
+			opts.CurvePoints = points
+		}
+		var pool types.PoolResponse
+		if err := s.get(ctx, getPool, url.Values{"addr_str": {address}}, &pool); err != nil {
+			return nil, err
+		}
+		d, err := depth.Analyze(pool.Pool, opts)
+		if err != nil {
+			return nil, badRequest("pool %s: %v", address, err)
+		}
+		return d, nil
+	})
+}
+
+// candles serves the candles of a pool between since and until (default
+// the last 24 hours), of interval (default 1h).
+func (s *Server) candles(w http.ResponseWriter, r *http.Request) {
+	address := utils.AddressKey(r.PathValue("addr_str"))
+	q := r.URL.Query()
+	s.aggregate(w, r, func(ctx context.Context) (interface{}, error) {
+		until := s.now().UTC().Truncate(time.Second)
+		if v := q.Get("until"); v != "" {
+			t, err := openapi.ParseTime(v)
+			if err != nil {
+				return nil, badRequest("invalid until: %v", err)
+			}
+			until = t
+		}
+		since := until.Add(-24 * time.Hour)
+		if v := q.Get("since"); v != "" {
+			t, err := openapi.ParseTime(v)
+			if err != nil {
+				return nil, badRequest("invalid since: %v", err)
+			}
+			since = t
+		}
+		if !since.Before(until) || until.Sub(since) > maxCandleSpan {
+			return nil, badRequest("since must be before until, and at most %s before it", maxCandleSpan)
+		}
+		interval := time.Hour
+		if v := q.Get("interval"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < time.Minute {
+				return nil, badRequest("invalid interval %q, want a duration of at least 1m", v)
+			}
+			interval = d
+		}
+
+		ops := export.Operations(ctx, s.client, since, until)
+		ofPool := func(yield func(types.OperationInfo, error) bool) {
+			for info, err := range ops {
+				if err != nil {
+					yield(info, upstreamError(err))
+					return
+				}
+				if utils.AddressKey(info.Operation.PoolAddress) == address && !yield(info, nil) {
+					return
+				}
+			}
+		}
+		result := []candles.Candle{}
+		for candle, err := range candles.Build(iter.Seq2[types.OperationInfo, error](ofPool), interval) {
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, candle)
+		}
+		return result, nil
+	})
+}
+
+func intParam(q url.Values, name string, fallback, min, max int) (int, error) {
+	v := q.Get(name)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		return 0, badRequest("%s must be an integer from %d to %d", name, min, max)
+	}
+	return n, nil
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxEntries is the cache size above which expired entries are swept.
+const maxEntries = 4096
+
+// cache holds responses for a TTL and coalesces concurrent requests for the
+// same key into one upstream fetch.
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]entry
+	calls   map[string]*call
+	now     func() time.Time
+}
+
+type entry struct {
+	body    []byte
+	expires time.Time
+}
+
+// call is a fetch in flight; done is closed once body and err are set.
+type call struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+func newCache(now func() time.Time) *cache {
+	return &cache{entries: map[string]entry{}, calls: map[string]*call{}, now: now}
+}
+
+// get returns the body cached for key, or fetches it, sharing the fetch with
+// every caller asking for key meanwhile. Successful fetches are cached for
+// ttl; zero caches nothing. The fetch is not cancelled with ctx, as other
+// callers may be waiting for it; a caller whose ctx is done stops waiting.
+func (c *cache) get(ctx context.Context, key string, ttl time.Duration, fetch func(context.Context) ([]byte, error)) (body []byte, hit bool, err error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && c.now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.body, true, nil
+	}
+	cl, inFlight := c.calls[key]
+	if !inFlight {
+		cl = &call{done: make(chan struct{})}
+		c.calls[key] = cl
+	}
+	c.mu.Unlock()
+
+	if !inFlight {
+		go func() {
+			cl.body, cl.err = fetch(context.WithoutCancel(ctx))
+			c.mu.Lock()
+			delete(c.calls, key)
+			if cl.err == nil && ttl > 0 {
+				c.store(key, entry{body: cl.body, expires: c.now().Add(ttl)})
+			}
+			c.mu.Unlock()
+			close(cl.done)
+		}()
+	}
+	select {
+	case <-cl.done:
+		// A coalesced response counts as a hit: it cost no upstream call.
+		return cl.body, inFlight, cl.err
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+// store adds an entry, first sweeping expired ones if the cache is full.
+// c.mu must be held.
+func (c *cache) store(key string, e entry) {
+	if len(c.entries) >= maxEntries {
+		now := c.now()
+		for k, old := range c.entries {
+			if !now.Before(old.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = e
+}
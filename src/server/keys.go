@@ -0,0 +1,121 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeyHeader is the header clients send their API key in. The api_key query
+// parameter is accepted too.
+const KeyHeader = "X-API-Key"
+
+// APIKey is a client allowed to use the server.
+type APIKey struct {
+	Name string `yaml:"name"`
+	Key  string `yaml:"key"`
+	// Quota is how many requests the client may make per Window; zero is
+	// unlimited.
+	Quota int `yaml:"quota"`
+	// Window defaults to 1 minute.
+	Window time.Duration `yaml:"window"`
+}
+
+// LoadKeys reads a YAML list of API keys.
+func LoadKeys(r io.Reader) ([]APIKey, error) {
+	var keys []APIKey
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&keys); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("server: decoding keys: %w", err)
+	}
+	seen := map[string]bool{}
+	for i, k := range keys {
+		if k.Key == "" {
+			return nil, fmt.Errorf("server: key %d (%s) is empty", i, k.Name)
+		}
+		if seen[k.Key] {
+			return nil, fmt.Errorf("server: key of %s is used twice", k.Name)
+		}
+		seen[k.Key] = true
+		if k.Quota < 0 || k.Window < 0 {
+			return nil, fmt.Errorf("server: key of %s: quota and window must not be negative", k.Name)
+		}
+	}
+	return keys, nil
+}
+
+// quotas counts the requests of each key in fixed windows.
+type quotas struct {
+	mu    sync.Mutex
+	keys  map[string]APIKey
+	usage map[string]*usage
+	now   func() time.Time
+}
+
+type usage struct {
+	start time.Time
+	count int
+}
+
+func newQuotas(keys []APIKey, now func() time.Time) *quotas {
+	q := &quotas{keys: map[string]APIKey{}, usage: map[string]*usage{}, now: now}
+	for _, k := range keys {
+		if k.Window <= 0 {
+			k.Window = time.Minute
+		}
+		q.keys[k.Key] = k
+	}
+	return q
+}
+
+// middleware rejects requests without a known key, or over its quota. With
+// no keys configured, every request is let through.
+func (q *quotas) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(q.keys) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := r.Header.Get(KeyHeader)
+		if key == "" {
+			key = r.URL.Query().Get("api_key")
+		}
+		k, ok := q.keys[key]
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "missing or unknown API key")
+			return
+		}
+		if k.Quota > 0 {
+			remaining, reset := q.take(k)
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(k.Quota))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(remaining, 0)))
+			if remaining < 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(reset.Seconds()+0.999)))
+				writeError(w, http.StatusTooManyRequests, fmt.Sprintf("quota of %d requests per %s exceeded", k.Quota, k.Window))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// take counts a request of k and returns how many it has left in the
+// current window, negative once over, and the time until the window ends.
+func (q *quotas) take(k APIKey) (remaining int, reset time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := q.now()
+	u, ok := q.usage[k.Key]
+	if !ok || !now.Before(u.start.Add(k.Window)) {
+		u = &usage{start: now}
+		q.usage[k.Key] = u
+	}
+	u.count++
+	return k.Quota - u.count, u.start.Add(k.Window).Sub(now)
+}
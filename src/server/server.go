@@ -0,0 +1,226 @@
+// Package server is an HTTP proxy for the Ston.fi API. It serves the same v1
+// routes from one shared client, caching GET responses and coalescing
+// identical requests in flight, and adds aggregated endpoints the upstream
+// lacks: swap routes, wallet portfolios, pool depth and candles. Clients can
+// be required to present API keys, each with a request quota.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/openapi"
+)
+
+// Options configures a Server.
+type Options struct {
+	// TTL is how long GET responses are cached. Defaults to 30 seconds;
+	// negative disables caching, though identical requests in flight are
+	// still coalesced.
+	TTL time.Duration
+	// Keys are the clients allowed to use the server. Without keys, the
+	// server is open.
+	Keys []APIKey
+}
+
+// Server proxies and aggregates the Ston.fi API.
+type Server struct {
+	client *client.StonfiClient
+	opts   Options
+	cache  *cache
+	quotas *quotas
+	spec   []byte
+	now    func() time.Time
+}
+
+// New returns a server answering from c.
+func New(c *client.StonfiClient, opts Options) (*Server, error) {
+	if opts.TTL == 0 {
+		opts.TTL = 30 * time.Second
+	}
+	if opts.TTL < 0 {
+		opts.TTL = 0
+	}
+	s := &Server{client: c, opts: opts, now: time.Now}
+	now := func() time.Time { return s.now() }
+	s.cache = newCache(now)
+	s.quotas = newQuotas(opts.Keys, now)
+	spec, err := buildSpec(len(opts.Keys) > 0)
+	if err != nil {
+		return nil, err
+	}
+	s.spec = spec
+	return s, nil
+}
+
+// Handler returns the server's routes. `/openapi.json` and `/healthz` need
+// no API key.
+func (s *Server) Handler() http.Handler {
+	api := http.NewServeMux()
+	for _, op := range openapi.Operations {
+		api.Handle(op.Method+" /v1"+op.Path, s.proxy(op))
+	}
+	api.HandleFunc("GET /v1/routes", s.routes)
+	api.HandleFunc("GET /v1/wallets/{addr_str}/portfolio", s.portfolio)
+	api.HandleFunc("GET /v1/pools/{addr_str}/depth", s.depth)
+	api.HandleFunc("GET /v1/pools/{addr_str}/candles", s.candles)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(s.spec)
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	mux.Handle("/", s.quotas.middleware(api))
+	return mux
+}
+
+// proxy forwards op upstream. GET responses go through the cache.
+func (s *Server) proxy(op openapi.Operation) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := url.Values{}
+		for key, values := range r.URL.Query() {
+			if key != "api_key" {
+				params[key] = values
+			}
+		}
+		for _, name := range op.PathParams {
+			params.Set(name, r.PathValue(name))
+		}
+		fetch := func(ctx context.Context) ([]byte, error) {
+			var raw json.RawMessage
+			if err := s.call(ctx, op, params, &raw); err != nil {
+				return nil, err
+			}
+			return raw, nil
+		}
+		if op.Method != http.MethodGet {
+			body, err := fetch(r.Context())
+			s.respond(w, body, false, err)
+			return
+		}
+		body, hit, err := s.cache.get(r.Context(), cacheKey(op.Method, op.Path, params), s.opts.TTL, fetch)
+		s.respond(w, body, hit, err)
+	}
+}
+
+// aggregate serves a computed response through the cache, keyed by the
+// request's path and query.
+func (s *Server) aggregate(w http.ResponseWriter, r *http.Request, compute func(ctx context.Context) (interface{}, error)) {
+	params := r.URL.Query()
+	params.Del("api_key")
+	body, hit, err := s.cache.get(r.Context(), cacheKey(r.Method, r.URL.Path, params), s.opts.TTL, func(ctx context.Context) ([]byte, error) {
+		v, err := compute(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	})
+	s.respond(w, body, hit, err)
+}
+
+// get fetches op through the cache and decodes it into response, for the
+// aggregated endpoints.
+func (s *Server) get(ctx context.Context, op openapi.Operation, params url.Values, response interface{}) error {
+	body, _, err := s.cache.get(ctx, cacheKey(op.Method, op.Path, params), s.opts.TTL, func(ctx context.Context) ([]byte, error) {
+		var raw json.RawMessage
+		if err := s.call(ctx, op, params, &raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("decoding %s: %w", op.ID, err)
+	}
+	return nil
+}
+
+// call calls op upstream, turning an error into an *httpError with the
+// upstream status code.
+func (s *Server) call(ctx context.Context, op openapi.Operation, params url.Values, response interface{}) error {
+	if err := s.client.CallOperation(ctx, op, params, response); err != nil {
+		return upstreamError(err)
+	}
+	return nil
+}
+
+// httpError is an error with the status code it is answered with.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string { return e.err.Error() }
+func (e *httpError) Unwrap() error { return e.err }
+
+func badRequest(format string, args ...interface{}) error {
+	return &httpError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+// upstreamError passes on the upstream's client errors; anything else is a
+// bad gateway.
+func upstreamError(err error) error {
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+		return &httpError{status: apiErr.StatusCode, err: err}
+	}
+	return &httpError{status: http.StatusBadGateway, err: err}
+}
+
+func (s *Server) respond(w http.ResponseWriter, body []byte, hit bool, err error) {
+	if err != nil {
+		var he *httpError
+		switch {
+		case errors.As(err, &he):
+			writeError(w, he.status, err.Error())
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+			writeError(w, http.StatusGatewayTimeout, err.Error())
+		default:
+			writeError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if hit {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		w.Header().Set("X-Cache", "MISS")
+	}
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(client.ErrorResponse{Message: message, Code: status})
+}
+
+// cacheKey identifies a request by method, path and sorted parameters.
+func cacheKey(method, path string, params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(method + " " + path)
+	for _, k := range keys {
+		for _, v := range params[k] {
+			b.WriteString("&" + url.QueryEscape(k) + "=" + url.QueryEscape(v))
+		}
+	}
+	return b.String()
+}
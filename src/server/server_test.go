@@ -0,0 +1,303 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/amm"
+	"github.com/itay747/go-stonfi/src/arbitrage"
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	wallet      = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+	// notAddress is an asset only reachable through two pools.
+	notAddress = "EQAvlWFDxGF2lXm67y4yzC17wYKD9A0guwPkMs1gOsM__NOT"
+)
+
+func pool(address, token0, token1, reserve0, reserve1 string) types.Pool {
+	return types.Pool{Address: address, Token0Address: token0, Token1Address: token1, Reserve0: reserve0, Reserve1: reserve1, LpFee: "20", ProtocolFee: "10"}
+}
+
+var fixtures = stonfitest.Fixtures{
+	Assets: []types.Asset{
+		{ContractAddress: tonAddress, Symbol: "TON", Decimals: 9, DexPriceUsd: "5"},
+		{ContractAddress: usdtAddress, Symbol: "USDT", Decimals: 6, DexPriceUsd: "1"},
+	},
+	Pools: []types.Pool{
+		pool(poolAddress, tonAddress, usdtAddress, "1000000000000", "5000000000"),
+		pool("ton-not", tonAddress, notAddress, "1000000000000", "1000000000000"),
+		pool("not-usdt", notAddress, usdtAddress, "1000000000000", "5000000000"),
+	},
+	Wallets: map[string]stonfitest.Wallet{wallet: {
+		Balances: map[string]string{tonAddress: "2000000000", usdtAddress: "3000000"},
+		Farms: []types.Farm{{
+			MinterAddress:      "farm",
+			PoolAddress:        poolAddress,
+			RewardTokenAddress: tonAddress,
+			LockedTotalLP:      "1000000000000",
+			LockedTotalLPUSD:   "1000",
+			NftInfos: []types.FarmNftInfo{{
+				Address:         "nft",
+				Status:          "active",
+				CreateTimestamp: "2024-01-01T00:00:00",
+				StakedTokens:    "2000000000",
+				Rewards:         []types.FarmNftReward{{Address: tonAddress, Amount: "1000000000"}},
+			}},
+		}},
+	}},
+}
+
+func newServer(t *testing.T, opts Options) (*stonfitest.Server, *httptest.Server, *Server) {
+	upstream := stonfitest.NewServerWithFixtures(fixtures)
+	s, err := New(upstream.Client(), opts)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	proxy := httptest.NewServer(s.Handler())
+	t.Cleanup(func() {
+		proxy.Close()
+		upstream.Close()
+	})
+	return upstream, proxy, s
+}
+
+func get(t *testing.T, url string, header ...string) (*http.Response, string) {
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestProxyCaches(t *testing.T) {
+	upstream, proxy, s := newServer(t, Options{TTL: time.Minute})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	resp, body := get(t, proxy.URL+"/v1/pools/"+poolAddress)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "MISS", resp.Header.Get("X-Cache"))
+	var p types.PoolResponse
+	assert.NoError(t, json.Unmarshal([]byte(body), &p))
+	assert.Equal(t, "1000000000000", p.Pool.Reserve0)
+
+	resp, _ = get(t, proxy.URL+"/v1/pools/"+poolAddress)
+	assert.Equal(t, "HIT", resp.Header.Get("X-Cache"))
+	assert.Len(t, upstream.Requests(), 1)
+
+	now = now.Add(time.Minute)
+	resp, _ = get(t, proxy.URL+"/v1/pools/"+poolAddress)
+	assert.Equal(t, "MISS", resp.Header.Get("X-Cache"), "expired")
+
+	resp, body = get(t, proxy.URL+"/v1/pools/EQnope")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "the upstream's client errors are passed on")
+	assert.Contains(t, body, `"code":404`)
+}
+
+func TestProxyCoalesces(t *testing.T) {
+	upstream, proxy, _ := newServer(t, Options{TTL: -1})
+	upstream.SetLatency(100 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, _ := get(t, proxy.URL+"/v1/assets")
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, upstream.Requests(), 1)
+
+	get(t, proxy.URL+"/v1/assets")
+	assert.Len(t, upstream.Requests(), 2, "nothing is cached with a negative TTL")
+}
+
+func TestKeysAndQuotas(t *testing.T) {
+	keys, err := LoadKeys(strings.NewReader("- {name: pricing, key: k1, quota: 2, window: 1m}\n- {name: backoffice, key: k2}\n"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	upstream, proxy, s := newServer(t, Options{Keys: keys})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	resp, _ := get(t, proxy.URL+"/v1/assets")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, _ = get(t, proxy.URL+"/v1/assets", KeyHeader, "wrong")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = get(t, proxy.URL+"/v1/assets", KeyHeader, "k1")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("X-RateLimit-Remaining"))
+	resp, _ = get(t, proxy.URL+"/v1/assets?api_key=k1")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = get(t, proxy.URL+"/v1/assets", KeyHeader, "k1")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+
+	resp, _ = get(t, proxy.URL+"/v1/assets", KeyHeader, "k2")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "quotas are per key")
+	now = now.Add(time.Minute)
+	resp, _ = get(t, proxy.URL+"/v1/assets", KeyHeader, "k1")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "a new window")
+
+	for _, r := range upstream.Requests() {
+		assert.NotContains(t, r, "api_key")
+	}
+	resp, _ = get(t, proxy.URL+"/openapi.json")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "the spec needs no key")
+
+	_, err = LoadKeys(strings.NewReader("- {name: a, key: k}\n- {name: b, key: k}\n"))
+	assert.ErrorContains(t, err, "used twice")
+}
+
+func TestRoutes(t *testing.T) {
+	_, proxy, _ := newServer(t, Options{})
+	resp, body := get(t, proxy.URL+"/v1/routes?offer_address="+tonAddress+"&ask_address="+usdtAddress+"&units=1000000000")
+	if !assert.Equal(t, http.StatusOK, resp.StatusCode, body) {
+		return
+	}
+	var routes []Route
+	assert.NoError(t, json.Unmarshal([]byte(body), &routes))
+	if !assert.Len(t, routes, 2) {
+		return
+	}
+	direct := routes[0]
+	if assert.Len(t, direct.Hops, 1, "the direct pool is deeper") {
+		q, _ := amm.SimulateSwap(fixtures.Pools[0], tonAddress, big.NewInt(1000000000), false)
+		assert.Equal(t, q.AskUnits.String(), direct.AskUnits)
+		assert.Equal(t, usdtAddress, direct.Hops[0].Ask)
+	}
+	if assert.Len(t, routes[1].Hops, 2) {
+		assert.Equal(t, routes[1].Hops[0].AskUnits, routes[1].Hops[1].OfferUnits)
+		assert.Greater(t, routes[1].PriceImpact, routes[1].Hops[0].PriceImpact)
+	}
+
+	resp, _ = get(t, proxy.URL+"/v1/routes?offer_address="+tonAddress+"&ask_address="+usdtAddress+"&units=1&max_hops=1&limit=5")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = get(t, proxy.URL+"/v1/routes?offer_address="+tonAddress+"&ask_address="+usdtAddress+"&units=1&max_hops=9")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestFindRoutesPrunes(t *testing.T) {
+	// TON reaches USDT through maxRouteEdges+2 intermediate assets, over
+	// pools of increasing depth, and through one shallow direct pool.
+	var pools []types.Pool
+	for i := 0; i < maxRouteEdges+2; i++ {
+		asset := fmt.Sprintf("asset-%d", i)
+		pools = append(pools,
+			pool(fmt.Sprintf("in-%d", i), tonAddress, asset, fmt.Sprintf("%d000000000000", i+1), "1000000000000"),
+			pool(fmt.Sprintf("out-%d", i), asset, usdtAddress, "1000000000000", "5000000000"))
+	}
+	pools = append(pools, pool("direct", tonAddress, usdtAddress, "1000000000", "5000000"))
+
+	routes := findRoutes(arbitrage.NewGraph(pools, false), tonAddress, usdtAddress, big.NewInt(1000000), 2, 100)
+	var via []string
+	for _, r := range routes {
+		via = append(via, r.Hops[0].Pool)
+	}
+	assert.Len(t, via, maxRouteEdges+1)
+	assert.Contains(t, via, "direct", "pools straight to the ask asset are always followed")
+	assert.NotContains(t, via, "in-0", "the shallowest pools are pruned")
+	assert.NotContains(t, via, "in-1")
+}
+
+func TestPortfolio(t *testing.T) {
+	_, proxy, _ := newServer(t, Options{})
+	resp, body := get(t, proxy.URL+"/v1/wallets/"+wallet+"/portfolio")
+	if !assert.Equal(t, http.StatusOK, resp.StatusCode, body) {
+		return
+	}
+	var p Portfolio
+	assert.NoError(t, json.Unmarshal([]byte(body), &p))
+	if assert.Len(t, p.Assets, 2) {
+		assert.Equal(t, "TON", p.Assets[0].Symbol, "largest first")
+		assert.Equal(t, 2.0, p.Assets[0].Amount, "scaled by the asset's decimals")
+		assert.Equal(t, 10.0, p.Assets[0].ValueUsd)
+		assert.Equal(t, 3.0, p.Assets[1].Amount)
+	}
+	if assert.Len(t, p.Farms, 1) {
+		assert.InDelta(t, 2, p.Farms[0].StakedUsd, 1e-9, "2 of 1000 LP tokens worth $1000")
+		assert.InDelta(t, 5, p.Farms[0].PendingUsd, 1e-9, "1 TON pending")
+	}
+	assert.InDelta(t, 20, p.TotalUsd, 1e-9)
+
+	resp, _ = get(t, proxy.URL+"/v1/wallets/"+wallet+"/farms")
+	assert.Equal(t, "HIT", resp.Header.Get("X-Cache"), "the portfolio fetches farms through the cache")
+}
+
+func TestDepthAndCandles(t *testing.T) {
+	upstream, proxy, s := newServer(t, Options{})
+	resp, body := get(t, proxy.URL+"/v1/pools/"+poolAddress+"/depth?levels=1,5&curve=10")
+	if assert.Equal(t, http.StatusOK, resp.StatusCode, body) {
+		var d struct {
+			Directions []struct{ Levels, Curve []json.RawMessage }
+		}
+		assert.NoError(t, json.Unmarshal([]byte(body), &d))
+		if assert.Len(t, d.Directions, 2) {
+			assert.Len(t, d.Directions[0].Levels, 2)
+			assert.Len(t, d.Directions[0].Curve, 10)
+		}
+	}
+	resp, _ = get(t, proxy.URL+"/v1/pools/"+poolAddress+"/depth?levels=200")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	for _, ts := range []string{"2024-01-01T00:10:00", "2024-01-01T00:50:00", "2024-01-01T01:10:00"} {
+		upstream.AddOperation(types.OperationInfo{Operation: types.Operation{
+			OperationType: "swap", Success: true, PoolAddress: poolAddress, PoolTxTimestamp: ts,
+			Asset0Address: tonAddress, Asset1Address: usdtAddress, Asset0Delta: "1000000000", Asset1Delta: "-5000000",
+		}, Asset0Info: fixtures.Assets[0], Asset1Info: fixtures.Assets[1]})
+	}
+	s.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+	resp, body = get(t, proxy.URL+"/v1/pools/"+poolAddress+"/candles?interval=1h")
+	if assert.Equal(t, http.StatusOK, resp.StatusCode, body) {
+		var candles []struct{ Trades int }
+		assert.NoError(t, json.Unmarshal([]byte(body), &candles))
+		if assert.Len(t, candles, 2) {
+			assert.Equal(t, 2, candles[0].Trades)
+		}
+	}
+	resp, _ = get(t, proxy.URL+"/v1/pools/"+poolAddress+"/candles?since=2023-01-01T00:00:00")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "more than 7 days")
+}
+
+func TestSpec(t *testing.T) {
+	_, proxy, _ := newServer(t, Options{Keys: []APIKey{{Name: "a", Key: "k"}}})
+	_, body := get(t, proxy.URL+"/openapi.json")
+	var spec struct {
+		Paths      map[string]json.RawMessage
+		Components struct {
+			Schemas         map[string]json.RawMessage
+			SecuritySchemes map[string]json.RawMessage
+		}
+	}
+	assert.NoError(t, json.Unmarshal([]byte(body), &spec))
+	for _, path := range []string{"/v1/pools/{addr_str}", "/v1/routes", "/v1/wallets/{addr_str}/portfolio", "/v1/pools/{addr_str}/depth", "/v1/pools/{addr_str}/candles"} {
+		assert.Contains(t, spec.Paths, path)
+	}
+	assert.Contains(t, spec.Components.Schemas, "Route")
+	assert.Contains(t, spec.Components.Schemas, "PoolResponse")
+	assert.Contains(t, spec.Components.SecuritySchemes, "apiKey")
+}
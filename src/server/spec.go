@@ -0,0 +1,46 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/itay747/go-stonfi/src/openapi"
+)
+
+// aggregateSpec describes the aggregated endpoints: their paths and the
+// schemas they add.
+//
+//go:embed aggregate.json
+var aggregateSpec []byte
+
+// buildSpec returns the server's OpenAPI description: the vendored one,
+// served from the proxy, with the aggregated endpoints and, if withKeys, the
+// API key scheme.
+func buildSpec(withKeys bool) ([]byte, error) {
+	var spec, extra map[string]interface{}
+	if err := json.Unmarshal(openapi.Spec, &spec); err != nil {
+		return nil, fmt.Errorf("server: decoding vendored spec: %w", err)
+	}
+	if err := json.Unmarshal(aggregateSpec, &extra); err != nil {
+		return nil, fmt.Errorf("server: decoding aggregated spec: %w", err)
+	}
+	spec["info"] = map[string]interface{}{"title": "go-stonfi proxy", "version": "v1"}
+	spec["servers"] = []interface{}{map[string]interface{}{"url": "/"}}
+	paths := spec["paths"].(map[string]interface{})
+	for path, item := range extra["paths"].(map[string]interface{}) {
+		paths[path] = item
+	}
+	components := spec["components"].(map[string]interface{})
+	schemas := components["schemas"].(map[string]interface{})
+	for name, schema := range extra["schemas"].(map[string]interface{}) {
+		schemas[name] = schema
+	}
+	if withKeys {
+		components["securitySchemes"] = map[string]interface{}{
+			"apiKey": map[string]interface{}{"type": "apiKey", "in": "header", "name": KeyHeader},
+		}
+		spec["security"] = []interface{}{map[string]interface{}{"apiKey": []interface{}{}}}
+	}
+	return json.MarshalIndent(spec, "", "  ")
+}
@@ -0,0 +1,52 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+)
+
+// Collect fetches pools, assets and farms from c and saves them as one
+// snapshot. Nothing is saved if any call fails.
+func Collect(ctx context.Context, c *client.StonfiClient, store *Store) (Snapshot, error) {
+	snap := Snapshot{TakenAt: time.Now().UTC()}
+
+	pools, err := c.GetPools(ctx)
+	if err != nil {
+		return snap, fmt.Errorf("fetching pools: %w", err)
+	}
+	assets, err := c.GetAssets(ctx)
+	if err != nil {
+		return snap, fmt.Errorf("fetching assets: %w", err)
+	}
+	farms, err := c.GetFarms(ctx)
+	if err != nil {
+		return snap, fmt.Errorf("fetching farms: %w", err)
+	}
+	snap.Pools, snap.Assets, snap.Farms = pools.PoolList, assets.AssetList, farms.Farms
+
+	if _, err := store.Save(ctx, snap); err != nil {
+		return snap, err
+	}
+	return snap, nil
+}
+
+// Run collects a snapshot every interval until ctx is done. Each outcome is
+// passed to report, if set.
+func Run(ctx context.Context, c *client.StonfiClient, store *Store, interval time.Duration, report func(Snapshot, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		snap, err := Collect(ctx, c, store)
+		if report != nil {
+			report(snap, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/itay747/go-stonfi/src/migrate"
+)
+
+// migrations are applied in order by migrate.Apply. Never edit a released
+// migration, add a new one.
+var migrations = []string{
+	// 1: snapshots of pools, assets and farms. Token amounts are integers of
+	// up to 256 bits, so they are stored as decimal text.
+	`
+	CREATE TABLE snapshots (
+		id       INTEGER PRIMARY KEY,
+		taken_at INTEGER NOT NULL -- Unix milliseconds
+	);
+	CREATE INDEX snapshots_taken_at ON snapshots (taken_at);
+
+	CREATE TABLE pool_snapshots (
+		snapshot_id         INTEGER NOT NULL REFERENCES snapshots (id) ON DELETE CASCADE,
+		address             TEXT    NOT NULL,
+		router_address      TEXT    NOT NULL,
+		token0_address      TEXT    NOT NULL,
+		token1_address      TEXT    NOT NULL,
+		reserve0            TEXT,
+		reserve1            TEXT,
+		lp_total_supply     TEXT,
+		lp_total_supply_usd REAL,
+		lp_price_usd        REAL,
+		apy_1d              REAL,
+		apy_7d              REAL,
+		apy_30d             REAL,
+		lp_fee              INTEGER,
+		protocol_fee        INTEGER,
+		ref_fee             INTEGER,
+		deprecated          INTEGER NOT NULL,
+		PRIMARY KEY (snapshot_id, address)
+	);
+	CREATE INDEX pool_snapshots_address ON pool_snapshots (address, snapshot_id);
+
+	CREATE TABLE asset_snapshots (
+		snapshot_id           INTEGER NOT NULL REFERENCES snapshots (id) ON DELETE CASCADE,
+		address               TEXT    NOT NULL,
+		symbol                TEXT    NOT NULL,
+		decimals              INTEGER NOT NULL,
+		dex_price_usd         REAL,
+		third_party_price_usd REAL,
+		blacklisted           INTEGER NOT NULL,
+		deprecated            INTEGER NOT NULL,
+		PRIMARY KEY (snapshot_id, address)
+	);
+	CREATE INDEX asset_snapshots_address ON asset_snapshots (address, snapshot_id);
+
+	CREATE TABLE farm_snapshots (
+		snapshot_id          INTEGER NOT NULL REFERENCES snapshots (id) ON DELETE CASCADE,
+		minter_address       TEXT    NOT NULL,
+		pool_address         TEXT    NOT NULL,
+		status               TEXT    NOT NULL,
+		apy                  REAL,
+		locked_total_lp      TEXT,
+		locked_total_lp_usd  REAL,
+		min_stake_duration_s INTEGER,
+		PRIMARY KEY (snapshot_id, minter_address)
+	);
+	CREATE INDEX farm_snapshots_pool ON farm_snapshots (pool_address, snapshot_id);
+	`,
+}
+
+// migrate applies the migrations the database has not seen yet.
+func (s *Store) migrate(ctx context.Context) error {
+	if err := migrate.Apply(ctx, s.db, migrations); err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	return nil
+}
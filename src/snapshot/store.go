@@ -0,0 +1,245 @@
+// Package snapshot records periodic snapshots of pools, assets and farms in a
+// SQLite database and queries their history.
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+	_ "modernc.org/sqlite"
+)
+
+// ErrNoData is returned by queries with no snapshot to answer from.
+var ErrNoData = errors.New("snapshot: no data")
+
+// Store is a SQLite database of snapshots.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens or creates the database at path and migrates it to the latest
+// schema. Use ":memory:" for a throwaway database.
+func Open(path string) (*Store, error) {
+	// Pragmas in the DSN apply to every connection the pool opens, so the
+	// cascades Prune relies on survive a connection being replaced.
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	db, err := sql.Open("sqlite", path+sep+"_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: opening %s: %w", path, err)
+	}
+	// SQLite serializes writers; one connection also keeps ":memory:" shared.
+	db.SetMaxOpenConns(1)
+	s := &Store{db: db}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Snapshot is the state of the DEX at a point in time.
+type Snapshot struct {
+	TakenAt time.Time
+	Pools   []types.Pool
+	Assets  []types.Asset
+	Farms   []types.Farm
+}
+
+// Save stores snap in a single transaction and returns its id.
+func (s *Store) Save(ctx context.Context, snap Snapshot) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO snapshots (taken_at) VALUES (?)`, snap.TakenAt.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("snapshot: inserting snapshot: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("snapshot: %w", err)
+	}
+
+	for _, p := range snap.Pools {
+		_, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO pool_snapshots (
+			snapshot_id, address, router_address, token0_address, token1_address,
+			reserve0, reserve1, lp_total_supply, lp_total_supply_usd, lp_price_usd,
+			apy_1d, apy_7d, apy_30d, lp_fee, protocol_fee, ref_fee, deprecated
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, utils.AddressKey(p.Address), utils.AddressKey(p.RouterAddress), utils.AddressKey(p.Token0Address), utils.AddressKey(p.Token1Address),
+			nullString(p.Reserve0), nullString(p.Reserve1), nullString(p.LpTotalSupply), nullFloat(p.LpTotalSupplyUsd), nullFloat(p.LpPriceUsd),
+			nullFloat(p.Apy1D), nullFloat(p.Apy7D), nullFloat(p.Apy30D), nullInt(p.LpFee), nullInt(p.ProtocolFee), nullInt(p.RefFee), p.Deprecated,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("snapshot: inserting pool %s: %w", p.Address, err)
+		}
+	}
+	for _, a := range snap.Assets {
+		_, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO asset_snapshots (
+			snapshot_id, address, symbol, decimals, dex_price_usd, third_party_price_usd, blacklisted, deprecated
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, utils.AddressKey(a.ContractAddress), a.Symbol, a.Decimals, nullFloat(a.DexPriceUsd), nullFloat(a.ThirdPartyPriceUsd), a.Blacklisted, a.Deprecated,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("snapshot: inserting asset %s: %w", a.ContractAddress, err)
+		}
+	}
+	for _, f := range snap.Farms {
+		_, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO farm_snapshots (
+			snapshot_id, minter_address, pool_address, status, apy, locked_total_lp, locked_total_lp_usd, min_stake_duration_s
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, utils.AddressKey(f.MinterAddress), utils.AddressKey(f.PoolAddress), f.Status, nullFloat(f.APY), nullString(f.LockedTotalLP), nullFloat(f.LockedTotalLPUSD), nullInt(f.MinStakeDurationS),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("snapshot: inserting farm %s: %w", f.MinterAddress, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("snapshot: %w", err)
+	}
+	return id, nil
+}
+
+// PoolPoint is the state of a pool in one snapshot. Fields the API left
+// empty are nil.
+type PoolPoint struct {
+	Time             time.Time
+	Reserve0         *big.Int
+	Reserve1         *big.Int
+	LpTotalSupply    *big.Int
+	LpTotalSupplyUsd *float64
+	LpPriceUsd       *float64
+	Apy1D            *float64
+	Apy7D            *float64
+	Apy30D           *float64
+	Deprecated       bool
+}
+
+// PoolHistory returns the snapshots of the pool at address taken in
+// [from, to], oldest first. The address may be in any form.
+func (s *Store) PoolHistory(ctx context.Context, address string, from, to time.Time) ([]PoolPoint, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT s.taken_at, p.reserve0, p.reserve1, p.lp_total_supply, p.lp_total_supply_usd,
+			p.lp_price_usd, p.apy_1d, p.apy_7d, p.apy_30d, p.deprecated
+		FROM pool_snapshots p JOIN snapshots s ON s.id = p.snapshot_id
+		WHERE p.address = ? AND s.taken_at BETWEEN ? AND ?
+		ORDER BY s.taken_at`,
+		utils.AddressKey(address), from.UnixMilli(), to.UnixMilli(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: querying pool history: %w", err)
+	}
+	defer rows.Close()
+
+	var points []PoolPoint
+	for rows.Next() {
+		var (
+			takenAt                int64
+			reserve0, reserve1, lp sql.NullString
+			lpUsd, lpPrice         sql.NullFloat64
+			apy1d, apy7d, apy30d   sql.NullFloat64
+			point                  PoolPoint
+		)
+		if err := rows.Scan(&takenAt, &reserve0, &reserve1, &lp, &lpUsd, &lpPrice, &apy1d, &apy7d, &apy30d, &point.Deprecated); err != nil {
+			return nil, fmt.Errorf("snapshot: scanning pool history: %w", err)
+		}
+		point.Time = time.UnixMilli(takenAt).UTC()
+		point.Reserve0, point.Reserve1, point.LpTotalSupply = bigOrNil(reserve0), bigOrNil(reserve1), bigOrNil(lp)
+		point.LpTotalSupplyUsd, point.LpPriceUsd = floatOrNil(lpUsd), floatOrNil(lpPrice)
+		point.Apy1D, point.Apy7D, point.Apy30D = floatOrNil(apy1d), floatOrNil(apy7d), floatOrNil(apy30d)
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("snapshot: querying pool history: %w", err)
+	}
+	return points, nil
+}
+
+// AssetPrice is the DEX price of an asset in a snapshot.
+type AssetPrice struct {
+	Time        time.Time
+	DexPriceUsd float64
+}
+
+// AssetPriceAt returns the price of the asset at address from the latest
+// snapshot taken at or before t that has one. It returns ErrNoData if there
+// is none.
+func (s *Store) AssetPriceAt(ctx context.Context, address string, t time.Time) (AssetPrice, error) {
+	var takenAt int64
+	var price AssetPrice
+	err := s.db.QueryRowContext(ctx, `
+		SELECT s.taken_at, a.dex_price_usd
+		FROM asset_snapshots a JOIN snapshots s ON s.id = a.snapshot_id
+		WHERE a.address = ? AND s.taken_at <= ? AND a.dex_price_usd IS NOT NULL
+		ORDER BY s.taken_at DESC LIMIT 1`,
+		utils.AddressKey(address), t.UnixMilli(),
+	).Scan(&takenAt, &price.DexPriceUsd)
+	if errors.Is(err, sql.ErrNoRows) {
+		return AssetPrice{}, fmt.Errorf("%w: no price for %s at %s", ErrNoData, address, t.Format(time.RFC3339))
+	}
+	if err != nil {
+		return AssetPrice{}, fmt.Errorf("snapshot: querying asset price: %w", err)
+	}
+	price.Time = time.UnixMilli(takenAt).UTC()
+	return price, nil
+}
+
+// Prune deletes the snapshots taken before t.
+func (s *Store) Prune(ctx context.Context, t time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM snapshots WHERE taken_at < ?`, t.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("snapshot: pruning: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullFloat(s string) sql.NullFloat64 {
+	v, err := strconv.ParseFloat(s, 64)
+	return sql.NullFloat64{Float64: v, Valid: err == nil}
+}
+
+func nullInt(s string) sql.NullInt64 {
+	v, err := strconv.ParseInt(s, 10, 64)
+	return sql.NullInt64{Int64: v, Valid: err == nil}
+}
+
+func bigOrNil(s sql.NullString) *big.Int {
+	if !s.Valid {
+		return nil
+	}
+	n, ok := new(big.Int).SetString(s.String, 10)
+	if !ok {
+		return nil
+	}
+	return n
+}
+
+func floatOrNil(f sql.NullFloat64) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
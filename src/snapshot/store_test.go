@@ -0,0 +1,145 @@
+package snapshot
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	maxUint256  = "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+)
+
+func testPool(reserve0, apy string) types.Pool {
+	return types.Pool{
+		Address:       poolAddress,
+		Token0Address: tonAddress,
+		Token1Address: usdtAddress,
+		Reserve0:      reserve0,
+		Reserve1:      maxUint256,
+		LpFee:         "20",
+		Apy7D:         apy,
+	}
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := Open(":memory:")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, snap := range []Snapshot{
+		{TakenAt: t0, Pools: []types.Pool{testPool("100", "0.5")}, Assets: []types.Asset{{ContractAddress: tonAddress, Symbol: "TON", DexPriceUsd: "5"}}},
+		{TakenAt: t0.Add(time.Hour), Pools: []types.Pool{testPool("200", "")}, Assets: []types.Asset{{ContractAddress: tonAddress, Symbol: "TON"}}},
+		{TakenAt: t0.Add(2 * time.Hour), Pools: []types.Pool{testPool("300", "0.7")}, Assets: []types.Asset{{ContractAddress: tonAddress, Symbol: "TON", DexPriceUsd: "6"}}},
+	} {
+		id, err := store.Save(ctx, snap)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(i+1), id)
+	}
+
+	t.Run("should return pool history in range, by any address form", func(t *testing.T) {
+		history, err := store.PoolHistory(ctx, "0:fc4c9f311160754a99d113877cf583b78e5d16d048819cd4b820168769499d7e", t0, t0.Add(time.Hour))
+		assert.NoError(t, err)
+		if !assert.Len(t, history, 2) {
+			return
+		}
+		assert.Equal(t, t0, history[0].Time)
+		assert.Equal(t, "100", history[0].Reserve0.String())
+		assert.Equal(t, maxUint256, history[0].Reserve1.String())
+		if assert.NotNil(t, history[0].Apy7D) {
+			assert.Equal(t, 0.5, *history[0].Apy7D)
+		}
+		assert.Nil(t, history[1].Apy7D)
+		assert.Nil(t, history[1].LpPriceUsd)
+	})
+
+	t.Run("should return the latest known price", func(t *testing.T) {
+		price, err := store.AssetPriceAt(ctx, tonAddress, t0.Add(90*time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, AssetPrice{Time: t0, DexPriceUsd: 5}, price)
+
+		price, err = store.AssetPriceAt(ctx, tonAddress, t0.Add(3*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, 6.0, price.DexPriceUsd)
+
+		_, err = store.AssetPriceAt(ctx, tonAddress, t0.Add(-time.Second))
+		assert.ErrorIs(t, err, ErrNoData)
+	})
+
+	t.Run("should prune old snapshots", func(t *testing.T) {
+		n, err := store.Prune(ctx, t0.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		history, err := store.PoolHistory(ctx, poolAddress, t0, t0.Add(3*time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, history, 2)
+	})
+}
+
+func TestMigrationsAreIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stonfi.db")
+	store, err := Open(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = store.Save(context.Background(), Snapshot{TakenAt: time.Now()})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Close())
+
+	store, err = Open(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+	var version int
+	assert.NoError(t, store.db.QueryRow(`PRAGMA user_version`).Scan(&version))
+	assert.Equal(t, len(migrations), version)
+
+	// Every connection has foreign keys on, not just the one that migrated.
+	store.db.SetMaxIdleConns(0)
+	for range 2 {
+		var enabled int
+		assert.NoError(t, store.db.QueryRow(`PRAGMA foreign_keys`).Scan(&enabled))
+		assert.Equal(t, 1, enabled)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	ctx := context.Background()
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{
+		Assets: []types.Asset{{ContractAddress: tonAddress, Symbol: "TON", DexPriceUsd: "5"}},
+		Pools:  []types.Pool{testPool("100", "0.5")},
+	})
+	defer s.Close()
+	store, err := Open(":memory:")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer store.Close()
+
+	snap, err := Collect(ctx, s.Client(), store)
+	assert.NoError(t, err)
+	history, err := store.PoolHistory(ctx, poolAddress, snap.TakenAt.Add(-time.Second), snap.TakenAt.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	s.Inject(stonfitest.Fault{Path: "/v1/farms", Status: http.StatusInternalServerError, Times: 1})
+	_, err = Collect(ctx, s.Client(), store)
+	assert.ErrorContains(t, err, "fetching farms")
+	history, err = store.PoolHistory(ctx, poolAddress, snap.TakenAt.Add(-time.Second), time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, history, 1, "failed collections save nothing")
+}
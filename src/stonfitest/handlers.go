@@ -0,0 +1,585 @@
+package stonfitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/amm"
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/openapi"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/assets", s.getAssets)
+	mux.HandleFunc("GET /v1/assets/{addr_str}", s.getAsset)
+	mux.HandleFunc("POST /v1/assets/search", s.searchAssets)
+	mux.HandleFunc("POST /v1/assets/query", s.queryAssets)
+	mux.HandleFunc("GET /v1/farms", s.getFarms)
+	mux.HandleFunc("GET /v1/farms/{addr_str}", s.getFarm)
+	mux.HandleFunc("GET /v1/farms_by_pool/{pool_addr_str}", s.getFarmsByPool)
+	mux.HandleFunc("GET /v1/markets", s.getMarkets)
+	mux.HandleFunc("GET /v1/pools", s.getPools)
+	mux.HandleFunc("GET /v1/pools/{addr_str}", s.getPool)
+	mux.HandleFunc("POST /v1/swap/simulate", s.simulateSwap(false))
+	mux.HandleFunc("POST /v1/reverse_swap/simulate", s.simulateSwap(true))
+	mux.HandleFunc("GET /v1/swap/status", s.getSwapStatus)
+	mux.HandleFunc("GET /v1/stats/dex", s.getDexStats)
+	mux.HandleFunc("GET /v1/stats/operations", s.getOperationStats)
+	mux.HandleFunc("GET /v1/stats/pool", s.getPoolStats)
+	mux.HandleFunc("GET /v1/wallets/{addr_str}/assets", s.getWalletAssets)
+	mux.HandleFunc("GET /v1/wallets/{addr_str}/assets/{asset_address}", s.getWalletAsset)
+	mux.HandleFunc("GET /v1/wallets/{addr_str}/farms", s.getWalletFarms)
+	mux.HandleFunc("GET /v1/wallets/{addr_str}/farms/{farm_address}", s.getWalletFarm)
+	mux.HandleFunc("GET /v1/wallets/{addr_str}/pools", s.getWalletPools)
+	mux.HandleFunc("GET /v1/wallets/{addr_str}/pools/{pool_address}", s.getWalletPool)
+	mux.HandleFunc("GET /v1/wallets/{addr_str}/operations", s.getWalletOperations)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+		s.mu.Unlock()
+
+		if f := s.matchFault(r); f != nil {
+			if f.Latency > 0 {
+				select {
+				case <-time.After(f.Latency):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if f.Status != 0 {
+				if f.Status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "1")
+				}
+				writeError(w, f.Status, http.StatusText(f.Status))
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(client.ErrorResponse{Message: message, Code: status})
+}
+
+func (s *Server) getAssets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, types.AssetListResponse{AssetList: append([]types.Asset{}, s.assets...)})
+}
+
+func (s *Server) getAsset(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	asset, ok := s.findAsset(r.PathValue("addr_str"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "asset not found")
+		return
+	}
+	writeJSON(w, types.AssetResponse{Asset: asset})
+}
+
+func (s *Server) searchAssets(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if !q.Has("search_string") {
+		writeError(w, http.StatusBadRequest, "search_string is required")
+		return
+	}
+	search := strings.ToLower(q.Get("search_string"))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []types.AssetList
+	for _, a := range s.assets {
+		if strings.Contains(strings.ToLower(a.Symbol), search) || strings.Contains(strings.ToLower(a.DisplayName), search) {
+			list = append(list, s.walletAsset(a, q.Get("wallet_address")))
+		}
+	}
+	writeJSON(w, types.SearchAssetsResponse{AssetList: list})
+}
+
+// queryAssets ignores the condition and returns every listed asset plus the
+// unconditional ones.
+func (s *Server) queryAssets(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	unconditional := map[string]bool{}
+	for _, a := range q["unconditional_assets"] {
+		unconditional[utils.AddressKey(a)] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []types.AssetList
+	for _, a := range s.assets {
+		if unconditional[utils.AddressKey(a.ContractAddress)] || (!a.Deprecated && !a.Blacklisted) {
+			list = append(list, s.walletAsset(a, q.Get("wallet_address")))
+		}
+	}
+	writeJSON(w, types.QueryWalletBallanceResponse{AssetList: list})
+}
+
+func (s *Server) getFarms(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, types.FarmListResponse{Farms: append([]types.Farm{}, s.farms...)})
+}
+
+func (s *Server) getFarm(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range s.farms {
+		if utils.SameAddress(f.MinterAddress, r.PathValue("addr_str")) {
+			writeJSON(w, types.FarmResponse{Farm: f})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "farm not found")
+}
+
+func (s *Server) getFarmsByPool(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	farms := []types.Farm{}
+	for _, f := range s.farms {
+		if utils.SameAddress(f.PoolAddress, r.PathValue("pool_addr_str")) {
+			farms = append(farms, f)
+		}
+	}
+	writeJSON(w, types.FarmListResponse{Farms: farms})
+}
+
+func (s *Server) getMarkets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pairs := [][]string{}
+	for _, p := range s.pools {
+		if !p.Deprecated {
+			pairs = append(pairs, []string{p.Token0Address, p.Token1Address})
+		}
+	}
+	writeJSON(w, types.MarketInfoResponse{Pairs: pairs})
+}
+
+func (s *Server) getPools(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, types.PoolListResponse{PoolList: append([]types.Pool{}, s.pools...)})
+}
+
+func (s *Server) getPool(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pool, ok := s.findPool(r.PathValue("addr_str"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "pool not found")
+		return
+	}
+	writeJSON(w, types.PoolResponse{Pool: pool})
+}
+
+// simulateSwap quotes against the deepest non-deprecated pool of the pair.
+func (s *Server) simulateSwap(reverse bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		for _, name := range []string{"offer_address", "ask_address", "units", "slippage_tolerance"} {
+			if q.Get(name) == "" {
+				writeError(w, http.StatusBadRequest, name+" is required")
+				return
+			}
+		}
+		offer, ask := q.Get("offer_address"), q.Get("ask_address")
+		units, ok := new(big.Int).SetString(q.Get("units"), 10)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "invalid units")
+			return
+		}
+		slippage, ok := new(big.Rat).SetString(q.Get("slippage_tolerance"))
+		if !ok || slippage.Sign() < 0 || slippage.Cmp(big.NewRat(1, 1)) > 0 {
+			writeError(w, http.StatusBadRequest, "invalid slippage_tolerance")
+			return
+		}
+		withReferral := q.Get("referral_address") != ""
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		pool, ok := s.pairPool(offer, ask)
+		if !ok {
+			writeError(w, http.StatusNotFound, "no pool for pair")
+			return
+		}
+		var quote *amm.Quote
+		var err error
+		if reverse {
+			quote, err = amm.SimulateReverseSwap(pool, offer, units, withReferral)
+		} else {
+			quote, err = amm.SimulateSwap(pool, offer, units, withReferral)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// min_ask_units = ask_units * (1 - slippage), rounded down
+		minAsk := new(big.Rat).Mul(new(big.Rat).SetInt(quote.AskUnits), new(big.Rat).Sub(big.NewRat(1, 1), slippage))
+		minAskUnits := new(big.Int).Quo(minAsk.Num(), minAsk.Denom())
+
+		offerAsset, _ := s.findAsset(offer)
+		askAsset, _ := s.findAsset(ask)
+		rate := new(big.Rat).SetFrac(quote.AskUnits, quote.OfferUnits)
+		rate.Mul(rate, new(big.Rat).SetFrac(pow10(offerAsset.Decimals), pow10(askAsset.Decimals)))
+		lpFee, _ := strconv.ParseInt(pool.LpFee, 10, 64)
+		protocolFee, _ := strconv.ParseInt(pool.ProtocolFee, 10, 64)
+
+		writeJSON(w, types.SwapSimulationResponse{
+			OfferAddress:      offer,
+			AskAddress:        ask,
+			OfferUnits:        quote.OfferUnits.String(),
+			AskUnits:          quote.AskUnits.String(),
+			MinAskUnits:       minAskUnits.String(),
+			FeeAddress:        pool.ProtocolFeeAddress,
+			FeeUnits:          new(big.Int).Add(quote.ProtocolFeeUnits, quote.RefFeeUnits).String(),
+			FeePercent:        big.NewRat(lpFee+protocolFee, amm.FeeDivider).FloatString(4),
+			PoolAddress:       pool.Address,
+			PriceImpact:       strconv.FormatFloat(quote.PriceImpact, 'f', -1, 64),
+			RouterAddress:     pool.RouterAddress,
+			SlippageTolerance: q.Get("slippage_tolerance"),
+			SwapRate:          rate.FloatString(9),
+		})
+	}
+}
+
+func (s *Server) getSwapStatus(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.swapStatuses[swapStatusKey(q.Get("router_address"), q.Get("owner_address"), q.Get("query_id"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "swap not found")
+		return
+	}
+	writeJSON(w, status)
+}
+
+func (s *Server) getDexStats(w http.ResponseWriter, r *http.Request) {
+	since, until, ok := timeRange(w, r, true)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var response types.DexStatsResponse
+	response.Since, response.Until = since, until
+
+	tvl, volume := new(big.Rat), new(big.Rat)
+	for _, p := range s.pools {
+		if v, ok := new(big.Rat).SetString(p.LpTotalSupplyUsd); ok {
+			tvl.Add(tvl, v)
+		}
+	}
+	wallets := map[string]bool{}
+	for _, op := range s.operationsIn(since, until, "") {
+		wallets[op.Operation.WalletAddress] = true
+		if op.Operation.OperationType != "swap" {
+			continue
+		}
+		response.Stats.Trades++
+		if v := usdValue(op.Operation.Asset0Amount, op.Asset0Info); v != nil {
+			volume.Add(volume, v)
+		}
+	}
+	response.Stats.Tvl = tvl.FloatString(2)
+	response.Stats.VolumeUsd = volume.FloatString(2)
+	response.Stats.UniqueWallets = len(wallets)
+	writeJSON(w, response)
+}
+
+func (s *Server) getOperationStats(w http.ResponseWriter, r *http.Request) {
+	since, until, ok := timeRange(w, r, true)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, types.OperationsStatsResponse{Operations: s.operationsIn(since, until, "")})
+}
+
+func (s *Server) getPoolStats(w http.ResponseWriter, r *http.Request) {
+	since, until, ok := timeRange(w, r, true)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var response types.PoolStatsResponse
+	response.Since, response.Until = since, until
+	for _, p := range s.pools {
+		if p.Deprecated {
+			continue
+		}
+		base, _ := s.findAsset(p.Token0Address)
+		quote, _ := s.findAsset(p.Token1Address)
+		stat := types.PoolStats{
+			PoolAddress:    p.Address,
+			RouterAddress:  p.RouterAddress,
+			BaseID:         p.Token0Address,
+			BaseName:       base.DisplayName,
+			BaseSymbol:     base.Symbol,
+			QuoteID:        p.Token1Address,
+			QuoteName:      quote.DisplayName,
+			QuoteSymbol:    quote.Symbol,
+			BaseLiquidity:  formatUnits(p.Reserve0, base.Decimals),
+			QuoteLiquidity: formatUnits(p.Reserve1, quote.Decimals),
+			LpPriceUsd:     p.LpPriceUsd,
+			Apy:            p.Apy1D,
+			BaseVolume:     "0",
+			QuoteVolume:    "0",
+		}
+		if r0, ok := new(big.Rat).SetString(stat.BaseLiquidity); ok && r0.Sign() > 0 {
+			if r1, ok := new(big.Rat).SetString(stat.QuoteLiquidity); ok {
+				stat.LastPrice = new(big.Rat).Quo(r1, r0).FloatString(9)
+			}
+		}
+		response.Stats = append(response.Stats, stat)
+	}
+	wallets := map[string]bool{}
+	for _, op := range s.operationsIn(since, until, "") {
+		wallets[op.Operation.WalletAddress] = true
+	}
+	response.UniqueWalletsCount = len(wallets)
+	writeJSON(w, response)
+}
+
+func (s *Server) getWalletAssets(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	address := r.PathValue("addr_str")
+	list := []types.AssetList{}
+	if wallet, ok := s.wallets[utils.AddressKey(address)]; ok {
+		for assetAddress := range wallet.Balances {
+			if a, ok := s.findAsset(assetAddress); ok {
+				list = append(list, s.walletAsset(a, address))
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ContractAddress < list[j].ContractAddress })
+	writeJSON(w, types.SearchAssetsResponse{AssetList: list})
+}
+
+func (s *Server) getWalletAsset(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	address := r.PathValue("addr_str")
+	if _, ok := s.wallets[utils.AddressKey(address)]; !ok {
+		writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+	asset, ok := s.findAsset(r.PathValue("asset_address"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "asset not found")
+		return
+	}
+	writeJSON(w, types.WalletAssetResponse{Asset: s.walletAsset(asset, address)})
+}
+
+func (s *Server) getWalletFarms(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	farms := []types.Farm{}
+	if wallet, ok := s.wallets[utils.AddressKey(r.PathValue("addr_str"))]; ok {
+		farms = append(farms, wallet.Farms...)
+	}
+	writeJSON(w, types.FarmListResponse{Farms: farms})
+}
+
+func (s *Server) getWalletFarm(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if wallet, ok := s.wallets[utils.AddressKey(r.PathValue("addr_str"))]; ok {
+		for _, f := range wallet.Farms {
+			if utils.SameAddress(f.MinterAddress, r.PathValue("farm_address")) {
+				writeJSON(w, types.FarmResponse{Farm: f})
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "farm not found")
+}
+
+func (s *Server) getWalletPools(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pools := []types.Pool{}
+	if wallet, ok := s.wallets[utils.AddressKey(r.PathValue("addr_str"))]; ok {
+		pools = append(pools, wallet.Pools...)
+	}
+	writeJSON(w, types.PoolListResponse{PoolList: pools})
+}
+
+func (s *Server) getWalletPool(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if wallet, ok := s.wallets[utils.AddressKey(r.PathValue("addr_str"))]; ok {
+		for _, p := range wallet.Pools {
+			if utils.SameAddress(p.Address, r.PathValue("pool_address")) {
+				writeJSON(w, types.PoolResponse{Pool: p})
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "pool not found")
+}
+
+func (s *Server) getWalletOperations(w http.ResponseWriter, r *http.Request) {
+	since, until, ok := timeRange(w, r, false)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ops := types.Operations{}
+	for _, op := range s.operationsIn(since, until, r.URL.Query().Get("op_type")) {
+		if utils.SameAddress(op.Operation.WalletAddress, r.PathValue("addr_str")) {
+			ops = append(ops, op)
+		}
+	}
+	writeJSON(w, types.WalletOperationsResponse{Operations: ops})
+}
+
+func (s *Server) findAsset(address string) (types.Asset, bool) {
+	for _, a := range s.assets {
+		if utils.SameAddress(a.ContractAddress, address) {
+			return a, true
+		}
+	}
+	return types.Asset{}, false
+}
+
+func (s *Server) findPool(address string) (types.Pool, bool) {
+	for _, p := range s.pools {
+		if utils.SameAddress(p.Address, address) {
+			return p, true
+		}
+	}
+	return types.Pool{}, false
+}
+
+// pairPool returns the non-deprecated pool of the pair with the largest offer reserve.
+func (s *Server) pairPool(offer, ask string) (types.Pool, bool) {
+	var best types.Pool
+	var bestReserve *big.Int
+	for _, p := range s.pools {
+		if p.Deprecated {
+			continue
+		}
+		var reserve string
+		switch {
+		case utils.SameAddress(p.Token0Address, offer) && utils.SameAddress(p.Token1Address, ask):
+			reserve = p.Reserve0
+		case utils.SameAddress(p.Token1Address, offer) && utils.SameAddress(p.Token0Address, ask):
+			reserve = p.Reserve1
+		default:
+			continue
+		}
+		n, ok := new(big.Int).SetString(reserve, 10)
+		if ok && (bestReserve == nil || n.Cmp(bestReserve) > 0) {
+			best, bestReserve = p, n
+		}
+	}
+	return best, bestReserve != nil
+}
+
+func (s *Server) walletAsset(a types.Asset, walletAddress string) types.AssetList {
+	item := types.AssetList{Asset: a}
+	if wallet, ok := s.wallets[utils.AddressKey(walletAddress)]; ok {
+		item.WalletAddress = walletAddress
+		for assetAddress, balance := range wallet.Balances {
+			if utils.SameAddress(assetAddress, a.ContractAddress) {
+				item.Balance = balance
+			}
+		}
+	}
+	return item
+}
+
+// operationsIn returns the operations with a pool transaction in [since, until]
+// of the given type, or any type if opType is empty. Zero times are unbounded.
+func (s *Server) operationsIn(since, until time.Time, opType string) types.Operations {
+	ops := types.Operations{}
+	for _, op := range s.operations {
+		if opType != "" && op.Operation.OperationType != opType {
+			continue
+		}
+		ts, err := openapi.ParseTime(op.Operation.PoolTxTimestamp)
+		if err != nil || (!since.IsZero() && ts.Before(since)) || (!until.IsZero() && ts.After(until)) {
+			continue
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func timeRange(w http.ResponseWriter, r *http.Request, required bool) (since, until time.Time, ok bool) {
+	q := r.URL.Query()
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &since}, {"until", &until}} {
+		v := q.Get(p.name)
+		if v == "" {
+			if required {
+				writeError(w, http.StatusBadRequest, p.name+" is required")
+				return since, until, false
+			}
+			continue
+		}
+		t, err := openapi.ParseTime(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s: %v", p.name, err))
+			return since, until, false
+		}
+		*p.dst = t
+	}
+	return since, until, true
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// formatUnits converts an amount in smallest units to a decimal string.
+func formatUnits(units string, decimals int) string {
+	n, ok := new(big.Int).SetString(units, 10)
+	if !ok {
+		return "0"
+	}
+	return new(big.Rat).SetFrac(n, pow10(decimals)).FloatString(decimals)
+}
+
+// usdValue is the USD value of units of asset, or nil if the asset has no price.
+func usdValue(units string, asset types.Asset) *big.Rat {
+	n, ok := new(big.Int).SetString(units, 10)
+	if !ok {
+		return nil
+	}
+	price, ok := new(big.Rat).SetString(asset.DexPriceUsd)
+	if !ok {
+		return nil
+	}
+	v := new(big.Rat).SetFrac(n.Abs(n), pow10(asset.Decimals))
+	return v.Mul(v, price)
+}
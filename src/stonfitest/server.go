@@ -0,0 +1,232 @@
+// Package stonfitest provides an in-process fake of the Ston.fi v1 API for
+// tests. The server keeps assets, pools, farms, wallets and operations in
+// memory, simulates swaps against pool reserves, and can inject errors and
+// latency.
+package stonfitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Wallet is the state of a wallet as seen by the API.
+type Wallet struct {
+	// Balances maps asset addresses to balances in the asset's smallest units.
+	Balances map[string]string
+	Pools    []types.Pool
+	Farms    []types.Farm
+}
+
+// Fixtures is a snapshot of state to seed a Server with.
+type Fixtures struct {
+	Assets     []types.Asset
+	Pools      []types.Pool
+	Farms      []types.Farm
+	Operations types.Operations
+	Wallets    map[string]Wallet
+}
+
+// Server is a fake Ston.fi API served over HTTP.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	assets       []types.Asset
+	pools        []types.Pool
+	farms        []types.Farm
+	operations   types.Operations
+	wallets      map[string]*Wallet
+	swapStatuses map[string]types.SwapResponse
+	faults       []*Fault
+	requests     []string
+}
+
+// NewServer starts a server with no state. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		wallets:      map[string]*Wallet{},
+		swapStatuses: map[string]types.SwapResponse{},
+	}
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// NewServerWithFixtures starts a server seeded with fixtures.
+func NewServerWithFixtures(fixtures Fixtures) *Server {
+	s := NewServer()
+	s.Seed(fixtures)
+	return s
+}
+
+// Client returns a StonfiClient that talks to the server.
+func (s *Server) Client() *client.StonfiClient {
+	return client.NewStonfiClientWithOptions(client.StonfiClientOptions{BaseURL: s.URL + "/v1"})
+}
+
+// Seed adds fixtures to the server's state.
+func (s *Server) Seed(fixtures Fixtures) {
+	for _, a := range fixtures.Assets {
+		s.AddAsset(a)
+	}
+	for _, p := range fixtures.Pools {
+		s.AddPool(p)
+	}
+	for _, f := range fixtures.Farms {
+		s.AddFarm(f)
+	}
+	for _, op := range fixtures.Operations {
+		s.AddOperation(op)
+	}
+	for address, w := range fixtures.Wallets {
+		s.SetWallet(address, w)
+	}
+}
+
+// AddAsset adds an asset, replacing any asset with the same address.
+func (s *Server) AddAsset(asset types.Asset) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.assets {
+		if utils.SameAddress(s.assets[i].ContractAddress, asset.ContractAddress) {
+			s.assets[i] = asset
+			return
+		}
+	}
+	s.assets = append(s.assets, asset)
+}
+
+// AddPool adds a pool, replacing any pool with the same address.
+func (s *Server) AddPool(pool types.Pool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.pools {
+		if utils.SameAddress(s.pools[i].Address, pool.Address) {
+			s.pools[i] = pool
+			return
+		}
+	}
+	s.pools = append(s.pools, pool)
+}
+
+// SetReserves updates the reserves of an existing pool.
+func (s *Server) SetReserves(poolAddress, reserve0, reserve1 string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.pools {
+		if utils.SameAddress(s.pools[i].Address, poolAddress) {
+			s.pools[i].Reserve0, s.pools[i].Reserve1 = reserve0, reserve1
+		}
+	}
+}
+
+// AddFarm adds a farm, replacing any farm with the same minter address.
+func (s *Server) AddFarm(farm types.Farm) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.farms {
+		if utils.SameAddress(s.farms[i].MinterAddress, farm.MinterAddress) {
+			s.farms[i] = farm
+			return
+		}
+	}
+	s.farms = append(s.farms, farm)
+}
+
+// AddOperation records an operation. It is returned by the stats and wallet
+// operations endpoints when its PoolTxTimestamp is in the requested range.
+func (s *Server) AddOperation(op types.OperationInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operations = append(s.operations, op)
+}
+
+// SetWallet replaces the state of the wallet at address.
+func (s *Server) SetWallet(address string, wallet Wallet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wallets[utils.AddressKey(address)] = &wallet
+}
+
+// SetSwapStatus sets the response of the swap status endpoint for a swap.
+func (s *Server) SetSwapStatus(routerAddress, ownerAddress, queryID string, status types.SwapResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.swapStatuses[swapStatusKey(routerAddress, ownerAddress, queryID)] = status
+}
+
+// Requests returns the requests served so far, as "METHOD /path?query".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Fault makes matching requests fail, slow down, or both.
+type Fault struct {
+	Method string // empty matches any method
+	Path   string // request path prefix such as "/v1/pools"; empty matches any path
+	// Status is the HTTP status to fail with; zero lets the request through
+	// after Latency.
+	Status  int
+	Latency time.Duration
+	// Times limits the fault to that many requests; zero means every request.
+	Times int
+}
+
+// Inject adds a fault. Faults are checked in the order they were injected.
+func (s *Server) Inject(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// FailNext makes the next n requests fail with status.
+func (s *Server) FailNext(status, n int) {
+	s.Inject(Fault{Status: status, Times: n})
+}
+
+// SetLatency delays every request by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.Inject(Fault{Latency: d})
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the fault for r, if any, and uses up one of its Times.
+func (s *Server) matchFault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != r.Method) || !hasPathPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+func hasPathPrefix(path, prefix string) bool {
+	return len(path) >= len(prefix) && path[:len(prefix)] == prefix
+}
+
+func swapStatusKey(routerAddress, ownerAddress, queryID string) string {
+	return utils.AddressKey(routerAddress) + "|" + utils.AddressKey(ownerAddress) + "|" + queryID
+}
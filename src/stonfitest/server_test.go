@@ -0,0 +1,158 @@
+package stonfitest
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/amm"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tonAddress  = "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c"
+	usdtAddress = "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	wallet      = "EQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwiuA"
+)
+
+var testPool = types.Pool{
+	Address:          poolAddress,
+	RouterAddress:    "EQB3ncyBUTjZUA5EnFKR5_EnOMI9V1tTEAAPaiU71gc4TiUt",
+	Token0Address:    tonAddress,
+	Token1Address:    usdtAddress,
+	Reserve0:         "1000000000000",
+	Reserve1:         "5000000000",
+	LpFee:            "20",
+	ProtocolFee:      "10",
+	RefFee:           "10",
+	LpTotalSupplyUsd: "10000",
+}
+
+func testFixtures() Fixtures {
+	return Fixtures{
+		Assets: []types.Asset{
+			{ContractAddress: tonAddress, Symbol: "TON", DisplayName: "Toncoin", Decimals: 9, DexPriceUsd: "5"},
+			{ContractAddress: usdtAddress, Symbol: "USD₮", DisplayName: "Tether USD", Decimals: 6, DexPriceUsd: "1"},
+		},
+		Pools: []types.Pool{testPool},
+		Farms: []types.Farm{{MinterAddress: "EQCEtmlPBWm7X9_Ec7Vmr5Jc6tLgrpjAoTrhoGdGbOkTL9ox", PoolAddress: poolAddress}},
+		Operations: types.Operations{{Operation: types.Operation{
+			OperationType:   "swap",
+			WalletAddress:   wallet,
+			PoolAddress:     poolAddress,
+			PoolTxTimestamp: "2024-01-01T00:30:00",
+			Asset0Amount:    "2000000000",
+			Asset1Amount:    "-10000000",
+		}, Asset0Info: types.Asset{ContractAddress: tonAddress, Decimals: 9, DexPriceUsd: "5"}}},
+		Wallets: map[string]Wallet{wallet: {Balances: map[string]string{usdtAddress: "42000000"}}},
+	}
+}
+
+func TestServer(t *testing.T) {
+	s := NewServerWithFixtures(testFixtures())
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	assets, err := c.GetAssets(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, assets.AssetList, 2)
+
+	// Addresses match in any form.
+	asset, err := c.GetAsset(ctx, "0:b113a994b5024a16719f69139328eb759596c38a25f59028b146fecdc3621dfe")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD₮", asset.Asset.Symbol)
+
+	_, err = c.GetPool(ctx, "EQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwiuA")
+	assert.ErrorContains(t, err, "status code: 404")
+
+	farms, err := c.GetFarmsByPool(ctx, poolAddress)
+	assert.NoError(t, err)
+	assert.Len(t, farms.Farms, 1)
+
+	balances, err := c.GetWalletAssets(ctx, wallet)
+	assert.NoError(t, err)
+	if assert.Len(t, balances.AssetList, 1) {
+		assert.Equal(t, "42000000", balances.AssetList[0].Balance)
+		assert.Equal(t, "USD₮", balances.AssetList[0].Symbol)
+		assert.Equal(t, 6, balances.AssetList[0].Decimals)
+	}
+
+	balance, err := c.GetWalletAsset(ctx, wallet, usdtAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, "42000000", balance.Asset.Balance)
+	assert.Equal(t, wallet, balance.Asset.WalletAddress)
+	_, err = c.GetWalletAsset(ctx, poolAddress, usdtAddress)
+	assert.ErrorContains(t, err, "status code: 404")
+	_, err = c.GetWalletAsset(ctx, wallet, poolAddress)
+	assert.ErrorContains(t, err, "status code: 404")
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stats, err := c.GetStats(ctx, start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stats.Stats.Trades)
+	assert.Equal(t, "10.00", stats.Stats.VolumeUsd)
+
+	ops, err := c.GetWalletOperations(ctx, wallet)
+	assert.NoError(t, err)
+	assert.Len(t, ops.Operations, 1)
+
+	assert.Contains(t, s.Requests(), "GET /v1/assets")
+}
+
+func TestServerSimulateSwap(t *testing.T) {
+	s := NewServerWithFixtures(testFixtures())
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	want, err := amm.SimulateSwap(testPool, tonAddress, big.NewInt(1000000000), false)
+	assert.NoError(t, err)
+
+	sim, err := c.SimulateSwap(ctx, tonAddress, usdtAddress, "1000000000", "0.01")
+	assert.NoError(t, err)
+	assert.Equal(t, want.AskUnits.String(), sim.AskUnits)
+	assert.Equal(t, poolAddress, sim.PoolAddress)
+
+	wantReverse, err := amm.SimulateReverseSwap(testPool, tonAddress, want.AskUnits, false)
+	assert.NoError(t, err)
+	reverse, err := c.SimulateReverseSwap(ctx, tonAddress, usdtAddress, sim.AskUnits, "0.01")
+	assert.NoError(t, err)
+	assert.Equal(t, wantReverse.OfferUnits.String(), reverse.OfferUnits)
+
+	// Swaps see reserve updates.
+	s.SetReserves(poolAddress, "2000000000000", "5000000000")
+	moved, err := c.SimulateSwap(ctx, tonAddress, usdtAddress, "1000000000", "0.01")
+	assert.NoError(t, err)
+	assert.NotEqual(t, sim.AskUnits, moved.AskUnits)
+}
+
+func TestServerFaults(t *testing.T) {
+	s := NewServerWithFixtures(testFixtures())
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	s.FailNext(http.StatusTooManyRequests, 1)
+	_, err := c.GetPools(ctx)
+	assert.ErrorContains(t, err, "status code: 429")
+	_, err = c.GetPools(ctx)
+	assert.NoError(t, err)
+
+	s.Inject(Fault{Path: "/v1/farms", Status: http.StatusInternalServerError})
+	_, err = c.GetFarms(ctx)
+	assert.ErrorContains(t, err, "status code: 500")
+	_, err = c.GetPools(ctx)
+	assert.NoError(t, err)
+	s.ClearFaults()
+
+	s.SetLatency(time.Second)
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = c.GetPools(timeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
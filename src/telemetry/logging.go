@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+// Redacted replaces redacted parameter values in logs.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedParams are the parameters that identify a user's wallet.
+var DefaultRedactedParams = []string{"wallet_address", "owner_address", "referral_address"}
+
+// LoggingOptions configures NewLoggingHook.
+type LoggingOptions struct {
+	// Level is the level of successful calls; failed calls log at LevelError.
+	// Defaults to slog.LevelDebug.
+	Level *slog.Level
+	// RedactParams lists the parameters whose values are replaced by Redacted.
+	// Nil means DefaultRedactedParams; an empty slice redacts nothing. The
+	// address in the path of /wallets endpoints counts as "wallet_address".
+	RedactParams []string
+}
+
+type loggingHook struct {
+	logger *slog.Logger
+	level  slog.Level
+	redact []string
+}
+
+// NewLoggingHook returns a hook that logs every API call to logger once it is
+// done, with its endpoint, parameters, status, latency, size and error.
+func NewLoggingHook(logger *slog.Logger, opts LoggingOptions) client.Hook {
+	h := &loggingHook{logger: logger, level: slog.LevelDebug, redact: opts.RedactParams}
+	if opts.Level != nil {
+		h.level = *opts.Level
+	}
+	if h.redact == nil {
+		h.redact = DefaultRedactedParams
+	}
+	return h
+}
+
+func (h *loggingHook) Before(ctx context.Context, call *client.CallInfo) context.Context {
+	return ctx
+}
+
+func (h *loggingHook) After(ctx context.Context, call *client.CallInfo, result *client.CallResult) {
+	level := h.level
+	if result.Err != nil {
+		level = slog.LevelError
+	}
+	if !h.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("endpoint", call.Endpoint.Name),
+		slog.String("method", call.Endpoint.Method),
+		slog.Group("params", h.params(call)...),
+		slog.Int("status", result.StatusCode),
+		slog.Duration("latency", result.Latency),
+		slog.Int("bytes", result.Bytes),
+	}
+	if result.Err != nil {
+		attrs = append(attrs, slog.String("error", result.Err.Error()))
+	}
+	h.logger.LogAttrs(ctx, level, "stonfi API call", attrs...)
+}
+
+func (h *loggingHook) params(call *client.CallInfo) []any {
+	keys := make([]string, 0, len(call.Params))
+	for key := range call.Params {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		// Params may be given in camelCase; they are sent, and matched
+		// against the redacted names, in snake_case.
+		sent := utils.SnakeCase(key)
+		name := sent
+		if sent == "addr_str" && strings.HasPrefix(call.Endpoint.Path, "/wallets/") {
+			name = "wallet_address"
+		}
+		value := strings.Join(call.Params[key], ",")
+		if slices.Contains(h.redact, name) {
+			value = Redacted
+		}
+		attrs = append(attrs, slog.String(sent, value))
+	}
+	return attrs
+}
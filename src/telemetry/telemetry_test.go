@@ -0,0 +1,110 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	wallet      = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+)
+
+func TestTracingHook(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Pools: []types.Pool{{Address: poolAddress}}})
+	defer s.Close()
+
+	var traceparent string
+	s.Config.Handler = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("Traceparent")
+			next.ServeHTTP(w, r)
+		})
+	}(s.Config.Handler)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	c := s.Client()
+	c.Use(NewTracingHook(TracingOptions{TracerProvider: provider, Propagator: propagation.TraceContext{}}))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	_, err := c.GetPool(ctx, poolAddress)
+	assert.NoError(t, err)
+	s.FailNext(http.StatusInternalServerError, 1)
+	_, err = c.GetPools(ctx)
+	assert.Error(t, err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if !assert.Len(t, spans, 3) {
+		return
+	}
+	ok, failed := spans[0], spans[1]
+	assert.Equal(t, "GetPool", ok.Name)
+	assert.Equal(t, parent.SpanContext().TraceID(), ok.SpanContext.TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), ok.Parent.SpanID())
+	assert.Contains(t, ok.Attributes, semconv.HTTPResponseStatusCode(http.StatusOK))
+	assert.Contains(t, ok.Attributes, EndpointKey.String("GetPool"))
+	assert.Contains(t, ok.Attributes, semconv.URLTemplate("/pools/{addr_str}"))
+	for _, attr := range ok.Attributes {
+		assert.NotContains(t, attr.Value.Emit(), poolAddress, "span attribute %s", attr.Key)
+	}
+	assert.Equal(t, codes.Unset, ok.Status.Code)
+
+	assert.Equal(t, "GetPools", failed.Name)
+	assert.Equal(t, codes.Error, failed.Status.Code)
+	assert.Contains(t, traceparent, failed.SpanContext.SpanID().String())
+}
+
+func TestLoggingHook(t *testing.T) {
+	s := stonfitest.NewServer()
+	defer s.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := s.Client()
+	c.Use(NewLoggingHook(logger, LoggingOptions{}))
+
+	_, err := c.GetWalletAssets(context.Background(), wallet)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "level=DEBUG")
+	assert.Contains(t, buf.String(), "endpoint=GetWalletAssets")
+	assert.Contains(t, buf.String(), "params.addr_str="+Redacted)
+	assert.Contains(t, buf.String(), "status=200")
+	assert.NotContains(t, buf.String(), wallet)
+
+	buf.Reset()
+	_, _ = c.GetSwapStatus(context.Background(), poolAddress, wallet, "1")
+	assert.Contains(t, buf.String(), "params.owner_address="+Redacted)
+	assert.NotContains(t, buf.String(), wallet)
+
+	buf.Reset()
+	_ = c.Call(context.Background(), client.Endpoint{Name: "GetSwapStatus", Method: http.MethodGet, Path: "/swap/status"},
+		url.Values{"routerAddress": {poolAddress}, "ownerAddress": {wallet}, "queryId": {"1"}}, &struct{}{})
+	assert.Contains(t, buf.String(), "params.owner_address="+Redacted, "camelCase params are redacted too")
+	assert.NotContains(t, buf.String(), wallet)
+
+	buf.Reset()
+	info := slog.LevelInfo
+	c = s.Client()
+	c.Use(NewLoggingHook(logger, LoggingOptions{Level: &info, RedactParams: []string{}}))
+	_, err = c.GetPool(context.Background(), poolAddress)
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "level=ERROR")
+	assert.Contains(t, buf.String(), "params.addr_str="+poolAddress)
+	assert.Contains(t, buf.String(), "status=404")
+}
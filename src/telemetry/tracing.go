@@ -0,0 +1,89 @@
+// Package telemetry provides client.Hook implementations that trace API calls
+// with OpenTelemetry and log them with log/slog.
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the tracer of this package.
+const instrumentationName = "github.com/itay747/go-stonfi"
+
+// Attribute keys specific to Ston.fi API calls.
+const (
+	EndpointKey      = attribute.Key("stonfi.endpoint")
+	ResponseBytesKey = attribute.Key("stonfi.response.bytes")
+)
+
+// TracingOptions configures NewTracingHook.
+type TracingOptions struct {
+	// TracerProvider defaults to the global provider.
+	TracerProvider trace.TracerProvider
+	// Propagator injects the span context into request headers. It defaults
+	// to the global propagator.
+	Propagator propagation.TextMapPropagator
+}
+
+type tracingHook struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTracingHook returns a hook that wraps every API call in a client span,
+// named after the endpoint and child of the span in the call's context.
+func NewTracingHook(opts TracingOptions) client.Hook {
+	if opts.TracerProvider == nil {
+		opts.TracerProvider = otel.GetTracerProvider()
+	}
+	if opts.Propagator == nil {
+		opts.Propagator = otel.GetTextMapPropagator()
+	}
+	return &tracingHook{
+		tracer:     opts.TracerProvider.Tracer(instrumentationName),
+		propagator: opts.Propagator,
+	}
+}
+
+func (h *tracingHook) Before(ctx context.Context, call *client.CallInfo) context.Context {
+	attrs := []attribute.KeyValue{
+		EndpointKey.String(call.Endpoint.Name),
+		semconv.HTTPRequestMethodKey.String(call.Endpoint.Method),
+	}
+	// The route template stands in for the full URL, which can hold wallet
+	// addresses in its path and query.
+	attrs = append(attrs, semconv.URLTemplate(call.Endpoint.Path))
+	if u, err := url.Parse(call.URL); err == nil && call.URL != "" {
+		attrs = append(attrs, semconv.ServerAddress(u.Hostname()))
+	}
+	ctx, _ = h.tracer.Start(ctx, call.Endpoint.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	h.propagator.Inject(ctx, propagation.HeaderCarrier(call.Header))
+	return ctx
+}
+
+func (h *tracingHook) After(ctx context.Context, call *client.CallInfo, result *client.CallResult) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+	if result.StatusCode != 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(result.StatusCode))
+	}
+	span.SetAttributes(ResponseBytesKey.Int(result.Bytes))
+	if result.Err != nil {
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+	} else if result.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(result.StatusCode))
+	}
+}
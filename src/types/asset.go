@@ -40,23 +40,16 @@ type AssetListResponse struct {
 
 
 
+// AssetList is an asset as listed by the search and wallet endpoints, with the
+// wallet's balance when the request names a wallet.
 type AssetList struct {
-	ContractAddress string `json:"contract_address"`
-	Kind            string `json:"kind"`
-	DexPriceUsd     string `json:"dex_price_usd"`
-	WalletAddress   string `json:"wallet_address"`
-	Balance         string `json:"balance"`
-	Meta            struct {
-		Symbol      string `json:"symbol"`
-		DisplayName string `json:"display_name"`
-		ImageURL    string `json:"image_url"`
-		Decimals    int    `json:"decimals"`
-	} `json:"meta"`
-	Tags []string `json:"tags"`
+	Asset
+	WalletAddress string `json:"wallet_address,omitempty"`
+	Balance       string `json:"balance,omitempty"`
 }
 type SearchAssetsResponse struct {
-	AssetList AssetList  `json:"asset_list"`
+	AssetList []AssetList `json:"asset_list"`
 }
 type QueryWalletBallanceResponse struct {
-	AssetList AssetList `json:"asset_list"`
+	AssetList []AssetList `json:"asset_list"`
 }
\ No newline at end of file
@@ -8,13 +8,36 @@ type Farm struct {
 	LockedTotalLP      string        `json:"locked_total_lp"`
 	LockedTotalLPUSD   string        `json:"locked_total_lp_usd"`
 	APY                string        `json:"apy"`
-	NftInfos           []interface{} `json:"nft_infos"`
-	Rewards            []struct {
-		Address          string `json:"address"`
-		Status           string `json:"status"`
-		RemainingRewards string `json:"remaining_rewards"`
-		RewardRate24H    string `json:"reward_rate_24h"`
-	} `json:"rewards"`
+	NftInfos           []FarmNftInfo `json:"nft_infos"`
+	Rewards            []FarmReward  `json:"rewards"`
+}
+
+// FarmReward is a reward stream of a farm. Amounts are in the reward token's
+// smallest units.
+type FarmReward struct {
+	Address          string `json:"address"`
+	Status           string `json:"status"`
+	RemainingRewards string `json:"remaining_rewards"`
+	RewardRate24H    string `json:"reward_rate_24h"`
+}
+
+// FarmNftInfo is a stake in a farm, held as an NFT by the staker. It is only
+// set on the farms of `/v1/wallets/{addr_str}/farms`.
+type FarmNftInfo struct {
+	Address             string          `json:"address"`
+	Status              string          `json:"status"`
+	CreateTimestamp     string          `json:"create_timestamp"`
+	MinUnstakeTimestamp string          `json:"min_unstake_timestamp"`
+	StakedTokens        string          `json:"staked_tokens"`
+	NonclaimedRewards   string          `json:"nonclaimed_rewards"`
+	Rewards             []FarmNftReward `json:"rewards"`
+}
+
+// FarmNftReward is the unclaimed reward of a stake in one reward token, in
+// its smallest units.
+type FarmNftReward struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
 }
 
 type FarmResponse struct {
@@ -22,6 +45,6 @@ type FarmResponse struct {
 }
 
 type FarmListResponse struct {
-	Farms []Farm `json:"farms"`
+	Farms []Farm `json:"farm_list"`
 }
 
@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
 
 // Aggregated stats for a pool.
 type DexStatsResponse struct {
@@ -44,37 +47,53 @@ type Operation struct {
 	PoolTxLt                 int64  `json:"pool_tx_lt"`
 	Success                  bool   `json:"success"`
 }
-type Operations []struct {
-	Operation Operation `json:"operation"`
-	Asset0Info Asset `json:"asset0_info"`
-	Asset1Info Asset `json:"asset1_info"`
+
+// LogicalTime orders the operations of a wallet: by wallet_tx_lt, or
+// pool_tx_lt when the API leaves it out.
+func (op Operation) LogicalTime() uint64 {
+	if lt, err := strconv.ParseUint(op.WalletTxLt, 10, 64); err == nil {
+		return lt
+	}
+	if op.PoolTxLt > 0 {
+		return uint64(op.PoolTxLt)
+	}
+	return 0
+}
+
+// OperationInfo is an operation together with the assets it involves.
+type OperationInfo struct {
+	Operation  Operation `json:"operation"`
+	Asset0Info Asset     `json:"asset0_info"`
+	Asset1Info Asset     `json:"asset1_info"`
 }
+type Operations []OperationInfo
 type OperationsStatsResponse struct {
-	Operations Operations `json:"operations"`		
+	Operations Operations `json:"operations"`
 }
 
 type PoolStatsResponse struct {
-	Since time.Time `json:"since"`
-	Until time.Time `json:"until"`
-	Stats []struct {
-		PoolAddress    string `json:"pool_address"`
-		RouterAddress  string `json:"router_address"`
-		URL            string `json:"url"`
-		BaseID         string `json:"base_id"`
-		BaseName       string `json:"base_name"`
-		BaseSymbol     string `json:"base_symbol"`
-		QuoteID        string `json:"quote_id"`
-		QuoteName      string `json:"quote_name"`
-		QuoteSymbol    string `json:"quote_symbol"`
-		LastPrice      string `json:"last_price"`
-		BaseVolume     string `json:"base_volume"`
-		QuoteVolume    string `json:"quote_volume"`
-		BaseLiquidity  string `json:"base_liquidity"`
-		QuoteLiquidity string `json:"quote_liquidity"`
-		LpPrice        string `json:"lp_price"`
-		LpPriceUsd     string `json:"lp_price_usd"`
-		Apy            string `json:"apy"`
-	} `json:"stats"`
-	UniqueWalletsCount int `json:"unique_wallets_count"`
+	Since              time.Time   `json:"since"`
+	Until              time.Time   `json:"until"`
+	Stats              []PoolStats `json:"stats"`
+	UniqueWalletsCount int         `json:"unique_wallets_count"`
 }
 
+type PoolStats struct {
+	PoolAddress    string `json:"pool_address"`
+	RouterAddress  string `json:"router_address"`
+	URL            string `json:"url"`
+	BaseID         string `json:"base_id"`
+	BaseName       string `json:"base_name"`
+	BaseSymbol     string `json:"base_symbol"`
+	QuoteID        string `json:"quote_id"`
+	QuoteName      string `json:"quote_name"`
+	QuoteSymbol    string `json:"quote_symbol"`
+	LastPrice      string `json:"last_price"`
+	BaseVolume     string `json:"base_volume"`
+	QuoteVolume    string `json:"quote_volume"`
+	BaseLiquidity  string `json:"base_liquidity"`
+	QuoteLiquidity string `json:"quote_liquidity"`
+	LpPrice        string `json:"lp_price"`
+	LpPriceUsd     string `json:"lp_price_usd"`
+	Apy            string `json:"apy"`
+}
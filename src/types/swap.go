@@ -18,14 +18,18 @@ type SwapSimulationResponse struct {
 	SlippageTolerance string `json:"slippage_tolerance"`
 	SwapRate          string `json:"swap_rate"`
 }
+
+// SwapResponse is the status of a swap. Type is "Found" once the router has
+// processed it, and "NotFound" until then.
 type SwapResponse struct {
-	TransactionID  string `json:"transaction_id"`
-	Status         string `json:"status"`
-	PriceImpact    string `json:"price_impact"`
-	AmountIn       string `json:"amount_in"`
-	AmountOut      string `json:"amount_out"`
-	TokenIn        string `json:"token_in"`
-	TokenOut       string `json:"token_out"`
+	Type          string `json:"@type"`
+	Address       string `json:"address,omitempty"`
+	QueryID       string `json:"query_id,omitempty"`
+	ExitCode      string `json:"exit_code,omitempty"`
+	Coins         string `json:"coins,omitempty"`
+	LogicalTime   string `json:"logical_time,omitempty"`
+	TxHash        string `json:"tx_hash,omitempty"`
+	BalanceDeltas string `json:"balance_deltas,omitempty"`
 }
 
 type SwapStatusResponse struct {
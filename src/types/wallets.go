@@ -7,7 +7,7 @@ type WalletAssetsResponse struct {
 
 // Struct for unmarshalling response from `/v1/wallets/{addr_str}/assets/{asset_address}`
 type WalletAssetResponse struct {
-	Asset Asset `json:"asset"`
+	Asset AssetList `json:"asset"`
 }
 
 // Struct for unmarshalling response from `/v1/wallets/{addr_str}/farms`
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NormalizeAddress converts a TON address in raw (`0:<hex>`) or user-friendly
+// (bounceable or not, base64 or base64url) form to its raw form, so that
+// different spellings of the same address compare equal.
+func NormalizeAddress(address string) (string, error) {
+	if wc, hash, ok := strings.Cut(address, ":"); ok {
+		workchain, err := strconv.ParseInt(wc, 10, 32)
+		if err != nil {
+			return "", fmt.Errorf("invalid workchain in address %q: %w", address, err)
+		}
+		raw, err := hex.DecodeString(hash)
+		if err != nil || len(raw) != 32 {
+			return "", fmt.Errorf("invalid account id in address %q", address)
+		}
+		return fmt.Sprintf("%d:%x", workchain, raw), nil
+	}
+
+	if len(address) != 48 {
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.NewReplacer("+", "-", "/", "_").Replace(address))
+	if err != nil || len(data) != 36 {
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+	if crc16(data[:34]) != binary.BigEndian.Uint16(data[34:]) {
+		return "", fmt.Errorf("invalid checksum in address %q", address)
+	}
+	return fmt.Sprintf("%d:%x", int8(data[1]), data[2:34]), nil
+}
+
+// AddressKey is the raw form of address, or address itself if it can't be
+// parsed. Use it to key maps and compare addresses that may be spelled
+// differently.
+func AddressKey(address string) string {
+	if raw, err := NormalizeAddress(address); err == nil {
+		return raw
+	}
+	return address
+}
+
+// SameAddress reports whether a and b are spellings of the same address.
+func SameAddress(a, b string) bool {
+	return AddressKey(a) == AddressKey(b)
+}
+
+// AddressForms returns the spellings of address: its raw form in lower and
+// upper case, and its user-friendly forms (bounceable or not, mainnet or
+// testnet, base64 or base64url).
+func AddressForms(address string) ([]string, error) {
+	raw, err := NormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	wc, hash, _ := strings.Cut(raw, ":")
+	workchain, _ := strconv.ParseInt(wc, 10, 8)
+	accountID, _ := hex.DecodeString(hash)
+
+	forms := []string{raw, wc + ":" + strings.ToUpper(hash)}
+	for _, tag := range []byte{0x11, 0x51, 0x91, 0xd1} {
+		data := make([]byte, 36)
+		data[0] = tag
+		data[1] = byte(int8(workchain))
+		copy(data[2:34], accountID)
+		binary.BigEndian.PutUint16(data[34:], crc16(data[:34]))
+		forms = append(forms, base64.URLEncoding.EncodeToString(data), base64.StdEncoding.EncodeToString(data))
+	}
+	return forms, nil
+}
+
+// crc16 is CRC-16/XMODEM, the checksum used by user-friendly TON addresses.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "should normalize bounceable addresses",
+			address:  "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs",
+			expected: "0:b113a994b5024a16719f69139328eb759596c38a25f59028b146fecdc3621dfe",
+		},
+		{
+			name:     "should normalize non-bounceable addresses",
+			address:  "UQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_p0p",
+			expected: "0:b113a994b5024a16719f69139328eb759596c38a25f59028b146fecdc3621dfe",
+		},
+		{
+			name:     "should normalize raw addresses",
+			address:  "0:B113A994B5024A16719F69139328EB759596C38A25F59028B146FECDC3621DFE",
+			expected: "0:b113a994b5024a16719f69139328eb759596c38a25f59028b146fecdc3621dfe",
+		},
+		{
+			name:     "should normalize the zero address",
+			address:  "EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAM9c",
+			expected: "0:0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			name:        "should reject a bad checksum",
+			address:     "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDt",
+			expectError: true,
+		},
+		{
+			name:        "should reject symbols",
+			address:     "USDT",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizeAddress(tt.address)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tt.expectError, err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAddressForms(t *testing.T) {
+	forms, err := AddressForms("0:b113a994b5024a16719f69139328eb759596c38a25f59028b146fecdc3621dfe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs",
+		"UQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_p0p",
+		"0:B113A994B5024A16719F69139328EB759596C38A25F59028B146FECDC3621DFE",
+	} {
+		if !slices.Contains(forms, want) {
+			t.Errorf("Expected %s in %v", want, forms)
+		}
+	}
+	for _, form := range forms {
+		if raw, err := NormalizeAddress(form); err != nil || raw != forms[0] {
+			t.Errorf("Expected %s to normalize to %s, got %s (%v)", form, forms[0], raw, err)
+		}
+	}
+}
+
+func TestAddressKey(t *testing.T) {
+	raw := "0:b113a994b5024a16719f69139328eb759596c38a25f59028b146fecdc3621dfe"
+	for _, address := range []string{raw, "EQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_sDs", "UQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_p0p"} {
+		if key := AddressKey(address); key != raw {
+			t.Errorf("Expected key of %s to be %s, got %s", address, raw, key)
+		}
+	}
+	if key := AddressKey("UQ..."); key != "UQ..." {
+		t.Errorf("Expected an invalid address to be its own key, got %s", key)
+	}
+	if !SameAddress(raw, "UQCxE6mUtQJKFnGfaROTKOt1lZbDiiX1kCixRv7Nw2Id_p0p") || SameAddress(raw, "UQ...") {
+		t.Error("Expected SameAddress to compare addresses by their raw form")
+	}
+}
@@ -2,34 +2,51 @@ package utils
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
 )
 var norm_req_re = regexp.MustCompile(`{([a-zA-Z0-9_]+)}`)
-// normalizeRequest prepares the request URL and query parameters.
+// NormalizeRequest fills the `{param}` placeholders in path from options and
+// returns the remaining options as URL-safe query parameters with snake_case
+// keys. Path parameters are escaped, and a missing one is an error. options is
+// not modified.
 func NormalizeRequest(path string, options map[string][]string) (string, map[string][]string, error) {
+	query := make(map[string][]string, len(options))
+	for key, values := range options {
+		query[key] = values
+	}
+
+	var missing []string
 	pathWithParams := norm_req_re.ReplaceAllStringFunc(path, func(m string) string {
 		key := m[1 : len(m)-1] // Remove the curly braces
-		values, exists := options[key]
-		if !exists || len(values) == 0 {
-			return m // Return the original match if no substitution is found
+		values, exists := query[key]
+		if !exists || len(values) == 0 || values[0] == "" {
+			missing = append(missing, key)
+			return m
 		}
-		delete(options, key)              // Remove the key from query options after using it
+		delete(query, key)                // Remove the key from query options after using it
 		return url.QueryEscape(values[0]) // Escape the first value corresponding to the key
 	})
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("missing path parameter(s) for %s: %s", path, strings.Join(missing, ", "))
+	}
 
 	// Ensure all values in the options map are URL safe and keys are in snake_case
-	for key, values := range options {
+	for key, values := range query {
+		escaped := make([]string, len(values))
 		for i, v := range values {
-			values[i] = ToUrlSafe(v)
+			escaped[i] = ToUrlSafe(v)
 		}
-		options[key] = values
+		query[key] = escaped
 	}
-	options = DecamelizeKeys(options)
 
-	return pathWithParams, options, nil
+	return pathWithParams, DecamelizeKeys(query), nil
 }
 
+// NormalizeResponse rewrites a JSON document with camelCase keys and null
+// values replaced by empty strings.
 func NormalizeResponse(response []byte) ([]byte, error) {
 	var data interface{}
 	if err := json.Unmarshal(response, &data); err != nil {
@@ -41,3 +58,32 @@ func NormalizeResponse(response []byte) ([]byte, error) {
 
 	return json.Marshal(data)
 }
+
+// SnakeCaseResponse rewrites a JSON document with snake_case keys, the casing
+// of the json tags in src/types, and object members that are null dropped, so
+// they decode to zero values whatever the field's type.
+func SnakeCaseResponse(response []byte) ([]byte, error) {
+	var data interface{}
+	if err := json.Unmarshal(response, &data); err != nil {
+		return nil, err
+	}
+	return json.Marshal(snakeCaseDocument(data))
+}
+
+func snakeCaseDocument(data interface{}) interface{} {
+	switch data := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(data))
+		for k, v := range data {
+			if v != nil {
+				result[SnakeCase(k)] = snakeCaseDocument(v)
+			}
+		}
+		return result
+	case []interface{}:
+		for i, v := range data {
+			data[i] = snakeCaseDocument(v)
+		}
+	}
+	return data
+}
@@ -112,3 +112,14 @@ func TestNormalizeResponse(t *testing.T) {
 		}
 	})
 }
+
+func TestSnakeCaseResponse(t *testing.T) {
+	result, err := SnakeCaseResponse([]byte(`{"poolList": [{"lpFee": "20", "decimals": null}], "@type": "Found"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"@type":"Found","pool_list":[{"lp_fee":"20"}]}`
+	if string(result) != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
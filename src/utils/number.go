@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// ParseDecimal parses a decimal string of the API, such as a USD price or an
+// APY. It reports false if s is empty, invalid, NaN or infinite.
+func ParseDecimal(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// ParseFloat is ParseDecimal for values where a missing or invalid decimal
+// counts as zero.
+func ParseFloat(s string) float64 {
+	v, _ := ParseDecimal(s)
+	return v
+}
+
+// ToUnits converts an amount in an asset's smallest units to whole tokens.
+func ToUnits(n *big.Int, decimals int) float64 {
+	r := new(big.Rat).SetFrac(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	f, _ := r.Float64()
+	return f
+}
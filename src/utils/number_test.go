@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for s, want := range map[string]float64{"5.5": 5.5, "0": 0, "-1e3": -1000} {
+		if v, ok := ParseDecimal(s); !ok || v != want {
+			t.Errorf("Expected %q to parse as %v, got %v (%v)", s, want, v, ok)
+		}
+	}
+	for _, s := range []string{"", "abc", "NaN", "Inf"} {
+		if _, ok := ParseDecimal(s); ok {
+			t.Errorf("Expected %q not to parse", s)
+		}
+		if v := ParseFloat(s); v != 0 {
+			t.Errorf("Expected %q to count as 0, got %v", s, v)
+		}
+	}
+}
+
+func TestToUnits(t *testing.T) {
+	if v := ToUnits(big.NewInt(1500000000), 9); v != 1.5 {
+		t.Errorf("Expected 1.5, got %v", v)
+	}
+	if v := ToUnits(big.NewInt(-25), 1); v != -2.5 {
+		t.Errorf("Expected -2.5, got %v", v)
+	}
+}
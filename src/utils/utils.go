@@ -2,7 +2,6 @@ package utils
 
 import (
 	"net/url"
-	"reflect"
 	"strings"
 	"unicode"
 )
@@ -51,36 +50,20 @@ func SnakeCaseKeys(data map[string]string) map[string]string {
 	return snakeCased
 }
 
-// DenullifyValues recursively replaces nil pointers and nil interfaces in any complex data structure (maps, slices, etc.).
+// DenullifyValues recursively replaces null values in decoded JSON (maps and slices) with empty strings.
 func DenullifyValues(data interface{}) interface{} {
-	v := reflect.ValueOf(data)
-	switch v.Kind() {
-	case reflect.Map:
-		for _, key := range v.MapKeys() {
-			val := v.MapIndex(key)
-			if val.IsNil() {
-				v.SetMapIndex(key, reflect.Zero(val.Type()))
-			} else {
-				newVal := DenullifyValues(val.Interface())
-				v.SetMapIndex(key, reflect.ValueOf(newVal))
-			}
-		}
-	case reflect.Slice:
-		for i := 0; i < v.Len(); i++ {
-			elem := v.Index(i)
-			if !elem.IsNil() {
-				newElem := DenullifyValues(elem.Interface())
-				elem.Set(reflect.ValueOf(newElem))
-			}
+	switch data := data.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}:
+		for k, v := range data {
+			data[k] = DenullifyValues(v)
 		}
-	case reflect.Ptr, reflect.Interface:
-		if v.IsNil() {
-			return nil
+	case []interface{}:
+		for i, v := range data {
+			data[i] = DenullifyValues(v)
 		}
-		newV := DenullifyValues(v.Elem().Interface())
-		return reflect.New(reflect.TypeOf(newV)).Elem().Interface()
 	}
-
 	return data
 }
 
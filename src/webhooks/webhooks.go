@@ -0,0 +1,344 @@
+// Package webhooks pushes the operations of tracked wallets to webhook
+// endpoints. Each poll fetches the operations of every wallet, picks those
+// after the last wallet_tx_lt seen and POSTs each one, signed with HMAC, to
+// every endpoint. Failed deliveries are retried with backoff, then written
+// to a dead-letter log.
+package webhooks
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/itay747/go-stonfi/src/utils"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the
+	// timestamp, a dot and the body, keyed with the endpoint's secret.
+	SignatureHeader = "X-Stonfi-Signature"
+	// TimestampHeader carries the Unix time the request was signed at.
+	TimestampHeader = "X-Stonfi-Timestamp"
+)
+
+// Endpoint is a webhook URL and the secret its payloads are signed with.
+type Endpoint struct {
+	URL    string
+	Secret string
+}
+
+// Event is the payload of a delivery.
+type Event struct {
+	// ID identifies the operation across retries and restarts.
+	ID        string              `json:"id"`
+	Wallet    string              `json:"wallet"`
+	Type      string              `json:"type"`
+	Lt        uint64              `json:"lt"`
+	Operation types.OperationInfo `json:"operation"`
+	// DetectedAt is when the poll found the operation.
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// DeadLetter is a delivery that failed for good.
+type DeadLetter struct {
+	Event    Event     `json:"event"`
+	URL      string    `json:"url"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	At       time.Time `json:"at"`
+}
+
+// Options configures a Service.
+type Options struct {
+	Endpoints []Endpoint
+	// OperationTypes limits the operations delivered, as in "swap" or
+	// "provide_liquidity". Empty delivers all.
+	OperationTypes []string
+	// MaxAttempts is how many times a delivery is tried. Defaults to 5.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled for each one
+	// after. Defaults to 1 second.
+	Backoff time.Duration
+	// HTTPClient defaults to a client with a 10 second timeout.
+	HTTPClient *http.Client
+	// DeadLetter receives failed deliveries as JSON lines, if set.
+	DeadLetter io.Writer
+}
+
+// Service tracks wallets and delivers their new operations.
+type Service struct {
+	client *client.StonfiClient
+	opts   Options
+
+	mu      sync.Mutex
+	wallets map[string]*cursor
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// cursor is where the operations of a wallet are delivered up to.
+type cursor struct {
+	address string
+	lt      uint64
+	// started is false until the first poll of a wallet tracked without a
+	// starting point, which only sets lt.
+	started bool
+}
+
+// New returns a service delivering to opts.Endpoints.
+func New(c *client.StonfiClient, opts Options) *Service {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Service{client: c, opts: opts, wallets: map[string]*cursor{}, now: time.Now, sleep: sleep}
+}
+
+// Track starts tracking wallet. Its operations up to the first poll are
+// not delivered.
+func (s *Service) Track(wallet string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.wallets[utils.AddressKey(wallet)]; !ok {
+		s.wallets[utils.AddressKey(wallet)] = &cursor{address: wallet}
+	}
+}
+
+// Resume tracks wallet from lt, a wallet_tx_lt saved by Cursors: the
+// operations after it are delivered at the next poll.
+func (s *Service) Resume(wallet string, lt uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.wallets[utils.AddressKey(wallet)] = &cursor{address: wallet, lt: lt, started: true}
+}
+
+// Untrack stops tracking wallet.
+func (s *Service) Untrack(wallet string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.wallets, utils.AddressKey(wallet))
+}
+
+// Cursors returns the last wallet_tx_lt delivered for each wallet that has
+// been polled, to Resume from after a restart.
+func (s *Service) Cursors() map[string]uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cursors := map[string]uint64{}
+	for _, c := range s.wallets {
+		if c.started {
+			cursors[c.address] = c.lt
+		}
+	}
+	return cursors
+}
+
+// Poll fetches the operations of the tracked wallets and delivers the new
+// ones, oldest first. It returns how many events were delivered to every
+// endpoint. A wallet whose operations cannot be fetched keeps its cursor,
+// to be tried again at the next poll; its error is returned with the
+// others.
+func (s *Service) Poll(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	cursors := make([]cursor, 0, len(s.wallets))
+	for _, c := range s.wallets {
+		cursors = append(cursors, *c)
+	}
+	s.mu.Unlock()
+	slices.SortFunc(cursors, func(a, b cursor) int { return strings.Compare(a.address, b.address) })
+
+	delivered := 0
+	var errs []error
+	for _, c := range cursors {
+		response, err := s.client.GetWalletOperations(ctx, c.address)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhooks: fetching operations of %s: %w", c.address, err))
+			continue
+		}
+		events, latest := s.newEvents(c, response.Operations)
+		for _, e := range events {
+			ok := true
+			for _, endpoint := range s.opts.Endpoints {
+				if err := s.deliver(ctx, endpoint, e); err != nil {
+					errs = append(errs, err)
+					ok = false
+				}
+			}
+			if ctx.Err() != nil {
+				// Leave the cursor for the next poll to deliver the event
+				// again, and the rest.
+				return delivered, errors.Join(append(errs, ctx.Err())...)
+			}
+			if ok {
+				delivered++
+			}
+			s.advance(c.address, e.Lt)
+		}
+		s.advance(c.address, latest)
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// Run polls until ctx is done, every interval. Each outcome is passed to
+// report, if set.
+func (s *Service) Run(ctx context.Context, interval time.Duration, report func(n int, err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := s.Poll(ctx)
+		if report != nil {
+			report(n, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newEvents returns the operations after c, oldest first, and the latest
+// logical time among all of ops.
+func (s *Service) newEvents(c cursor, ops types.Operations) ([]Event, uint64) {
+	latest := c.lt
+	var events []Event
+	now := s.now().UTC()
+	for _, op := range ops {
+		lt := op.Operation.LogicalTime()
+		latest = max(latest, lt)
+		if !c.started || lt <= c.lt {
+			continue
+		}
+		if len(s.opts.OperationTypes) > 0 && !slices.Contains(s.opts.OperationTypes, op.Operation.OperationType) {
+			continue
+		}
+		events = append(events, Event{
+			ID:         fmt.Sprintf("%s:%d:%s", utils.AddressKey(c.address), lt, op.Operation.PoolTxHash),
+			Wallet:     c.address,
+			Type:       op.Operation.OperationType,
+			Lt:         lt,
+			Operation:  op,
+			DetectedAt: now,
+		})
+	}
+	slices.SortStableFunc(events, func(a, b Event) int { return cmp.Compare(a.Lt, b.Lt) })
+	return events, latest
+}
+
+// advance moves the cursor of wallet to lt, if the wallet is still tracked
+// and lt is later.
+func (s *Service) advance(wallet string, lt uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.wallets[utils.AddressKey(wallet)]; ok {
+		c.lt = max(c.lt, lt)
+		c.started = true
+	}
+}
+
+// deliver posts e to endpoint, retrying network errors, 429s and 5xx
+// responses. A delivery that fails for good is dead-lettered.
+func (s *Service) deliver(ctx context.Context, endpoint Endpoint, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("webhooks: encoding event %s: %w", e.ID, err)
+	}
+	backoff := s.opts.Backoff
+	attempts := 0
+	for {
+		attempts++
+		var retry bool
+		retry, err = s.post(ctx, endpoint, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempts >= s.opts.MaxAttempts || s.sleep(ctx, backoff) != nil {
+			break
+		}
+		backoff *= 2
+	}
+	err = fmt.Errorf("webhooks: delivering %s to %s after %d attempts: %w", e.ID, endpoint.URL, attempts, err)
+	if ctx.Err() != nil {
+		// Interrupted rather than failed: the event is delivered again.
+		return err
+	}
+	if s.opts.DeadLetter != nil {
+		letter, _ := json.Marshal(DeadLetter{Event: e, URL: endpoint.URL, Attempts: attempts, Error: err.Error(), At: s.now().UTC()})
+		if _, werr := s.opts.DeadLetter.Write(append(letter, '\n')); werr != nil {
+			return errors.Join(err, fmt.Errorf("webhooks: writing dead letter: %w", werr))
+		}
+	}
+	return err
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func (s *Service) post(ctx context.Context, endpoint Endpoint, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	if endpoint.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(endpoint.Secret, timestamp, body))
+	}
+	resp, err := s.opts.HTTPClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, fmt.Errorf("answered %s", resp.Status)
+}
+
+// Sign returns the signature header value of a payload.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is that of a payload, for receivers to
+// check deliveries with.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,198 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/stonfitest"
+	"github.com/itay747/go-stonfi/src/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	wallet      = "UQBynBO23ywHy_CgarY9NK9FTz0yDsG82PtcbSTQgGoXwnZF"
+	poolAddress = "EQD8TJ8xEWB1SpnRE4d89YO3jl0W0EiBnNS4IBaHaUmdfizE"
+	secret      = "s3cret"
+)
+
+var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func operation(lt uint64, opType string) types.OperationInfo {
+	return types.OperationInfo{Operation: types.Operation{
+		OperationType:   opType,
+		WalletAddress:   wallet,
+		WalletTxLt:      strconv.FormatUint(lt, 10),
+		PoolAddress:     poolAddress,
+		PoolTxHash:      "hash" + strconv.FormatUint(lt, 10),
+		PoolTxTimestamp: "2024-01-01T00:00:00",
+	}}
+}
+
+// receiver is a webhook endpoint answering with statuses in turn, then 200.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	events   []Event
+	bad      int
+}
+
+func newReceiver(statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if !Verify(secret, req.Header.Get(TimestampHeader), body, req.Header.Get(SignatureHeader)) {
+			r.bad++
+		}
+		if len(r.statuses) > 0 {
+			status := r.statuses[0]
+			r.statuses = r.statuses[1:]
+			w.WriteHeader(status)
+			return
+		}
+		var e Event
+		json.Unmarshal(body, &e)
+		r.events = append(r.events, e)
+	}))
+	return r
+}
+
+func (r *receiver) lts() []uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var lts []uint64
+	for _, e := range r.events {
+		lts = append(lts, e.Lt)
+	}
+	return lts
+}
+
+func newService(s *stonfitest.Server, opts Options) (*Service, *[]time.Duration) {
+	svc := New(s.Client(), opts)
+	svc.now = func() time.Time { return now }
+	var sleeps []time.Duration
+	svc.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return svc, &sleeps
+}
+
+func TestPoll(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Operations: types.Operations{operation(100, "swap")}})
+	defer s.Close()
+	r := newReceiver()
+	defer r.Close()
+	svc, _ := newService(s, Options{Endpoints: []Endpoint{{URL: r.URL, Secret: secret}}, OperationTypes: []string{"swap", "provide_liquidity"}})
+	svc.Track(wallet)
+	ctx := context.Background()
+
+	n, err := svc.Poll(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, n, "operations before tracking started are not new")
+	assert.Equal(t, map[string]uint64{wallet: 100}, svc.Cursors())
+
+	s.AddOperation(operation(300, "provide_liquidity"))
+	s.AddOperation(operation(200, "swap"))
+	s.AddOperation(operation(400, "withdraw_liquidity"))
+	n, err = svc.Poll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []uint64{200, 300}, r.lts(), "oldest first, filtered by type")
+	assert.Zero(t, r.bad, "every payload is signed")
+	assert.Equal(t, map[string]uint64{wallet: 400}, svc.Cursors())
+	if len(r.events) > 0 {
+		e := r.events[0]
+		assert.Equal(t, "swap", e.Type)
+		assert.Equal(t, now, e.DetectedAt)
+		assert.Equal(t, "hash200", e.Operation.Operation.PoolTxHash)
+	}
+
+	n, err = svc.Poll(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestResume(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Operations: types.Operations{operation(100, "swap"), operation(200, "swap")}})
+	defer s.Close()
+	r := newReceiver()
+	defer r.Close()
+	svc, _ := newService(s, Options{Endpoints: []Endpoint{{URL: r.URL, Secret: secret}}})
+	svc.Resume(wallet, 100)
+
+	n, err := svc.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []uint64{200}, r.lts())
+}
+
+func TestRetriesAndDeadLetters(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Operations: types.Operations{operation(100, "swap"), operation(200, "swap")}})
+	defer s.Close()
+	// The first event is retried twice; the second is rejected outright.
+	r := newReceiver(http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK, http.StatusBadRequest)
+	defer r.Close()
+	var deadLetters bytes.Buffer
+	svc, sleeps := newService(s, Options{Endpoints: []Endpoint{{URL: r.URL, Secret: secret}}, MaxAttempts: 3, Backoff: time.Second, DeadLetter: &deadLetters})
+	svc.Resume(wallet, 0)
+
+	n, err := svc.Poll(context.Background())
+	assert.ErrorContains(t, err, "after 1 attempts: answered 400 Bad Request")
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *sleeps, "exponential backoff")
+	assert.Equal(t, map[string]uint64{wallet: 200}, svc.Cursors(), "dead letters do not hold up the cursor")
+
+	var letter DeadLetter
+	assert.NoError(t, json.Unmarshal(deadLetters.Bytes(), &letter))
+	assert.Equal(t, uint64(200), letter.Event.Lt)
+	assert.Equal(t, r.URL, letter.URL)
+	assert.Equal(t, 1, letter.Attempts)
+
+	*sleeps = nil
+	r.mu.Lock()
+	r.statuses = []int{500, 500, 500}
+	r.mu.Unlock()
+	s.AddOperation(operation(300, "swap"))
+	_, err = svc.Poll(context.Background())
+	assert.ErrorContains(t, err, "after 3 attempts")
+	assert.Len(t, *sleeps, 2)
+}
+
+func TestPollFetchError(t *testing.T) {
+	s := stonfitest.NewServerWithFixtures(stonfitest.Fixtures{Operations: types.Operations{operation(100, "swap")}})
+	defer s.Close()
+	r := newReceiver()
+	defer r.Close()
+	svc, _ := newService(s, Options{Endpoints: []Endpoint{{URL: r.URL, Secret: secret}}})
+	svc.Resume(wallet, 50)
+
+	s.FailNext(http.StatusInternalServerError, 10)
+	_, err := svc.Poll(context.Background())
+	assert.ErrorContains(t, err, "fetching operations of "+wallet)
+	assert.Equal(t, map[string]uint64{wallet: 50}, svc.Cursors())
+
+	s.ClearFaults()
+	n, err := svc.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"x"}`)
+	signature := Sign(secret, "1704067200", body)
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, Verify(secret, "1704067200", body, signature))
+	assert.False(t, Verify(secret, "1704067201", body, signature), "the timestamp is signed")
+	assert.False(t, Verify("other", "1704067200", body, signature))
+}
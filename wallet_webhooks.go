@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/itay747/go-stonfi/src/client"
+	"github.com/itay747/go-stonfi/src/webhooks"
+)
+
+func init() {
+	registerCommand(&command{
+		name:    "wallet-webhooks",
+		summary: "Push the new operations of wallets to webhooks as signed JSON",
+		setup: func(fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+			urls := fs.String("url", "", "Comma-separated webhook URLs")
+			secret := fs.String("secret", os.Getenv("STONFI_WEBHOOK_SECRET"), "HMAC secret the payloads are signed with (default $STONFI_WEBHOOK_SECRET)")
+			types := fs.String("types", "", "Comma-separated operation types to deliver, as in swap,provide_liquidity (default all)")
+			interval := fs.Duration("interval", time.Minute, "Time between polls")
+			attempts := fs.Int("attempts", 5, "Delivery attempts before an event is dead-lettered")
+			deadLetter := fs.String("dead-letter", "webhooks-dead-letter.jsonl", "File failed deliveries are appended to")
+			state := fs.String("state", "", "File the last wallet_tx_lt of each wallet is kept in, to resume from")
+			return func(ctx context.Context, args []string) error {
+				if len(args) == 0 || *urls == "" {
+					return fmt.Errorf("usage: %s wallet-webhooks -url <url>[,<url>...] [flags] <wallet-address>...", programName())
+				}
+				letters, err := os.OpenFile(*deadLetter, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+				if err != nil {
+					return err
+				}
+				defer letters.Close()
+
+				opts := webhooks.Options{MaxAttempts: *attempts, DeadLetter: letters}
+				for _, u := range strings.Split(*urls, ",") {
+					opts.Endpoints = append(opts.Endpoints, webhooks.Endpoint{URL: strings.TrimSpace(u), Secret: *secret})
+				}
+				if *types != "" {
+					opts.OperationTypes = strings.Split(*types, ",")
+				}
+				svc := webhooks.New(client.NewStonfiClient(), opts)
+
+				cursors := map[string]uint64{}
+				if *state != "" {
+					data, err := os.ReadFile(*state)
+					if err != nil && !errors.Is(err, os.ErrNotExist) {
+						return err
+					}
+					if len(data) > 0 {
+						if err := json.Unmarshal(data, &cursors); err != nil {
+							return fmt.Errorf("reading %s: %w", *state, err)
+						}
+					}
+				}
+				for _, wallet := range args {
+					if lt, ok := cursors[wallet]; ok {
+						svc.Resume(wallet, lt)
+					} else {
+						svc.Track(wallet)
+					}
+				}
+
+				infoMessage(fmt.Sprintf("Pushing operations of %d wallets to %d webhooks every %s", len(args), len(opts.Endpoints), *interval))
+				svc.Run(ctx, *interval, func(n int, err error) {
+					if err != nil {
+						errorMessage(fmt.Sprintf("Error: %v", err))
+					}
+					if n > 0 {
+						successMessage(fmt.Sprintf("Delivered %d operations", n))
+					}
+					if *state != "" {
+						data, _ := json.MarshalIndent(svc.Cursors(), "", "  ")
+						if err := os.WriteFile(*state, data, 0o644); err != nil {
+							errorMessage(fmt.Sprintf("Error: %v", err))
+						}
+					}
+				})
+				return nil
+			}
+		},
+	})
+}